@@ -182,6 +182,9 @@ func appList(w http.ResponseWriter, r *http.Request, t auth.Token) error {
 	if teamOwner := r.URL.Query().Get("teamOwner"); teamOwner != "" {
 		filter.TeamOwner = teamOwner
 	}
+	if team := r.URL.Query().Get("team"); team != "" {
+		filter.Team = team
+	}
 	if owner := r.URL.Query().Get("owner"); owner != "" {
 		filter.UserOwner = owner
 	}
@@ -198,12 +201,24 @@ func appList(w http.ResponseWriter, r *http.Request, t auth.Token) error {
 	if tags, ok := r.URL.Query()["tag"]; ok {
 		filter.Tags = tags
 	}
+	filter.Sort = r.URL.Query().Get("sort")
+	limit, skip, err := paginationParams(r)
+	if err != nil {
+		return err
+	}
 	contexts := permission.ContextsForPermission(t, permission.PermAppRead)
 	if len(contexts) == 0 {
 		w.WriteHeader(http.StatusNoContent)
 		return nil
 	}
-	apps, err := app.List(appFilterByContext(contexts, filter))
+	var apps []app.App
+	if limit > 0 {
+		var total int
+		apps, total, err = app.ListPaginated(appFilterByContext(contexts, filter), skip, limit)
+		w.Header().Set(totalCountHeader, strconv.Itoa(total))
+	} else {
+		apps, err = app.List(appFilterByContext(contexts, filter))
+	}
 	if err != nil {
 		return err
 	}
@@ -250,14 +265,16 @@ func appInfo(w http.ResponseWriter, r *http.Request, t auth.Token) error {
 }
 
 type inputApp struct {
-	TeamOwner   string
-	Platform    string
-	Plan        string
-	Name        string
-	Description string
-	Pool        string
-	Router      string
-	RouterOpts  map[string]string
+	TeamOwner        string
+	Platform         string
+	Plan             string
+	Name             string
+	Description      string
+	DocumentationURL string
+	Annotations      map[string]string
+	Pool             string
+	Router           string
+	RouterOpts       map[string]string
 }
 
 // title: app create
@@ -282,15 +299,17 @@ func createApp(w http.ResponseWriter, r *http.Request, t auth.Token) (err error)
 	dec.IgnoreUnknownKeys(true)
 	dec.DecodeValues(&ia, r.Form)
 	a := app.App{
-		TeamOwner:   ia.TeamOwner,
-		Platform:    ia.Platform,
-		Plan:        appTypes.Plan{Name: ia.Plan},
-		Name:        ia.Name,
-		Description: ia.Description,
-		Pool:        ia.Pool,
-		RouterOpts:  ia.RouterOpts,
-		Router:      ia.Router,
-		Tags:        r.Form["tag"],
+		TeamOwner:        ia.TeamOwner,
+		Platform:         ia.Platform,
+		Plan:             appTypes.Plan{Name: ia.Plan},
+		Name:             ia.Name,
+		Description:      ia.Description,
+		DocumentationURL: ia.DocumentationURL,
+		Annotations:      ia.Annotations,
+		Pool:             ia.Pool,
+		RouterOpts:       ia.RouterOpts,
+		Router:           ia.Router,
+		Tags:             r.Form["tag"],
 	}
 	if a.TeamOwner == "" {
 		a.TeamOwner, err = permission.TeamForPermission(t, permission.PermAppCreate)
@@ -392,6 +411,85 @@ func createApp(w http.ResponseWriter, r *http.Request, t auth.Token) (err error)
 	return nil
 }
 
+// title: app clone
+// path: /apps/{app}/clone
+// method: POST
+// consume: application/x-www-form-urlencoded
+// produce: application/x-json-stream
+// responses:
+//   200: App cloned
+//   400: Invalid data
+//   401: Unauthorized
+//   403: Quota exceeded
+//   404: App not found
+//   409: App already exists
+func cloneApp(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	name := r.FormValue("name")
+	if name == "" {
+		return &errors.HTTP{Code: http.StatusBadRequest, Message: "you must provide the name of the new app"}
+	}
+	a, err := getAppFromContext(r.URL.Query().Get(":app"), r)
+	if err != nil {
+		return err
+	}
+	canReadEnv := permission.Check(t, permission.PermAppReadEnv,
+		contextsForApp(&a)...,
+	)
+	canCreate := permission.Check(t, permission.PermAppCreate,
+		permission.Context(permission.CtxTeam, a.TeamOwner),
+	)
+	if !canReadEnv || !canCreate {
+		return permission.ErrUnauthorized
+	}
+	instances, err := service.GetServiceInstancesBoundToApp(a.Name)
+	if err != nil {
+		return err
+	}
+	for _, si := range instances {
+		allowed := permission.Check(t, permission.PermServiceInstanceUpdateBind,
+			append(permission.Contexts(permission.CtxTeam, si.Teams),
+				permission.Context(permission.CtxServiceInstance, si.Name),
+			)...,
+		)
+		if !allowed {
+			return permission.ErrUnauthorized
+		}
+	}
+	u, err := t.User()
+	if err != nil {
+		return err
+	}
+	evt, err := event.New(&event.Opts{
+		Target:     appTarget(name),
+		Kind:       permission.PermAppCreate,
+		Owner:      t,
+		CustomData: event.FormToCustomData(r.Form),
+		Allowed:    event.Allowed(permission.PermAppReadEvents, contextsForApp(&a)...),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(err) }()
+	w.Header().Set("Content-Type", "application/x-json-stream")
+	keepAliveWriter := tsuruIo.NewKeepAliveWriter(w, 30*time.Second, "")
+	defer keepAliveWriter.Stop()
+	writer := &tsuruIo.SimpleJsonMessageEncoderWriter{Encoder: json.NewEncoder(keepAliveWriter)}
+	_, err = a.Clone(name, u, writer)
+	if err != nil {
+		if e, ok := err.(*app.AppCreationError); ok {
+			if e.Err == app.ErrAppAlreadyExists {
+				return &errors.HTTP{Code: http.StatusConflict, Message: e.Error()}
+			}
+			if _, ok := e.Err.(*quota.QuotaExceededError); ok {
+				return &errors.HTTP{Code: http.StatusForbidden, Message: "Quota exceeded"}
+			}
+		}
+		return err
+	}
+	fmt.Fprintf(writer, "App %q successfully cloned to %q.\n", a.Name, name)
+	return nil
+}
+
 // title: app update
 // path: /apps/{name}
 // method: PUT
@@ -414,15 +512,17 @@ func updateApp(w http.ResponseWriter, r *http.Request, t auth.Token) (err error)
 	dec.DecodeValues(&ia, r.Form)
 	imageReset, _ := strconv.ParseBool(r.FormValue("imageReset"))
 	updateData := app.App{
-		TeamOwner:      ia.TeamOwner,
-		Plan:           appTypes.Plan{Name: ia.Plan},
-		Pool:           ia.Pool,
-		Description:    ia.Description,
-		Router:         ia.Router,
-		Tags:           r.Form["tag"],
-		Platform:       r.FormValue("platform"),
-		UpdatePlatform: imageReset,
-		RouterOpts:     ia.RouterOpts,
+		TeamOwner:        ia.TeamOwner,
+		Plan:             appTypes.Plan{Name: ia.Plan},
+		Pool:             ia.Pool,
+		Description:      ia.Description,
+		DocumentationURL: ia.DocumentationURL,
+		Annotations:      ia.Annotations,
+		Router:           ia.Router,
+		Tags:             r.Form["tag"],
+		Platform:         r.FormValue("platform"),
+		UpdatePlatform:   imageReset,
+		RouterOpts:       ia.RouterOpts,
 	}
 	appName := r.URL.Query().Get(":appname")
 	a, err := getAppFromContext(appName, r)
@@ -433,9 +533,12 @@ func updateApp(w http.ResponseWriter, r *http.Request, t auth.Token) (err error)
 	if updateData.Router != "" || len(updateData.RouterOpts) > 0 {
 		return &errors.HTTP{Code: http.StatusBadRequest, Message: "updating router was deprecated, please add the wanted router and remove the old one"}
 	}
-	if updateData.Description != "" {
+	if updateData.Description != "" || updateData.DocumentationURL != "" {
 		wantedPerms = append(wantedPerms, permission.PermAppUpdateDescription)
 	}
+	if len(updateData.Annotations) > 0 {
+		wantedPerms = append(wantedPerms, permission.PermAppUpdateAnnotations)
+	}
 	if len(updateData.Tags) > 0 {
 		wantedPerms = append(wantedPerms, permission.PermAppUpdateTags)
 	}
@@ -552,6 +655,9 @@ func addUnits(w http.ResponseWriter, r *http.Request, t auth.Token) (err error)
 	keepAliveWriter := tsuruIo.NewKeepAliveWriter(w, 30*time.Second, "")
 	defer keepAliveWriter.Stop()
 	writer := &tsuruIo.SimpleJsonMessageEncoderWriter{Encoder: json.NewEncoder(keepAliveWriter)}
+	if node := r.FormValue("node"); node != "" {
+		return a.AddUnitsOnNode(n, processName, node, writer)
+	}
 	return a.AddUnits(n, processName, writer)
 }
 
@@ -596,6 +702,9 @@ func removeUnits(w http.ResponseWriter, r *http.Request, t auth.Token) (err erro
 	keepAliveWriter := tsuruIo.NewKeepAliveWriter(w, 30*time.Second, "")
 	defer keepAliveWriter.Stop()
 	writer := &tsuruIo.SimpleJsonMessageEncoderWriter{Encoder: json.NewEncoder(keepAliveWriter)}
+	if node := r.FormValue("node"); node != "" {
+		return a.RemoveUnitsOnNode(n, processName, node, writer)
+	}
 	return a.RemoveUnits(n, processName, writer)
 }
 
@@ -870,6 +979,21 @@ func writeEnvVars(w http.ResponseWriter, a *app.App, variables ...string) error
 	return json.NewEncoder(w).Encode(result)
 }
 
+// writeBuildEnvVars writes the environment variables sent to units building
+// images for the app, build variables override the ones used at runtime.
+func writeBuildEnvVars(w http.ResponseWriter, a *app.App) error {
+	envs := a.Envs()
+	for name, env := range a.BuildEnvs() {
+		envs[name] = env
+	}
+	result := make([]bind.EnvVar, 0, len(envs))
+	for _, v := range envs {
+		result = append(result, v)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(result)
+}
+
 // title: set envs
 // path: /apps/{app}/env
 // method: POST
@@ -994,6 +1118,263 @@ func unsetEnv(w http.ResponseWriter, r *http.Request, t auth.Token) (err error)
 	})
 }
 
+// title: import envs
+// path: /apps/{app}/env/import
+// method: POST
+// consume: application/json, text/plain
+// produce: application/x-json-stream
+// responses:
+//   200: Envs updated
+//   400: Invalid data
+//   401: Unauthorized
+//   404: App not found
+func importEnv(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	format := app.EnvFormatDotenv
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		format = app.EnvFormatJSON
+	}
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	variables, err := app.ParseEnvs(data, format)
+	if err != nil {
+		return err
+	}
+	if len(variables) == 0 {
+		msg := "You must provide the list of environment variables"
+		return &errors.HTTP{Code: http.StatusBadRequest, Message: msg}
+	}
+	query := r.URL.Query()
+	noRestart, _ := strconv.ParseBool(query.Get("noRestart"))
+	private, _ := strconv.ParseBool(query.Get("private"))
+	appName := query.Get(":app")
+	a, err := getAppFromContext(appName, r)
+	if err != nil {
+		return err
+	}
+	allowed := permission.Check(t, permission.PermAppUpdateEnvSet,
+		contextsForApp(&a)...,
+	)
+	if !allowed {
+		return permission.ErrUnauthorized
+	}
+	customData := url.Values{":app": {appName}}
+	for i := range variables {
+		if private {
+			variables[i].Public = false
+		}
+		value := variables[i].Value
+		if !variables[i].Public {
+			value = "*****"
+		}
+		customData.Set(fmt.Sprintf("Envs.%d.Name", i), variables[i].Name)
+		customData.Set(fmt.Sprintf("Envs.%d.Value", i), value)
+	}
+	customData.Set("NoRestart", strconv.FormatBool(noRestart))
+	customData.Set("Private", strconv.FormatBool(private))
+	evt, err := event.New(&event.Opts{
+		Target:     appTarget(appName),
+		Kind:       permission.PermAppUpdateEnvSet,
+		Owner:      t,
+		CustomData: event.FormToCustomData(customData),
+		Allowed:    event.Allowed(permission.PermAppReadEvents, contextsForApp(&a)...),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(err) }()
+	w.Header().Set("Content-Type", "application/x-json-stream")
+	keepAliveWriter := tsuruIo.NewKeepAliveWriter(w, 30*time.Second, "")
+	defer keepAliveWriter.Stop()
+	writer := &tsuruIo.SimpleJsonMessageEncoderWriter{Encoder: json.NewEncoder(keepAliveWriter)}
+	return a.SetEnvs(bind.SetEnvArgs{
+		Envs:          variables,
+		ShouldRestart: !noRestart,
+		Writer:        writer,
+	})
+}
+
+// title: export envs
+// path: /apps/{app}/env/export
+// method: GET
+// produce: application/json, text/plain
+// responses:
+//   200: OK
+//   400: Invalid format
+//   401: Unauthorized
+//   404: App not found
+func exportEnv(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	appName := r.URL.Query().Get(":app")
+	a, err := getAppFromContext(appName, r)
+	if err != nil {
+		return err
+	}
+	allowed := permission.Check(t, permission.PermAppReadEnv,
+		contextsForApp(&a)...,
+	)
+	if !allowed {
+		return permission.ErrUnauthorized
+	}
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = app.EnvFormatJSON
+	}
+	data, err := a.ExportEnvs(format)
+	if err != nil {
+		return err
+	}
+	if format == app.EnvFormatJSON {
+		w.Header().Set("Content-Type", "application/json")
+	} else {
+		w.Header().Set("Content-Type", "text/plain")
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// title: get build envs
+// path: /apps/{app}/build-env
+// method: GET
+// produce: application/json
+// responses:
+//   200: OK
+//   401: Unauthorized
+//   404: App not found
+func getBuildEnv(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	appName := r.URL.Query().Get(":app")
+	a, err := getAppFromContext(appName, r)
+	if err != nil {
+		return err
+	}
+	allowed := permission.Check(t, permission.PermAppReadBuildEnv,
+		contextsForApp(&a)...,
+	)
+	if !allowed {
+		return permission.ErrUnauthorized
+	}
+	result := []bind.EnvVar{}
+	for _, v := range a.BuildEnvs() {
+		result = append(result, v)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(result)
+}
+
+// title: set build envs
+// path: /apps/{app}/build-env
+// method: POST
+// consume: application/x-www-form-urlencoded
+// produce: application/x-json-stream
+// responses:
+//   200: Envs updated
+//   400: Invalid data
+//   401: Unauthorized
+//   404: App not found
+func setBuildEnv(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	err = r.ParseForm()
+	if err != nil {
+		return &errors.HTTP{Code: http.StatusBadRequest, Message: err.Error()}
+	}
+	var e apiTypes.Envs
+	dec := form.NewDecoder(nil)
+	dec.IgnoreUnknownKeys(true)
+	err = dec.DecodeValues(&e, r.Form)
+	if err != nil {
+		return &errors.HTTP{Code: http.StatusBadRequest, Message: err.Error()}
+	}
+	if len(e.Envs) == 0 {
+		msg := "You must provide the list of environment variables"
+		return &errors.HTTP{Code: http.StatusBadRequest, Message: msg}
+	}
+	appName := r.URL.Query().Get(":app")
+	a, err := getAppFromContext(appName, r)
+	if err != nil {
+		return err
+	}
+	allowed := permission.Check(t, permission.PermAppUpdateBuildEnvSet,
+		contextsForApp(&a)...,
+	)
+	if !allowed {
+		return permission.ErrUnauthorized
+	}
+	if e.Private {
+		for i := 0; i < len(e.Envs); i++ {
+			r.Form.Set(fmt.Sprintf("Envs.%d.Value", i), "*****")
+		}
+	}
+	evt, err := event.New(&event.Opts{
+		Target:     appTarget(appName),
+		Kind:       permission.PermAppUpdateBuildEnvSet,
+		Owner:      t,
+		CustomData: event.FormToCustomData(r.Form),
+		Allowed:    event.Allowed(permission.PermAppReadEvents, contextsForApp(&a)...),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(err) }()
+	variables := []bind.EnvVar{}
+	for _, v := range e.Envs {
+		variables = append(variables, bind.EnvVar{Name: v.Name, Value: v.Value, Public: !e.Private})
+	}
+	w.Header().Set("Content-Type", "application/x-json-stream")
+	keepAliveWriter := tsuruIo.NewKeepAliveWriter(w, 30*time.Second, "")
+	defer keepAliveWriter.Stop()
+	writer := &tsuruIo.SimpleJsonMessageEncoderWriter{Encoder: json.NewEncoder(keepAliveWriter)}
+	return a.SetBuildEnvs(bind.SetEnvArgs{
+		Envs:   variables,
+		Writer: writer,
+	})
+}
+
+// title: unset build envs
+// path: /apps/{app}/build-env
+// method: DELETE
+// produce: application/x-json-stream
+// responses:
+//   200: Envs removed
+//   400: Invalid data
+//   401: Unauthorized
+//   404: App not found
+func unsetBuildEnv(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	msg := "You must provide the list of environment variables."
+	if r.FormValue("env") == "" {
+		return &errors.HTTP{Code: http.StatusBadRequest, Message: msg}
+	}
+	variables := r.Form["env"]
+	appName := r.URL.Query().Get(":app")
+	a, err := getAppFromContext(appName, r)
+	if err != nil {
+		return err
+	}
+	allowed := permission.Check(t, permission.PermAppUpdateBuildEnvUnset,
+		contextsForApp(&a)...,
+	)
+	if !allowed {
+		return permission.ErrUnauthorized
+	}
+	evt, err := event.New(&event.Opts{
+		Target:     appTarget(appName),
+		Kind:       permission.PermAppUpdateBuildEnvUnset,
+		Owner:      t,
+		CustomData: event.FormToCustomData(r.Form),
+		Allowed:    event.Allowed(permission.PermAppReadEvents, contextsForApp(&a)...),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(err) }()
+	w.Header().Set("Content-Type", "application/x-json-stream")
+	keepAliveWriter := tsuruIo.NewKeepAliveWriter(w, 30*time.Second, "")
+	defer keepAliveWriter.Stop()
+	writer := &tsuruIo.SimpleJsonMessageEncoderWriter{Encoder: json.NewEncoder(keepAliveWriter)}
+	return a.UnsetBuildEnvs(bind.UnsetEnvArgs{
+		VariableNames: variables,
+		Writer:        writer,
+	})
+}
+
 // title: set cname
 // path: /apps/{app}/cname
 // method: POST
@@ -1111,6 +1492,14 @@ func appLog(w http.ResponseWriter, r *http.Request, t auth.Token) error {
 	} else {
 		return &errors.HTTP{Code: http.StatusBadRequest, Message: `Parameter "lines" is mandatory.`}
 	}
+	var skip int
+	if s := r.URL.Query().Get("skip"); s != "" {
+		skip, err = strconv.Atoi(s)
+		if err != nil || skip < 0 {
+			msg := `Parameter "skip" must be a non negative integer.`
+			return &errors.HTTP{Code: http.StatusBadRequest, Message: msg}
+		}
+	}
 	w.Header().Set("Content-Type", "application/x-json-stream")
 	source := r.URL.Query().Get("source")
 	unit := r.URL.Query().Get("unit")
@@ -1127,7 +1516,15 @@ func appLog(w http.ResponseWriter, r *http.Request, t auth.Token) error {
 	if !allowed {
 		return permission.ErrUnauthorized
 	}
-	logs, err := a.LastLogs(lines, filterLog)
+	if r.URL.Query().Get("skip") != "" {
+		var total int
+		total, err = a.CountLogs(filterLog)
+		if err != nil {
+			return err
+		}
+		w.Header().Set(totalCountHeader, strconv.Itoa(total))
+	}
+	logs, err := a.PreviousLogs(lines, skip, filterLog)
 	if err != nil {
 		return err
 	}
@@ -1325,10 +1722,15 @@ func unbindServiceInstance(w http.ResponseWriter, r *http.Request, t auth.Token)
 // produce: application/x-json-stream
 // responses:
 //   200: Ok
+//   400: Invalid data
 //   401: Unauthorized
 //   404: App not found
 func restart(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
 	process := r.FormValue("process")
+	units := r.Form["unit"]
+	if process != "" && len(units) > 0 {
+		return &errors.HTTP{Code: http.StatusBadRequest, Message: "process and unit cannot be used together"}
+	}
 	appName := r.URL.Query().Get(":app")
 	a, err := getAppFromContext(appName, r)
 	if err != nil {
@@ -1355,9 +1757,200 @@ func restart(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
 	keepAliveWriter := tsuruIo.NewKeepAliveWriter(w, 30*time.Second, "")
 	defer keepAliveWriter.Stop()
 	writer := &tsuruIo.SimpleJsonMessageEncoderWriter{Encoder: json.NewEncoder(keepAliveWriter)}
+	if len(units) > 0 {
+		return a.RestartUnits(units, writer)
+	}
 	return a.Restart(process, writer)
 }
 
+// title: app restart schedule
+// path: /apps/{app}/restart-schedule
+// method: PUT
+// consume: application/x-www-form-urlencoded
+// responses:
+//   200: Ok
+//   400: Invalid schedule
+//   401: Unauthorized
+//   404: App not found
+func setRestartSchedule(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	schedule := r.FormValue("schedule")
+	appName := r.URL.Query().Get(":app")
+	a, err := getAppFromContext(appName, r)
+	if err != nil {
+		return err
+	}
+	allowed := permission.Check(t, permission.PermAppUpdateRestartSchedule,
+		contextsForApp(&a)...,
+	)
+	if !allowed {
+		return permission.ErrUnauthorized
+	}
+	evt, err := event.New(&event.Opts{
+		Target:     appTarget(appName),
+		Kind:       permission.PermAppUpdateRestartSchedule,
+		Owner:      t,
+		CustomData: event.FormToCustomData(r.Form),
+		Allowed:    event.Allowed(permission.PermAppReadEvents, contextsForApp(&a)...),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(err) }()
+	return a.SetRestartSchedule(schedule)
+}
+
+// title: add app scale schedule
+// path: /apps/{app}/scale-schedules
+// method: POST
+// consume: application/x-www-form-urlencoded
+// responses:
+//   200: Ok
+//   400: Invalid data
+//   401: Unauthorized
+//   404: App not found
+func addScaleSchedule(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	var sched app.ScaleSchedule
+	err = r.ParseForm()
+	if err != nil {
+		return &errors.HTTP{Code: http.StatusBadRequest, Message: err.Error()}
+	}
+	dec := form.NewDecoder(nil)
+	dec.IgnoreCase(true)
+	dec.IgnoreUnknownKeys(true)
+	err = dec.DecodeValues(&sched, r.Form)
+	if err != nil {
+		return &errors.HTTP{Code: http.StatusBadRequest, Message: err.Error()}
+	}
+	appName := r.URL.Query().Get(":app")
+	a, err := getAppFromContext(appName, r)
+	if err != nil {
+		return err
+	}
+	allowed := permission.Check(t, permission.PermAppUpdateScaleSchedule,
+		contextsForApp(&a)...,
+	)
+	if !allowed {
+		return permission.ErrUnauthorized
+	}
+	evt, err := event.New(&event.Opts{
+		Target:     appTarget(appName),
+		Kind:       permission.PermAppUpdateScaleSchedule,
+		Owner:      t,
+		CustomData: event.FormToCustomData(r.Form),
+		Allowed:    event.Allowed(permission.PermAppReadEvents, contextsForApp(&a)...),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(err) }()
+	return a.AddScaleSchedule(sched)
+}
+
+// title: remove app scale schedule
+// path: /apps/{app}/scale-schedules/{name}
+// method: DELETE
+// responses:
+//   200: Ok
+//   401: Unauthorized
+//   404: App or schedule not found
+func removeScaleSchedule(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	err = r.ParseForm()
+	if err != nil {
+		return &errors.HTTP{Code: http.StatusBadRequest, Message: err.Error()}
+	}
+	appName := r.URL.Query().Get(":app")
+	name := r.URL.Query().Get(":name")
+	a, err := getAppFromContext(appName, r)
+	if err != nil {
+		return err
+	}
+	allowed := permission.Check(t, permission.PermAppUpdateScaleSchedule,
+		contextsForApp(&a)...,
+	)
+	if !allowed {
+		return permission.ErrUnauthorized
+	}
+	evt, err := event.New(&event.Opts{
+		Target:     appTarget(appName),
+		Kind:       permission.PermAppUpdateScaleSchedule,
+		Owner:      t,
+		CustomData: event.FormToCustomData(r.Form),
+		Allowed:    event.Allowed(permission.PermAppReadEvents, contextsForApp(&a)...),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(err) }()
+	err = a.RemoveScaleSchedule(name)
+	if err == app.ErrScaleScheduleNotFound {
+		return &errors.HTTP{Code: http.StatusNotFound, Message: err.Error()}
+	}
+	return err
+}
+
+// title: app maintenance enable
+// path: /apps/{app}/maintenance
+// method: POST
+// produce: application/x-json-stream
+// responses:
+//   200: Ok
+//   400: Maintenance not configured
+//   401: Unauthorized
+//   404: App not found
+func enableMaintenance(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	return setMaintenance(w, r, t, true)
+}
+
+// title: app maintenance disable
+// path: /apps/{app}/maintenance
+// method: DELETE
+// produce: application/x-json-stream
+// responses:
+//   200: Ok
+//   401: Unauthorized
+//   404: App not found
+func disableMaintenance(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	return setMaintenance(w, r, t, false)
+}
+
+func setMaintenance(w http.ResponseWriter, r *http.Request, t auth.Token, enabled bool) (err error) {
+	appName := r.URL.Query().Get(":app")
+	a, err := getAppFromContext(appName, r)
+	if err != nil {
+		return err
+	}
+	allowed := permission.Check(t, permission.PermAppUpdateMaintenance,
+		contextsForApp(&a)...,
+	)
+	if !allowed {
+		return permission.ErrUnauthorized
+	}
+	evt, err := event.New(&event.Opts{
+		Target:     appTarget(appName),
+		Kind:       permission.PermAppUpdateMaintenance,
+		Owner:      t,
+		CustomData: map[string]bool{"enabled": enabled},
+		Allowed:    event.Allowed(permission.PermAppReadEvents, contextsForApp(&a)...),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(err) }()
+	w.Header().Set("Content-Type", "application/x-json-stream")
+	keepAliveWriter := tsuruIo.NewKeepAliveWriter(w, 30*time.Second, "")
+	defer keepAliveWriter.Stop()
+	writer := &tsuruIo.SimpleJsonMessageEncoderWriter{Encoder: json.NewEncoder(keepAliveWriter)}
+	evt.SetLogWriter(writer)
+	if enabled {
+		err = a.EnableMaintenance(evt)
+		if err == app.ErrMaintenanceNotConfigured {
+			return &errors.HTTP{Code: http.StatusBadRequest, Message: err.Error()}
+		}
+		return err
+	}
+	return a.DisableMaintenance(evt)
+}
+
 // title: app sleep
 // path: /apps/{app}/sleep
 // method: POST
@@ -1717,6 +2310,13 @@ func registerUnit(w http.ResponseWriter, r *http.Request, t auth.Token) error {
 		}
 		return err
 	}
+	building, err := a.IsBuildUnit(hostname)
+	if err != nil {
+		return err
+	}
+	if building {
+		return writeBuildEnvVars(w, a)
+	}
 	return writeEnvVars(w, a)
 }
 
@@ -1747,6 +2347,38 @@ func appMetricEnvs(w http.ResponseWriter, r *http.Request, t auth.Token) error {
 	return json.NewEncoder(w).Encode(metricMap)
 }
 
+// title: app units metrics
+// path: /apps/{app}/metrics
+// method: GET
+// produce: application/json
+// responses:
+//   200: Ok
+//   204: No content
+//   401: Unauthorized
+//   404: App not found
+func appUnitsMetrics(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	a, err := getAppFromContext(r.URL.Query().Get(":app"), r)
+	if err != nil {
+		return err
+	}
+	allowed := permission.Check(t, permission.PermAppReadMetric,
+		contextsForApp(&a)...,
+	)
+	if !allowed {
+		return permission.ErrUnauthorized
+	}
+	metrics, err := a.UnitsMetrics()
+	if err != nil {
+		return err
+	}
+	if len(metrics) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(metrics)
+}
+
 // title: rebuild routes
 // path: /apps/{app}/routes
 // method: POST
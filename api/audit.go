@@ -0,0 +1,77 @@
+// Copyright 2018 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/tsuru/tsuru/audit"
+	"github.com/tsuru/tsuru/auth"
+	"github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/permission"
+)
+
+// title: audit list
+// path: /audit
+// method: GET
+// produce: application/json
+// responses:
+//   200: OK
+//   204: No content
+//   400: Invalid data
+//   401: Unauthorized
+func auditList(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	if !permission.Check(t, permission.PermAuditRead) {
+		return permission.ErrUnauthorized
+	}
+	limit, skip, err := paginationParams(r)
+	if err != nil {
+		return err
+	}
+	filter := &audit.Filter{
+		User:  r.URL.Query().Get("user"),
+		App:   r.URL.Query().Get("app"),
+		Skip:  skip,
+		Limit: limit,
+	}
+	filter.Since, err = parseAuditTime(r, "since")
+	if err != nil {
+		return err
+	}
+	filter.Until, err = parseAuditTime(r, "until")
+	if err != nil {
+		return err
+	}
+	entries, total, err := audit.List(filter)
+	if err != nil {
+		return err
+	}
+	w.Header().Set(totalCountHeader, strconv.Itoa(total))
+	if len(entries) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(entries)
+}
+
+func parseAuditTime(r *http.Request, param string) (time.Time, error) {
+	value := r.URL.Query().Get(param)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, &errors.HTTP{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("Parameter %q must be a RFC 3339 timestamp.", param),
+		}
+	}
+	return parsed, nil
+}
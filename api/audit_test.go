@@ -0,0 +1,146 @@
+// Copyright 2018 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/tsuru/tsuru/app"
+	"github.com/tsuru/tsuru/audit"
+	"github.com/tsuru/tsuru/permission"
+	"gopkg.in/check.v1"
+)
+
+func (s *S) TestAuditMiddlewareRecordsMutatingRequests(c *check.C) {
+	body := strings.NewReader("name=hook1&team=" + s.team.Name + "&url=example.com")
+	request, err := http.NewRequest("POST", "/events/webhooks", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+	request, err = http.NewRequest("GET", "/events/webhooks", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder = httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusNoContent)
+	request, err = http.NewRequest("POST", "/events/webhooks", nil)
+	c.Assert(err, check.IsNil)
+	recorder = httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusUnauthorized)
+	entries, total, err := audit.List(nil)
+	c.Assert(err, check.IsNil)
+	c.Assert(total, check.Equals, 1)
+	c.Assert(entries[0].User, check.Equals, s.token.GetUserName())
+	c.Assert(entries[0].Method, check.Equals, "POST")
+	c.Assert(entries[0].Path, check.Equals, "/events/webhooks")
+	c.Assert(entries[0].StatusCode, check.Equals, http.StatusBadRequest)
+	c.Assert(entries[0].Error, check.Equals, `invalid webhook url "example.com"`)
+}
+
+func (s *S) TestAuditMiddlewareRecordsApp(c *check.C) {
+	a := app.App{Name: "myapp", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(&a, s.user)
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest("POST", "/apps/myapp/restart", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	entries, _, err := audit.List(&audit.Filter{App: "myapp"})
+	c.Assert(err, check.IsNil)
+	c.Assert(entries, check.HasLen, 1)
+	c.Assert(entries[0].User, check.Equals, s.token.GetUserName())
+	c.Assert(entries[0].Path, check.Equals, "/apps/myapp/restart")
+	c.Assert(entries[0].StatusCode, check.Equals, http.StatusOK)
+}
+
+func (s *S) TestAuditMiddlewareRecordsAppOnDeployRoutes(c *check.C) {
+	a := app.App{Name: "myapp", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(&a, s.user)
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest("POST", "/apps/myapp/deploy/rollback", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+	entries, _, err := audit.List(&audit.Filter{App: "myapp"})
+	c.Assert(err, check.IsNil)
+	c.Assert(entries, check.HasLen, 1)
+	c.Assert(entries[0].App, check.Equals, "myapp")
+	c.Assert(entries[0].Path, check.Equals, "/apps/myapp/deploy/rollback")
+	c.Assert(entries[0].StatusCode, check.Equals, http.StatusBadRequest)
+	c.Assert(entries[0].Error, check.Equals, "you cannot rollback without an image name")
+}
+
+func (s *S) TestAuditList(c *check.C) {
+	base := time.Date(2018, 5, 10, 12, 0, 0, 0, time.UTC)
+	for i, user := range []string{"a@tsuru.io", "b@tsuru.io", "a@tsuru.io"} {
+		err := audit.Add(&audit.Entry{
+			Time:       base.Add(time.Duration(i) * time.Hour),
+			User:       user,
+			Method:     "POST",
+			Path:       "/apps/myapp/restart",
+			App:        "myapp",
+			StatusCode: http.StatusOK,
+		})
+		c.Assert(err, check.IsNil)
+	}
+	request, err := http.NewRequest("GET", "/audit?user=a@tsuru.io&since=2018-05-10T12:30:00Z&app=myapp", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(recorder.Header().Get("Content-Type"), check.Equals, "application/json")
+	c.Assert(recorder.Header().Get("X-Total-Count"), check.Equals, "1")
+	var entries []audit.Entry
+	err = json.NewDecoder(recorder.Body).Decode(&entries)
+	c.Assert(err, check.IsNil)
+	c.Assert(entries, check.HasLen, 1)
+	c.Assert(entries[0].User, check.Equals, "a@tsuru.io")
+	c.Assert(entries[0].Time.Equal(base.Add(2*time.Hour)), check.Equals, true)
+}
+
+func (s *S) TestAuditListEmpty(c *check.C) {
+	request, err := http.NewRequest("GET", "/audit?user=nobody@tsuru.io", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusNoContent)
+}
+
+func (s *S) TestAuditListInvalidTime(c *check.C) {
+	request, err := http.NewRequest("GET", "/audit?since=yesterday", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+	c.Assert(recorder.Body.String(), check.Equals, "Parameter \"since\" must be a RFC 3339 timestamp.\n")
+}
+
+func (s *S) TestAuditListUnauthorized(c *check.C) {
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermAppRead,
+		Context: permission.Context(permission.CtxGlobal, ""),
+	})
+	request, err := http.NewRequest("GET", "/audit", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+}
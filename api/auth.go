@@ -729,9 +729,11 @@ func authScheme(w http.ResponseWriter, r *http.Request) error {
 // title: regenerate token
 // path: /users/api-key
 // method: POST
+// consume: application/x-www-form-urlencoded
 // produce: application/json
 // responses:
 //   200: OK
+//   400: Invalid scope
 //   401: Unauthorized
 //   404: User not found
 func regenerateAPIToken(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
@@ -761,7 +763,20 @@ func regenerateAPIToken(w http.ResponseWriter, r *http.Request, t auth.Token) (e
 	if err != nil {
 		return &errors.HTTP{Code: http.StatusNotFound, Message: err.Error()}
 	}
-	apiKey, err := u.RegenerateAPIKey()
+	scope := auth.TokenScope{
+		Kind: r.FormValue("scope"),
+		Apps: r.Form["apps"],
+	}
+	for _, appName := range scope.Apps {
+		_, err = app.GetByName(appName)
+		if err != nil {
+			if err == app.ErrAppNotFound {
+				return &errors.HTTP{Code: http.StatusNotFound, Message: err.Error()}
+			}
+			return err
+		}
+	}
+	apiKey, err := u.RegenerateScopedAPIKey(scope)
 	if err != nil {
 		return err
 	}
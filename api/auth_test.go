@@ -1222,6 +1222,53 @@ func (s *AuthSuite) TestRegenerateAPITokenHandlerOtherUserAndNotAdminUser(c *che
 	c.Assert(err.(*errors.HTTP).Code, check.Equals, http.StatusForbidden)
 }
 
+func (s *AuthSuite) TestRegenerateAPITokenHandlerWithScope(c *check.C) {
+	a := app.App{Name: "myapp", Platform: "python", TeamOwner: s.team.Name}
+	err := app.CreateApp(&a, s.user)
+	c.Assert(err, check.IsNil)
+	body := strings.NewReader("scope=deploy-only&apps=myapp")
+	request, err := http.NewRequest("POST", "/users/api-key", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	var got string
+	err = json.NewDecoder(recorder.Body).Decode(&got)
+	c.Assert(err, check.IsNil)
+	token, err := auth.APIAuth("bearer " + got)
+	c.Assert(err, check.IsNil)
+	c.Assert(token.Scope.Kind, check.Equals, auth.TokenScopeDeployOnly)
+	c.Assert(token.Scope.Apps, check.DeepEquals, []string{"myapp"})
+	c.Assert(permission.Check(token, permission.PermAppDeploy, permission.Context(permission.CtxApp, "myapp")), check.Equals, true)
+	c.Assert(permission.Check(token, permission.PermAppDelete, permission.Context(permission.CtxApp, "myapp")), check.Equals, false)
+	c.Assert(permission.Check(token, permission.PermUserUpdateToken, permission.Context(permission.CtxUser, s.user.Email)), check.Equals, false)
+}
+
+func (s *AuthSuite) TestRegenerateAPITokenHandlerInvalidScope(c *check.C) {
+	body := strings.NewReader("scope=everything")
+	request, err := http.NewRequest("POST", "/users/api-key", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+	c.Assert(recorder.Body.String(), check.Equals, "invalid token scope \"everything\", must be one of: read-only, deploy-only\n")
+}
+
+func (s *AuthSuite) TestRegenerateAPITokenHandlerScopeAppNotFound(c *check.C) {
+	body := strings.NewReader("apps=unknown")
+	request, err := http.NewRequest("POST", "/users/api-key", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusNotFound)
+}
+
 func (s *AuthSuite) TestShowAPITokenForUserWithNoToken(c *check.C) {
 	u := auth.User{Email: "zobomafoo@zimbabue.com", Password: "123456"}
 	_, err := nativeScheme.Create(&u)
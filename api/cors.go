@@ -0,0 +1,82 @@
+// Copyright 2018 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/tsuru/config"
+)
+
+var (
+	defaultCORSMethods = []string{"GET", "POST", "PUT", "DELETE"}
+	defaultCORSHeaders = []string{"Accept", "Authorization", "Content-Type"}
+	defaultCORSExposed = []string{totalCountHeader, "Retry-After"}
+)
+
+// corsMiddleware allows browsers in the configured origins to call the API,
+// answering preflight requests before they reach the router, which doesn't
+// know about OPTIONS.
+type corsMiddleware struct {
+	origins []string
+	methods []string
+	headers []string
+	exposed []string
+}
+
+// newCORSMiddleware returns nil when no origin is allowed in the config.
+func newCORSMiddleware() *corsMiddleware {
+	origins, _ := config.GetList("server:cors:allowed-origins")
+	if len(origins) == 0 {
+		return nil
+	}
+	methods, _ := config.GetList("server:cors:allowed-methods")
+	if len(methods) == 0 {
+		methods = defaultCORSMethods
+	}
+	headers, _ := config.GetList("server:cors:allowed-headers")
+	if len(headers) == 0 {
+		headers = defaultCORSHeaders
+	}
+	exposed, _ := config.GetList("server:cors:exposed-headers")
+	if len(exposed) == 0 {
+		exposed = defaultCORSExposed
+	}
+	return &corsMiddleware{origins: origins, methods: methods, headers: headers, exposed: exposed}
+}
+
+func (m *corsMiddleware) allowedOrigin(origin string) bool {
+	for _, o := range m.origins {
+		if o == "*" || strings.EqualFold(o, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *corsMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		next(w, r)
+		return
+	}
+	w.Header().Add("Vary", "Origin")
+	if !m.allowedOrigin(origin) {
+		next(w, r)
+		return
+	}
+	w.Header().Set("Access-Control-Allow-Origin", origin)
+	if r.Method != http.MethodOptions || r.Header.Get("Access-Control-Request-Method") == "" {
+		if len(m.exposed) > 0 {
+			w.Header().Set("Access-Control-Expose-Headers", strings.Join(m.exposed, ", "))
+		}
+		next(w, r)
+		return
+	}
+	w.Header().Set("Access-Control-Allow-Methods", strings.Join(m.methods, ", "))
+	w.Header().Set("Access-Control-Allow-Headers", strings.Join(m.headers, ", "))
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,94 @@
+// Copyright 2018 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/tsuru/config"
+	"gopkg.in/check.v1"
+)
+
+func (s *S) TestNewCORSMiddleware(c *check.C) {
+	c.Assert(newCORSMiddleware(), check.IsNil)
+	config.Set("server:cors:allowed-origins", []interface{}{"https://dashboard.example.com"})
+	defer config.Unset("server:cors")
+	m := newCORSMiddleware()
+	c.Assert(m, check.DeepEquals, &corsMiddleware{
+		origins: []string{"https://dashboard.example.com"},
+		methods: defaultCORSMethods,
+		headers: defaultCORSHeaders,
+		exposed: []string{"X-Total-Count", "Retry-After"},
+	})
+	config.Set("server:cors:allowed-methods", []interface{}{"GET"})
+	config.Set("server:cors:allowed-headers", []interface{}{"Authorization"})
+	config.Set("server:cors:exposed-headers", []interface{}{"X-Total-Count"})
+	m = newCORSMiddleware()
+	c.Assert(m.methods, check.DeepEquals, []string{"GET"})
+	c.Assert(m.headers, check.DeepEquals, []string{"Authorization"})
+	c.Assert(m.exposed, check.DeepEquals, []string{"X-Total-Count"})
+}
+
+func (s *S) TestCORSMiddlewarePreflight(c *check.C) {
+	m := &corsMiddleware{
+		origins: []string{"https://dashboard.example.com"},
+		methods: []string{"GET", "POST"},
+		headers: []string{"Authorization", "Content-Type"},
+	}
+	request, err := http.NewRequest("OPTIONS", "/apps", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Origin", "https://dashboard.example.com")
+	request.Header.Set("Access-Control-Request-Method", "POST")
+	recorder := httptest.NewRecorder()
+	h, log := doHandler()
+	m.ServeHTTP(recorder, request, h)
+	c.Assert(log.called, check.Equals, false)
+	c.Assert(recorder.Code, check.Equals, http.StatusNoContent)
+	c.Assert(recorder.Header().Get("Access-Control-Allow-Origin"), check.Equals, "https://dashboard.example.com")
+	c.Assert(recorder.Header().Get("Access-Control-Allow-Methods"), check.Equals, "GET, POST")
+	c.Assert(recorder.Header().Get("Access-Control-Allow-Headers"), check.Equals, "Authorization, Content-Type")
+	c.Assert(recorder.Header().Get("Access-Control-Expose-Headers"), check.Equals, "")
+	c.Assert(recorder.Header().Get("Vary"), check.Equals, "Origin")
+}
+
+func (s *S) TestCORSMiddlewareSimpleRequest(c *check.C) {
+	m := &corsMiddleware{origins: []string{"*"}, exposed: []string{"X-Total-Count", "Retry-After"}}
+	request, err := http.NewRequest("GET", "/apps", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Origin", "https://dashboard.example.com")
+	recorder := httptest.NewRecorder()
+	h, log := doHandler()
+	m.ServeHTTP(recorder, request, h)
+	c.Assert(log.called, check.Equals, true)
+	c.Assert(recorder.Header().Get("Access-Control-Allow-Origin"), check.Equals, "https://dashboard.example.com")
+	c.Assert(recorder.Header().Get("Access-Control-Allow-Methods"), check.Equals, "")
+	c.Assert(recorder.Header().Get("Access-Control-Expose-Headers"), check.Equals, "X-Total-Count, Retry-After")
+}
+
+func (s *S) TestCORSMiddlewareOriginNotAllowed(c *check.C) {
+	m := &corsMiddleware{origins: []string{"https://dashboard.example.com"}}
+	request, err := http.NewRequest("OPTIONS", "/apps", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Origin", "https://evil.example.com")
+	request.Header.Set("Access-Control-Request-Method", "DELETE")
+	recorder := httptest.NewRecorder()
+	h, log := doHandler()
+	m.ServeHTTP(recorder, request, h)
+	c.Assert(log.called, check.Equals, true)
+	c.Assert(recorder.Header().Get("Access-Control-Allow-Origin"), check.Equals, "")
+	c.Assert(recorder.Header().Get("Vary"), check.Equals, "Origin")
+}
+
+func (s *S) TestCORSMiddlewareWithoutOrigin(c *check.C) {
+	m := &corsMiddleware{origins: []string{"*"}, exposed: []string{"X-Total-Count", "Retry-After"}}
+	request, err := http.NewRequest("GET", "/apps", nil)
+	c.Assert(err, check.IsNil)
+	recorder := httptest.NewRecorder()
+	h, log := doHandler()
+	m.ServeHTTP(recorder, request, h)
+	c.Assert(log.called, check.Equals, true)
+	c.Assert(recorder.Header(), check.HasLen, 0)
+}
@@ -170,6 +170,41 @@ func diffDeploy(w http.ResponseWriter, r *http.Request, t auth.Token) error {
 	})
 }
 
+// title: cancel deploy
+// path: /apps/{appname}/deploy/cancel
+// method: POST
+// consume: application/x-www-form-urlencoded
+// responses:
+//   204: Cancel requested
+//   400: Invalid data
+//   401: Unauthorized
+//   403: Forbidden
+//   404: Not found
+func deployCancel(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	appName := r.URL.Query().Get(":appname")
+	a, err := app.GetByName(appName)
+	if err != nil {
+		if err != app.ErrAppNotFound {
+			return err
+		}
+		if !permission.Check(t, permission.PermAppUpdateEvents) {
+			return permission.ErrUnauthorized
+		}
+		return &tsuruErrors.HTTP{Code: http.StatusNotFound, Message: fmt.Sprintf("App %s not found.", appName)}
+	}
+	if !permission.Check(t, permission.PermAppUpdateEvents, contextsForApp(a)...) {
+		return permission.ErrUnauthorized
+	}
+	evt, err := event.GetRunning(appTarget(appName), permission.PermAppDeploy.FullName())
+	if err != nil {
+		if err == event.ErrEventNotFound {
+			return &tsuruErrors.HTTP{Code: http.StatusNotFound, Message: fmt.Sprintf("no deploy running for app %s", appName)}
+		}
+		return err
+	}
+	return cancelEvent(w, r, t, evt)
+}
+
 // title: rollback
 // path: /apps/{appname}/deploy/rollback
 // method: POST
@@ -301,6 +336,43 @@ func deployInfo(w http.ResponseWriter, r *http.Request, t auth.Token) error {
 	return json.NewEncoder(w).Encode(deploy)
 }
 
+// title: deploy changes
+// path: /deploys/{deploy}/changes
+// method: GET
+// produce: application/json
+// responses:
+//   200: OK
+//   400: Invalid data
+//   401: Unauthorized
+//   404: Not found
+func deployChanges(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	depID := r.URL.Query().Get(":deploy")
+	deploy, err := app.GetDeploy(depID)
+	if err != nil {
+		if err == event.ErrEventNotFound {
+			return &tsuruErrors.HTTP{Code: http.StatusNotFound, Message: "Deploy not found."}
+		}
+		return err
+	}
+	dbApp, err := app.GetByName(deploy.App)
+	if err != nil {
+		return err
+	}
+	canGet := permission.Check(t, permission.PermAppReadDeploy, contextsForApp(dbApp)...)
+	if !canGet {
+		return &tsuruErrors.HTTP{Code: http.StatusNotFound, Message: "Deploy not found."}
+	}
+	changes, err := app.CompareDeploys(r.URL.Query().Get("from"), depID)
+	if err != nil {
+		if err == event.ErrEventNotFound {
+			return &tsuruErrors.HTTP{Code: http.StatusNotFound, Message: "Deploy not found."}
+		}
+		return err
+	}
+	w.Header().Add("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(changes)
+}
+
 // title: rebuild
 // path: /apps/{appname}/deploy/rebuild
 // method: POST
@@ -1081,6 +1081,96 @@ func (s *DeploySuite) TestDeployInfoByNonAuthenticated(c *check.C) {
 	c.Assert(recorder.Code, check.Equals, http.StatusUnauthorized)
 }
 
+func (s *DeploySuite) TestDeployChanges(c *check.C) {
+	user, _ := s.token.User()
+	a := app.App{Name: "g1", Platform: "python", TeamOwner: s.team.Name}
+	err := app.CreateApp(&a, user)
+	c.Assert(err, check.IsNil)
+	timestamp := time.Now()
+	depData := []app.DeployData{
+		{App: "g1", Timestamp: timestamp.Add(-3600 * time.Second), Commit: "e293e3e3me03ejm3puejmp3ej3iejop32", Origin: "git"},
+		{App: "g1", Timestamp: timestamp, Commit: "e82nn93nd93mm12o2ueh83dhbd3iu112", Origin: "git", Diff: "fake-diff"},
+	}
+	evts := insertDeploysAsEvents(depData, c)
+	url := fmt.Sprintf("/deploys/%s/changes", evts[1].UniqueID.Hex())
+	request, err := http.NewRequest("GET", url, nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	server := RunServer(true)
+	server.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(recorder.Header().Get("Content-Type"), check.Equals, "application/json")
+	var result app.DeployChanges
+	err = json.Unmarshal(recorder.Body.Bytes(), &result)
+	c.Assert(err, check.IsNil)
+	c.Assert(result.From.ID, check.Equals, evts[0].UniqueID)
+	c.Assert(result.To.ID, check.Equals, evts[1].UniqueID)
+	c.Assert(result.Deploys, check.HasLen, 1)
+	c.Assert(result.Deploys[0].Diff, check.Equals, "fake-diff")
+}
+
+func (s *DeploySuite) TestDeployChangesInvalid(c *check.C) {
+	user, _ := s.token.User()
+	a := app.App{Name: "g1", Platform: "python", TeamOwner: s.team.Name}
+	err := app.CreateApp(&a, user)
+	c.Assert(err, check.IsNil)
+	timestamp := time.Now()
+	depData := []app.DeployData{
+		{App: "g1", Timestamp: timestamp.Add(-3600 * time.Second)},
+		{App: "g1", Timestamp: timestamp},
+	}
+	evts := insertDeploysAsEvents(depData, c)
+	url := fmt.Sprintf("/deploys/%s/changes?from=%s", evts[0].UniqueID.Hex(), evts[1].UniqueID.Hex())
+	request, err := http.NewRequest("GET", url, nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	server := RunServer(true)
+	server.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+	c.Assert(recorder.Body.String(), check.Equals, "the first deploy must be older than the second one\n")
+}
+
+func (s *DeploySuite) TestDeployChangesInvalidByUserWithoutAccess(c *check.C) {
+	user, _ := s.token.User()
+	a := app.App{Name: "g1", Platform: "python", TeamOwner: s.team.Name}
+	err := app.CreateApp(&a, user)
+	c.Assert(err, check.IsNil)
+	user = &auth.User{Email: "user@user.com", Password: "123456"}
+	app.AuthScheme = nativeScheme
+	_, err = nativeScheme.Create(user)
+	c.Assert(err, check.IsNil)
+	token, err := nativeScheme.Login(map[string]string{"email": user.Email, "password": "123456"})
+	c.Assert(err, check.IsNil)
+	timestamp := time.Now()
+	depData := []app.DeployData{
+		{App: "g1", Timestamp: timestamp.Add(-3600 * time.Second)},
+		{App: "g1", Timestamp: timestamp},
+	}
+	evts := insertDeploysAsEvents(depData, c)
+	url := fmt.Sprintf("/deploys/%s/changes?from=%s", evts[0].UniqueID.Hex(), evts[1].UniqueID.Hex())
+	request, err := http.NewRequest("GET", url, nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	server := RunServer(true)
+	server.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusNotFound)
+	c.Assert(recorder.Body.String(), check.Equals, "Deploy not found.\n")
+}
+
+func (s *DeploySuite) TestDeployChangesNotFound(c *check.C) {
+	url := fmt.Sprintf("/deploys/%s/changes", bson.NewObjectId().Hex())
+	request, err := http.NewRequest("GET", url, nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	server := RunServer(true)
+	server.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusNotFound)
+}
+
 func (s *DeploySuite) TestDeployInfoByUserWithoutAccess(c *check.C) {
 	user := &auth.User{Email: "user@user.com", Password: "123456"}
 	app.AuthScheme = nativeScheme
@@ -1112,11 +1202,121 @@ func (s *DeploySuite) TestDeployInfoByUserWithoutAccess(c *check.C) {
 	c.Assert(body, check.Equals, "Deploy not found.\n")
 }
 
+func (s *DeploySuite) TestDeployCancel(c *check.C) {
+	user, _ := s.token.User()
+	a := app.App{Name: "otherapp", Platform: "python", TeamOwner: s.team.Name}
+	err := app.CreateApp(&a, user)
+	c.Assert(err, check.IsNil)
+	evt, err := event.New(&event.Opts{
+		Target:        appTarget(a.Name),
+		Kind:          permission.PermAppDeploy,
+		Owner:         s.token,
+		Allowed:       event.Allowed(permission.PermAppReadEvents, contextsForApp(&a)...),
+		AllowedCancel: event.Allowed(permission.PermAppUpdateEvents, contextsForApp(&a)...),
+		Cancelable:    true,
+	})
+	c.Assert(err, check.IsNil)
+	defer evt.Abort()
+	body := strings.NewReader("reason=build is stuck")
+	request, err := http.NewRequest("POST", "/apps/otherapp/deploy/cancel", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	server := RunServer(true)
+	server.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusNoContent)
+	canceled, err := evt.AckCancel()
+	c.Assert(err, check.IsNil)
+	c.Assert(canceled, check.Equals, true)
+	c.Assert(evt.CancelInfo.Reason, check.Equals, "build is stuck")
+	c.Assert(evt.CancelInfo.Owner, check.Equals, s.token.GetUserName())
+}
+
+func (s *DeploySuite) TestDeployCancelNoRunningDeploy(c *check.C) {
+	user, _ := s.token.User()
+	a := app.App{Name: "otherapp", Platform: "python", TeamOwner: s.team.Name}
+	err := app.CreateApp(&a, user)
+	c.Assert(err, check.IsNil)
+	body := strings.NewReader("reason=build is stuck")
+	request, err := http.NewRequest("POST", "/apps/otherapp/deploy/cancel", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	server := RunServer(true)
+	server.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusNotFound)
+	c.Assert(recorder.Body.String(), check.Equals, "no deploy running for app otherapp\n")
+}
+
+func (s *DeploySuite) TestDeployCancelNoReason(c *check.C) {
+	user, _ := s.token.User()
+	a := app.App{Name: "otherapp", Platform: "python", TeamOwner: s.team.Name}
+	err := app.CreateApp(&a, user)
+	c.Assert(err, check.IsNil)
+	evt, err := event.New(&event.Opts{
+		Target:        appTarget(a.Name),
+		Kind:          permission.PermAppDeploy,
+		Owner:         s.token,
+		Allowed:       event.Allowed(permission.PermAppReadEvents, contextsForApp(&a)...),
+		AllowedCancel: event.Allowed(permission.PermAppUpdateEvents, contextsForApp(&a)...),
+		Cancelable:    true,
+	})
+	c.Assert(err, check.IsNil)
+	defer evt.Abort()
+	request, err := http.NewRequest("POST", "/apps/otherapp/deploy/cancel", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	server := RunServer(true)
+	server.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+	c.Assert(recorder.Body.String(), check.Equals, "reason is mandatory\n")
+}
+
+func (s *DeploySuite) TestDeployCancelWithoutPermission(c *check.C) {
+	user, _ := s.token.User()
+	a := app.App{Name: "otherapp", Platform: "python", TeamOwner: s.team.Name}
+	err := app.CreateApp(&a, user)
+	c.Assert(err, check.IsNil)
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermAppUpdateEvents,
+		Context: permission.Context(permission.CtxApp, "anotherapp"),
+	})
+	server := RunServer(true)
+	for _, appName := range []string{"otherapp", "unknownapp"} {
+		body := strings.NewReader("reason=build is stuck")
+		request, err := http.NewRequest("POST", "/apps/"+appName+"/deploy/cancel", body)
+		c.Assert(err, check.IsNil)
+		request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		request.Header.Set("Authorization", "bearer "+token.GetValue())
+		recorder := httptest.NewRecorder()
+		server.ServeHTTP(recorder, request)
+		c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+	}
+}
+
+func (s *DeploySuite) TestDeployCancelAppNotFound(c *check.C) {
+	body := strings.NewReader("reason=build is stuck")
+	request, err := http.NewRequest("POST", "/apps/unknownapp/deploy/cancel", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	server := RunServer(true)
+	server.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusNotFound)
+	c.Assert(recorder.Body.String(), check.Equals, "App unknownapp not found.\n")
+}
+
 func (s *DeploySuite) TestDeployRollbackHandler(c *check.C) {
 	user, _ := s.token.User()
 	a := app.App{Name: "otherapp", Platform: "python", TeamOwner: s.team.Name}
 	err := app.CreateApp(&a, user)
 	c.Assert(err, check.IsNil)
+	err = image.AppendAppImageName(a.Name, "my-image-123:v1")
+	c.Assert(err, check.IsNil)
 	v := url.Values{}
 	v.Set("origin", "rollback")
 	v.Set("image", "my-image-123:v1")
@@ -1159,6 +1359,8 @@ func (s *DeploySuite) TestDeployRollbackHandlerWithCompleteImage(c *check.C) {
 	a := app.App{Name: "otherapp", Platform: "python", TeamOwner: s.team.Name}
 	err := app.CreateApp(&a, user)
 	c.Assert(err, check.IsNil)
+	err = image.AppendAppImageName(a.Name, "127.0.0.1:5000/tsuru/app-tsuru-dashboard:v1")
+	c.Assert(err, check.IsNil)
 	v := url.Values{}
 	v.Set("origin", "rollback")
 	v.Set("image", "127.0.0.1:5000/tsuru/app-tsuru-dashboard:v1")
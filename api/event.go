@@ -127,6 +127,12 @@ func eventCancel(w http.ResponseWriter, r *http.Request, t auth.Token) error {
 	if err != nil {
 		return &errors.HTTP{Code: http.StatusNotFound, Message: err.Error()}
 	}
+	return cancelEvent(w, r, t, e)
+}
+
+// cancelEvent requests the cancelation of a running event, checking the
+// token is allowed to cancel it.
+func cancelEvent(w http.ResponseWriter, r *http.Request, t auth.Token, e *event.Event) error {
 	reason := r.FormValue("reason")
 	if reason == "" {
 		return &errors.HTTP{Code: http.StatusBadRequest, Message: "reason is mandatory"}
@@ -141,7 +147,7 @@ func eventCancel(w http.ResponseWriter, r *http.Request, t auth.Token) error {
 	}
 	err = e.TryCancel(reason, t.GetUserName())
 	if err != nil {
-		if err == event.ErrNotCancelable {
+		if err == event.ErrNotCancelable || err == event.ErrCancelAlreadyRequested {
 			return &errors.HTTP{Code: http.StatusBadRequest, Message: err.Error()}
 		}
 		return err
@@ -0,0 +1,306 @@
+// Copyright 2018 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// generator reads the doc comments of the API handlers, in the format used
+// by tsuru-api-docs, and the request fields read by each of them, writing
+// them to a Go file used to build the OpenAPI description of the API. With
+// -format yaml it writes the handlers in the format of docs/handlers.yml
+// instead, reading the handlers of every directory given as argument.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+var fileTpl = `// AUTOMATICALLY GENERATED FILE - DO NOT EDIT!
+// Please run 'go generate' to update this file.
+//
+// Copyright {{.Time.Year}} tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+var handlerDocs = map[string]handlerDoc{
+{{range .Handlers}} \
+    {{printf "%q" .Name}}: {
+        Title: {{printf "%q" .Title}},
+{{if .Consume}}        Consume: {{printf "%q" .Consume}},
+{{end}}{{if .Produce}}        Produce: {{printf "%q" .Produce}},
+{{end}}{{if .Responses}}        Responses: map[string]string{
+{{range .Responses}}            {{printf "%q" .Code}}: {{printf "%q" .Description}},
+{{end}}        },
+{{end}}{{if .Query}}        Query: []string{ {{range .Query}}{{printf "%q" .}}, {{end}} },
+{{end}}{{if .Form}}        Form: []string{ {{range .Form}}{{printf "%q" .}}, {{end}} },
+{{end}}    },
+{{end}} \
+}
+`
+
+var yamlTpl = `handlers:
+{{range .Handlers}}  - title: {{.Title}}
+    path: {{.Path}}
+    method: {{.Method}}
+{{if .Consume}}    consume: {{.Consume}}
+{{end}}{{if .Produce}}    produce: {{.Produce}}
+{{end}}{{if .Responses}}    responses:
+{{range .Responses}}      {{.Code}}: {{.Description}}
+{{end}}{{end}}{{end}}`
+
+type response struct {
+	Code        string
+	Description string
+}
+
+type handler struct {
+	Name      string
+	Title     string
+	Path      string
+	Method    string
+	Consume   string
+	Produce   string
+	Responses []response
+	Query     []string
+	Form      []string
+}
+
+type context struct {
+	Time     time.Time
+	Handlers []handler
+}
+
+func main() {
+	out := flag.String("o", "", "output file")
+	outFormat := flag.String("format", "go", "output format, go or yaml")
+	flag.Parse()
+	dirs := flag.Args()
+	if len(dirs) == 0 {
+		dirs = []string{"."}
+	}
+	var handlers []handler
+	for _, dir := range dirs {
+		handlers = append(handlers, parseDir(dir)...)
+	}
+	switch *outFormat {
+	case "go":
+		writeGo(*out, handlers)
+	case "yaml":
+		writeYAML(*out, handlers)
+	default:
+		log.Fatalf("unknown format %q", *outFormat)
+	}
+}
+
+func parseDir(dir string) []handler {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(info os.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go")
+	}, parser.ParseComments)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var handlers []handler
+	for _, pkg := range pkgs {
+		fileNames := make([]string, 0, len(pkg.Files))
+		for name := range pkg.Files {
+			fileNames = append(fileNames, name)
+		}
+		sort.Strings(fileNames)
+		for _, name := range fileNames {
+			for _, decl := range pkg.Files[name].Decls {
+				fn, ok := decl.(*ast.FuncDecl)
+				if !ok || fn.Recv != nil || fn.Doc == nil {
+					continue
+				}
+				h, ok := parseHandler(fn)
+				if ok {
+					handlers = append(handlers, h)
+				}
+			}
+		}
+	}
+	return handlers
+}
+
+// writeYAML writes the handlers in the order they are declared, in the format
+// of docs/handlers.yml.
+func writeYAML(out string, handlers []handler) {
+	tmpl, err := template.New("tpl").Parse(yamlTpl)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, context{Handlers: handlers})
+	if err != nil {
+		log.Fatal(err)
+	}
+	writeFile(out, buf.Bytes())
+}
+
+func writeGo(out string, handlers []handler) {
+	tmpl, err := template.New("tpl").Parse(fileTpl)
+	if err != nil {
+		log.Fatal(err)
+	}
+	sort.Slice(handlers, func(i, j int) bool {
+		return handlers[i].Name < handlers[j].Name
+	})
+	data := context{
+		Time:     time.Now(),
+		Handlers: handlers,
+	}
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, data)
+	if err != nil {
+		log.Fatal(err)
+	}
+	rawFile := buf.Bytes()
+	rawFile = bytes.Replace(rawFile, []byte("\\\n"), []byte{}, -1)
+	formatedFile, err := format.Source(rawFile)
+	if err != nil {
+		log.Fatalf("unable to format code: %s\n%s", err, rawFile)
+	}
+	writeFile(out, formatedFile)
+}
+
+func writeFile(out string, data []byte) {
+	file, err := os.OpenFile(out, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0660)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer file.Close()
+	file.Write(data)
+}
+
+func parseHandler(fn *ast.FuncDecl) (handler, bool) {
+	text := fn.Doc.Text()
+	idx := strings.Index(text, "title:")
+	if idx == -1 {
+		return handler{}, false
+	}
+	h := handler{Name: fn.Name.Name}
+	var inResponses bool
+	for _, line := range strings.Split(text[idx:], "\n") {
+		indented := strings.TrimLeft(line, " \t") != line
+		parts := strings.SplitN(strings.TrimSpace(line), ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		key, value := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+		if indented {
+			if inResponses {
+				h.Responses = append(h.Responses, response{Code: key, Description: value})
+			}
+			continue
+		}
+		inResponses = false
+		switch key {
+		case "title":
+			h.Title = value
+		case "path":
+			h.Path = value
+		case "method":
+			h.Method = value
+		case "consume":
+			h.Consume = value
+		case "produce":
+			h.Produce = value
+		case "responses":
+			inResponses = true
+		}
+	}
+	if h.Path == "" || h.Method == "" {
+		return handler{}, false
+	}
+	sort.Slice(h.Responses, func(i, j int) bool {
+		return h.Responses[i].Code < h.Responses[j].Code
+	})
+	readsBody := h.Method != "GET" && h.Method != "DELETE"
+	h.Query, h.Form = requestFields(fn.Body, readsBody)
+	return h, true
+}
+
+// requestFields looks for the fields read from the request in the body of a
+// handler. Fields read with FormValue are sent in the body unless the handler
+// has no body. Fields decoded into structs are not found.
+func requestFields(body *ast.BlockStmt, readsBody bool) (query, form []string) {
+	querySet := map[string]bool{}
+	formSet := map[string]bool{}
+	ast.Inspect(body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || len(call.Args) != 1 {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		lit, ok := call.Args[0].(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			return true
+		}
+		name, err := strconv.Unquote(lit.Value)
+		if err != nil || name == "" || strings.HasPrefix(name, ":") {
+			return true
+		}
+		switch sel.Sel.Name {
+		case "FormValue":
+			if readsBody {
+				formSet[name] = true
+			} else {
+				querySet[name] = true
+			}
+		case "PostFormValue":
+			formSet[name] = true
+		case "Get":
+			if isQueryCall(sel.X) {
+				querySet[name] = true
+			} else if isFormField(sel.X) {
+				formSet[name] = true
+			}
+		}
+		return true
+	})
+	return sortedKeys(querySet), sortedKeys(formSet)
+}
+
+// isQueryCall reports whether expr is a call to r.URL.Query().
+func isQueryCall(expr ast.Expr) bool {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	return ok && sel.Sel.Name == "Query"
+}
+
+// isFormField reports whether expr is r.Form or r.PostForm.
+func isFormField(expr ast.Expr) bool {
+	sel, ok := expr.(*ast.SelectorExpr)
+	return ok && (sel.Sel.Name == "Form" || sel.Sel.Name == "PostForm")
+}
+
+func sortedKeys(m map[string]bool) []string {
+	if len(m) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,165 @@
+// Copyright 2018 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"compress/gzip"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/tsuru/config"
+)
+
+const defaultGzipMinSize = 1024
+
+// gzipMiddleware compresses responses larger than minSize for clients
+// accepting gzip.
+type gzipMiddleware struct {
+	minSize int
+}
+
+// newGzipMiddleware returns nil when compression is disabled in the config.
+func newGzipMiddleware() *gzipMiddleware {
+	if disabled, _ := config.GetBool("server:gzip:disabled"); disabled {
+		return nil
+	}
+	minSize, err := config.GetInt("server:gzip:min-size")
+	if err != nil {
+		minSize = defaultGzipMinSize
+	}
+	return &gzipMiddleware{minSize: minSize}
+}
+
+func (m *gzipMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	// Upgraded connections are hijacked and written directly, there's no
+	// response to compress.
+	if r.Method == http.MethodHead || r.Header.Get("Upgrade") != "" {
+		next(w, r)
+		return
+	}
+	w.Header().Add("Vary", "Accept-Encoding")
+	if !acceptsGzip(r) {
+		next(w, r)
+		return
+	}
+	gw := &gzipResponseWriter{ResponseWriter: w, minSize: m.minSize}
+	defer gw.close()
+	next(gw, r)
+}
+
+func acceptsGzip(r *http.Request) bool {
+	for _, part := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		params := strings.Split(part, ";")
+		if strings.TrimSpace(params[0]) != "gzip" {
+			continue
+		}
+		for _, param := range params[1:] {
+			param = strings.TrimSpace(param)
+			if !strings.HasPrefix(param, "q=") {
+				continue
+			}
+			if q, err := strconv.ParseFloat(param[2:], 64); err == nil && q == 0 {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// gzipResponseWriter holds the response until it's larger than minSize, then
+// starts compressing it. A flush before that sends what was held without
+// compression, so small streamed responses are not delayed.
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	minSize int
+	buf     []byte
+	status  int
+	decided bool
+	gz      *gzip.Writer
+}
+
+func (w *gzipResponseWriter) WriteHeader(code int) {
+	if w.decided {
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+	w.status = code
+}
+
+func (w *gzipResponseWriter) Write(data []byte) (int, error) {
+	if w.decided {
+		if w.gz != nil {
+			return w.gz.Write(data)
+		}
+		return w.ResponseWriter.Write(data)
+	}
+	w.buf = append(w.buf, data...)
+	if len(w.buf) >= w.minSize {
+		if err := w.decide(true); err != nil {
+			return 0, err
+		}
+	}
+	return len(data), nil
+}
+
+func (w *gzipResponseWriter) decide(compress bool) error {
+	w.decided = true
+	header := w.ResponseWriter.Header()
+	// net/http would sniff the type from the compressed data, it has to be
+	// done with the buffer before it's written.
+	if _, ok := header["Content-Type"]; !ok && len(w.buf) > 0 {
+		header.Set("Content-Type", http.DetectContentType(w.buf))
+	}
+	if compress && header.Get("Content-Encoding") == "" {
+		header.Del("Content-Length")
+		header.Set("Content-Encoding", "gzip")
+		w.gz = gzip.NewWriter(w.ResponseWriter)
+	}
+	if w.status != 0 {
+		w.ResponseWriter.WriteHeader(w.status)
+	}
+	if len(w.buf) == 0 {
+		return nil
+	}
+	buf := w.buf
+	w.buf = nil
+	var err error
+	if w.gz != nil {
+		_, err = w.gz.Write(buf)
+	} else {
+		_, err = w.ResponseWriter.Write(buf)
+	}
+	return err
+}
+
+func (w *gzipResponseWriter) Flush() {
+	if !w.decided {
+		w.decide(false)
+	}
+	if w.gz != nil {
+		w.gz.Flush()
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w *gzipResponseWriter) CloseNotify() <-chan bool {
+	if notifier, ok := w.ResponseWriter.(http.CloseNotifier); ok {
+		return notifier.CloseNotify()
+	}
+	return make(chan bool)
+}
+
+func (w *gzipResponseWriter) close() {
+	if !w.decided {
+		w.decide(false)
+	}
+	if w.gz != nil {
+		w.gz.Close()
+	}
+}
@@ -0,0 +1,150 @@
+// Copyright 2018 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/io"
+	"gopkg.in/check.v1"
+)
+
+func (s *S) TestNewGzipMiddleware(c *check.C) {
+	c.Assert(newGzipMiddleware(), check.DeepEquals, &gzipMiddleware{minSize: defaultGzipMinSize})
+	config.Set("server:gzip:min-size", 10)
+	defer config.Unset("server:gzip")
+	c.Assert(newGzipMiddleware(), check.DeepEquals, &gzipMiddleware{minSize: 10})
+	config.Set("server:gzip:disabled", true)
+	c.Assert(newGzipMiddleware(), check.IsNil)
+}
+
+func (s *S) TestAcceptsGzip(c *check.C) {
+	tests := []struct {
+		header   string
+		expected bool
+	}{
+		{"", false},
+		{"gzip", true},
+		{"deflate, gzip", true},
+		{"gzip;q=0.5, deflate", true},
+		{"gzip; q=0", false},
+		{"gzip;q=0.000", false},
+		{"deflate", false},
+		{"*", false},
+	}
+	for _, tt := range tests {
+		request, err := http.NewRequest("GET", "/apps", nil)
+		c.Assert(err, check.IsNil)
+		request.Header.Set("Accept-Encoding", tt.header)
+		c.Check(acceptsGzip(request), check.Equals, tt.expected, check.Commentf("header %q", tt.header))
+	}
+}
+
+func (s *S) TestGzipMiddlewareCompressesLargeResponses(c *check.C) {
+	m := &gzipMiddleware{minSize: 100}
+	body := bytes.Repeat([]byte("tsuru"), 100)
+	request, err := http.NewRequest("GET", "/apps", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Accept-Encoding", "gzip")
+	recorder := httptest.NewRecorder()
+	m.ServeHTTP(recorder, request, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write(body[:50])
+		w.Write(body[50:])
+	})
+	c.Assert(recorder.Code, check.Equals, http.StatusCreated)
+	c.Assert(recorder.Header().Get("Content-Encoding"), check.Equals, "gzip")
+	c.Assert(recorder.Header().Get("Content-Type"), check.Equals, "application/json")
+	c.Assert(recorder.Header().Get("Vary"), check.Equals, "Accept-Encoding")
+	reader, err := gzip.NewReader(recorder.Body)
+	c.Assert(err, check.IsNil)
+	data, err := ioutil.ReadAll(reader)
+	c.Assert(err, check.IsNil)
+	c.Assert(data, check.DeepEquals, body)
+}
+
+func (s *S) TestGzipMiddlewareDetectsContentType(c *check.C) {
+	m := &gzipMiddleware{minSize: 100}
+	body := append([]byte("<html><body>"), bytes.Repeat([]byte("tsuru"), 100)...)
+	request, err := http.NewRequest("GET", "/", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Accept-Encoding", "gzip")
+	recorder := httptest.NewRecorder()
+	m.ServeHTTP(recorder, request, func(w http.ResponseWriter, r *http.Request) {
+		w.Write(body)
+	})
+	c.Assert(recorder.Header().Get("Content-Encoding"), check.Equals, "gzip")
+	c.Assert(recorder.Header().Get("Content-Type"), check.Equals, "text/html; charset=utf-8")
+	reader, err := gzip.NewReader(recorder.Body)
+	c.Assert(err, check.IsNil)
+	data, err := ioutil.ReadAll(reader)
+	c.Assert(err, check.IsNil)
+	c.Assert(data, check.DeepEquals, body)
+}
+
+func (s *S) TestGzipMiddlewareSmallResponses(c *check.C) {
+	m := &gzipMiddleware{minSize: 100}
+	request, err := http.NewRequest("GET", "/apps", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Accept-Encoding", "gzip")
+	recorder := httptest.NewRecorder()
+	m.ServeHTTP(recorder, request, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("app not found"))
+	})
+	c.Assert(recorder.Code, check.Equals, http.StatusNotFound)
+	c.Assert(recorder.Header().Get("Content-Encoding"), check.Equals, "")
+	c.Assert(recorder.Body.String(), check.Equals, "app not found")
+}
+
+func (s *S) TestGzipMiddlewareClientWithoutGzip(c *check.C) {
+	m := &gzipMiddleware{minSize: 10}
+	body := bytes.Repeat([]byte("tsuru"), 100)
+	request, err := http.NewRequest("GET", "/apps", nil)
+	c.Assert(err, check.IsNil)
+	recorder := httptest.NewRecorder()
+	m.ServeHTTP(recorder, request, func(w http.ResponseWriter, r *http.Request) {
+		w.Write(body)
+	})
+	c.Assert(recorder.Header().Get("Content-Encoding"), check.Equals, "")
+	c.Assert(recorder.Header().Get("Vary"), check.Equals, "Accept-Encoding")
+	c.Assert(recorder.Body.Bytes(), check.DeepEquals, body)
+}
+
+func (s *S) TestGzipMiddlewareFlushBeforeMinSize(c *check.C) {
+	m := &gzipMiddleware{minSize: 100}
+	request, err := http.NewRequest("GET", "/apps/myapp/log", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Accept-Encoding", "gzip")
+	recorder := httptest.NewRecorder()
+	m.ServeHTTP(recorder, request, func(w http.ResponseWriter, r *http.Request) {
+		fw := io.FlushingWriter{ResponseWriter: w}
+		fw.Write([]byte("first line\n"))
+		c.Assert(recorder.Body.String(), check.Equals, "first line\n")
+		fw.Write(bytes.Repeat([]byte("a"), 200))
+	})
+	c.Assert(recorder.Header().Get("Content-Encoding"), check.Equals, "")
+	c.Assert(recorder.Body.Len(), check.Equals, 211)
+}
+
+func (s *S) TestGzipMiddlewareIgnoresUpgrade(c *check.C) {
+	m := &gzipMiddleware{minSize: 10}
+	request, err := http.NewRequest("GET", "/apps/myapp/log/stream", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Accept-Encoding", "gzip")
+	request.Header.Set("Upgrade", "websocket")
+	recorder := httptest.NewRecorder()
+	var writer http.ResponseWriter
+	m.ServeHTTP(recorder, request, func(w http.ResponseWriter, r *http.Request) {
+		writer = w
+	})
+	c.Assert(writer, check.Equals, http.ResponseWriter(recorder))
+}
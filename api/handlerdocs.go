@@ -0,0 +1,1968 @@
+// AUTOMATICALLY GENERATED FILE - DO NOT EDIT!
+// Please run 'go generate' to update this file.
+//
+// Copyright 2018 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+var handlerDocs = map[string]handlerDoc{
+	"addAppRouter": {
+		Title:   "add app router",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "OK",
+			"400": "Invalid request",
+			"404": "App or router not found",
+		},
+	},
+	"addDefaultRole": {
+		Title:   "add default role",
+		Consume: "application/x-www-form-urlencoded",
+		Responses: map[string]string{
+			"200": "Ok",
+			"400": "Invalid data",
+			"401": "Unauthorized",
+		},
+	},
+	"addKeyToUser": {
+		Title:   "add key",
+		Consume: "application/x-www-form-urlencoded",
+		Responses: map[string]string{
+			"200": "Ok",
+			"400": "Invalid data",
+			"401": "Unauthorized",
+			"409": "Key already exists",
+		},
+		Form: []string{"force", "key", "name"},
+	},
+	"addLog": {
+		Title:   "app log",
+		Consume: "application/x-www-form-urlencoded",
+		Responses: map[string]string{
+			"200": "Ok",
+			"400": "Invalid data",
+			"401": "Unauthorized",
+			"404": "App not found",
+		},
+		Form: []string{"source", "unit"},
+	},
+	"addNodeHandler": {
+		Title:   "add node",
+		Consume: "application/x-www-form-urlencoded",
+		Produce: "application/x-json-stream",
+		Responses: map[string]string{
+			"201": "Ok",
+			"401": "Unauthorized",
+			"404": "Not found",
+		},
+	},
+	"addPermissions": {
+		Title:   "add permissions",
+		Consume: "application/x-www-form-urlencoded",
+		Responses: map[string]string{
+			"200": "Ok",
+			"400": "Invalid data",
+			"401": "Unauthorized",
+			"409": "Permission not allowed",
+		},
+	},
+	"addPlan": {
+		Title:   "plan create",
+		Consume: "application/x-www-form-urlencoded",
+		Responses: map[string]string{
+			"201": "Plan created",
+			"400": "Invalid data",
+			"401": "Unauthorized",
+			"409": "Plan already exists",
+		},
+		Form: []string{"cpushare", "default", "memory", "name", "swap"},
+	},
+	"addPoolHandler": {
+		Title:   "pool create",
+		Consume: "application/x-www-form-urlencoded",
+		Responses: map[string]string{
+			"201": "Pool created",
+			"400": "Invalid data",
+			"401": "Unauthorized",
+			"409": "Pool already exists",
+		},
+	},
+	"addRole": {
+		Title:   "role create",
+		Consume: "application/x-www-form-urlencoded",
+		Responses: map[string]string{
+			"201": "Role created",
+			"400": "Invalid data",
+			"401": "Unauthorized",
+			"409": "Role already exists",
+		},
+		Form: []string{"context", "description", "name"},
+	},
+	"addScaleSchedule": {
+		Title:   "add app scale schedule",
+		Consume: "application/x-www-form-urlencoded",
+		Responses: map[string]string{
+			"200": "Ok",
+			"400": "Invalid data",
+			"401": "Unauthorized",
+			"404": "App not found",
+		},
+	},
+	"addTeamToPoolHandler": {
+		Title:   "add team too pool",
+		Consume: "application/x-www-form-urlencoded",
+		Responses: map[string]string{
+			"200": "Pool updated",
+			"400": "Invalid data",
+			"401": "Unauthorized",
+			"404": "Pool not found",
+		},
+	},
+	"addUnits": {
+		Title:   "add units",
+		Consume: "application/x-www-form-urlencoded",
+		Produce: "application/x-json-stream",
+		Responses: map[string]string{
+			"200": "Units added",
+			"400": "Invalid data",
+			"401": "Unauthorized",
+			"404": "App not found",
+		},
+		Form: []string{"node", "process"},
+	},
+	"apiSchema": {
+		Title:   "api schema",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "OK",
+		},
+	},
+	"appDelete": {
+		Title:   "remove app",
+		Produce: "application/x-json-stream",
+		Responses: map[string]string{
+			"200": "App removed",
+			"401": "Unauthorized",
+			"404": "Not found",
+		},
+	},
+	"appInfo": {
+		Title:   "app info",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "OK",
+			"401": "Unauthorized",
+			"404": "Not found",
+		},
+	},
+	"appList": {
+		Title:   "app list",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "List apps",
+			"204": "No content",
+			"401": "Unauthorized",
+		},
+		Query: []string{"locked", "name", "owner", "platform", "pool", "sort", "team", "teamOwner"},
+	},
+	"appLog": {
+		Title:   "app log",
+		Produce: "application/x-json-stream",
+		Responses: map[string]string{
+			"200": "Ok",
+			"400": "Invalid data",
+			"401": "Unauthorized",
+			"404": "App not found",
+		},
+		Query: []string{"follow", "lines", "skip", "source", "unit"},
+	},
+	"appLogStream": {
+		Title:   "app log stream",
+		Produce: "Websocket connection upgrade",
+		Responses: map[string]string{
+			"101": "Switch Protocol to websocket",
+		},
+	},
+	"appMetricEnvs": {
+		Title:   "metric envs",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "Ok",
+			"401": "Unauthorized",
+			"404": "App not found",
+		},
+	},
+	"appRebuildRoutes": {
+		Title:   "rebuild routes",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "Ok",
+			"401": "Unauthorized",
+			"404": "App not found",
+		},
+		Form: []string{"dry"},
+	},
+	"appUnitsMetrics": {
+		Title:   "app units metrics",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "Ok",
+			"204": "No content",
+			"401": "Unauthorized",
+			"404": "App not found",
+		},
+	},
+	"assignRole": {
+		Title:   "assign role to user",
+		Consume: "application/x-www-form-urlencoded",
+		Responses: map[string]string{
+			"200": "Ok",
+			"400": "Invalid data",
+			"401": "Unauthorized",
+			"404": "Role not found",
+		},
+		Form: []string{"context", "email"},
+	},
+	"auditList": {
+		Title:   "audit list",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "OK",
+			"204": "No content",
+			"400": "Invalid data",
+			"401": "Unauthorized",
+		},
+		Query: []string{"app", "user"},
+	},
+	"authScheme": {
+		Title:   "get auth scheme",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "OK",
+		},
+	},
+	"autoScaleDeleteRule": {
+		Title: "delete autoscale rule",
+		Responses: map[string]string{
+			"200": "Ok",
+			"401": "Unauthorized",
+			"404": "Not found",
+		},
+	},
+	"autoScaleGetConfig": {
+		Title:   "get autoscale config",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "Ok",
+			"401": "Unauthorized",
+		},
+	},
+	"autoScaleHistoryHandler": {
+		Title:   "list autoscale history",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "Ok",
+			"204": "No content",
+			"401": "Unauthorized",
+		},
+		Query: []string{"limit", "skip"},
+	},
+	"autoScaleListRules": {
+		Title:   "autoscale rules list",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "Ok",
+			"204": "No content",
+			"401": "Unauthorized",
+		},
+	},
+	"autoScaleRunHandler": {
+		Title:   "autoscale run",
+		Produce: "application/x-json-stream",
+		Responses: map[string]string{
+			"200": "Ok",
+			"401": "Unauthorized",
+		},
+	},
+	"autoScaleSetRule": {
+		Title:   "autoscale set rule",
+		Consume: "application/x-www-form-urlencoded",
+		Responses: map[string]string{
+			"200": "Ok",
+			"400": "Invalid data",
+			"401": "Unauthorized",
+		},
+	},
+	"bindServiceInstance": {
+		Title:   "bind service instance",
+		Consume: "application/x-www-form-urlencoded",
+		Produce: "application/x-json-stream",
+		Responses: map[string]string{
+			"200": "Ok",
+			"400": "Invalid data",
+			"401": "Unauthorized",
+			"404": "App not found",
+		},
+		Form: []string{"noRestart"},
+	},
+	"build": {
+		Title:   "app build",
+		Consume: "application/x-www-form-urlencoded",
+		Responses: map[string]string{
+			"200": "OK",
+			"400": "Invalid data",
+			"403": "Forbidden",
+			"404": "Not found",
+		},
+		Form: []string{"tag", "user"},
+	},
+	"changeAppQuota": {
+		Title:   "update application quota",
+		Consume: "application/x-www-form-urlencoded",
+		Responses: map[string]string{
+			"200": "Quota updated",
+			"400": "Invalid data",
+			"401": "Unauthorized",
+			"404": "Application not found",
+		},
+		Form: []string{"limit"},
+	},
+	"changePassword": {
+		Title:   "change password",
+		Consume: "application/x-www-form-urlencoded",
+		Responses: map[string]string{
+			"200": "Ok",
+			"400": "Invalid data",
+			"401": "Unauthorized",
+			"403": "Forbidden",
+			"404": "Not found",
+		},
+		Form: []string{"confirm", "new", "old"},
+	},
+	"changeUserQuota": {
+		Title:   "update user quota",
+		Consume: "application/x-www-form-urlencoded",
+		Responses: map[string]string{
+			"200": "Quota updated",
+			"400": "Invalid data",
+			"401": "Unauthorized",
+			"404": "User not found",
+		},
+		Form: []string{"limit"},
+	},
+	"cloneApp": {
+		Title:   "app clone",
+		Consume: "application/x-www-form-urlencoded",
+		Produce: "application/x-json-stream",
+		Responses: map[string]string{
+			"200": "App cloned",
+			"400": "Invalid data",
+			"401": "Unauthorized",
+			"403": "Quota exceeded",
+			"404": "App not found",
+			"409": "App already exists",
+		},
+		Form: []string{"name"},
+	},
+	"cmdlineHandler": {
+		Title: "profile cmdline handler",
+		Responses: map[string]string{
+			"200": "Ok",
+			"401": "Unauthorized",
+		},
+	},
+	"createApp": {
+		Title:   "app create",
+		Consume: "application/x-www-form-urlencoded",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"201": "App created",
+			"400": "Invalid data",
+			"401": "Unauthorized",
+			"403": "Quota exceeded",
+			"409": "App already exists",
+		},
+	},
+	"createCluster": {
+		Title:   "create provisioner cluster",
+		Consume: "application/x-www-form-urlencoded",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "Ok",
+			"400": "Invalid data",
+			"401": "Unauthorized",
+			"404": "Pool does not exist",
+			"409": "Cluster already exists",
+		},
+	},
+	"createServiceInstance": {
+		Title:   "service instance create",
+		Consume: "application/x-www-form-urlencoded",
+		Responses: map[string]string{
+			"201": "Service created",
+			"400": "Invalid data",
+			"401": "Unauthorized",
+			"409": "Service already exists",
+		},
+		Form: []string{"description", "name", "owner", "plan"},
+	},
+	"createTeam": {
+		Title:   "team create",
+		Consume: "application/x-www-form-urlencoded",
+		Responses: map[string]string{
+			"201": "Team created",
+			"400": "Invalid data",
+			"401": "Unauthorized",
+			"409": "Team already exists",
+		},
+		Form: []string{"name"},
+	},
+	"createUser": {
+		Title:   "user create",
+		Consume: "application/x-www-form-urlencoded",
+		Responses: map[string]string{
+			"201": "User created",
+			"400": "Invalid data",
+			"401": "Unauthorized",
+			"403": "Forbidden",
+			"409": "User already exists",
+		},
+		Form: []string{"email", "password"},
+	},
+	"deleteCluster": {
+		Title:   "delete provisioner cluster",
+		Consume: "application/x-www-form-urlencoded",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "Ok",
+			"401": "Unauthorized",
+			"404": "Cluster not found",
+		},
+	},
+	"deploy": {
+		Title:   "app deploy",
+		Consume: "application/x-www-form-urlencoded",
+		Responses: map[string]string{
+			"200": "OK",
+			"400": "Invalid data",
+			"403": "Forbidden",
+			"404": "Not found",
+		},
+		Form: []string{"commit", "message", "origin", "user"},
+	},
+	"deployCancel": {
+		Title:   "cancel deploy",
+		Consume: "application/x-www-form-urlencoded",
+		Responses: map[string]string{
+			"204": "Cancel requested",
+			"400": "Invalid data",
+			"401": "Unauthorized",
+			"403": "Forbidden",
+			"404": "Not found",
+		},
+	},
+	"deployChanges": {
+		Title:   "deploy changes",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "OK",
+			"400": "Invalid data",
+			"401": "Unauthorized",
+			"404": "Not found",
+		},
+		Query: []string{"from"},
+	},
+	"deployInfo": {
+		Title:   "deploy info",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "OK",
+			"401": "Unauthorized",
+			"404": "Not found",
+		},
+	},
+	"deployRebuild": {
+		Title:   "rebuild",
+		Consume: "application/x-www-form-urlencoded",
+		Produce: "application/x-json-stream",
+		Responses: map[string]string{
+			"200": "OK",
+			"400": "Invalid data",
+			"403": "Forbidden",
+			"404": "Not found",
+		},
+		Form: []string{"origin"},
+	},
+	"deployRollback": {
+		Title:   "rollback",
+		Consume: "application/x-www-form-urlencoded",
+		Produce: "application/x-json-stream",
+		Responses: map[string]string{
+			"200": "OK",
+			"400": "Invalid data",
+			"403": "Forbidden",
+			"404": "Not found",
+		},
+		Form: []string{"image", "origin"},
+	},
+	"deployRollbackUpdate": {
+		Title:   "rollback update",
+		Consume: "application/x-www-form-urlencoded",
+		Responses: map[string]string{
+			"200": "Rollback updated",
+			"400": "Invalid data",
+			"403": "Forbidden",
+		},
+		Form: []string{"disable", "image", "reason"},
+	},
+	"deploysList": {
+		Title:   "deploy list",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "OK",
+			"204": "No content",
+		},
+		Query: []string{"app", "limit", "skip"},
+	},
+	"diffDeploy": {
+		Title:   "deploy diff",
+		Consume: "application/x-www-form-urlencoded",
+		Responses: map[string]string{
+			"200": "OK",
+			"400": "Invalid data",
+			"403": "Forbidden",
+			"404": "Not found",
+		},
+		Form: []string{"customdata"},
+	},
+	"disableMaintenance": {
+		Title:   "app maintenance disable",
+		Produce: "application/x-json-stream",
+		Responses: map[string]string{
+			"200": "Ok",
+			"401": "Unauthorized",
+			"404": "App not found",
+		},
+	},
+	"dissociateRole": {
+		Title: "dissociate role from user",
+		Responses: map[string]string{
+			"200": "Ok",
+			"400": "Invalid data",
+			"401": "Unauthorized",
+			"404": "Role not found",
+		},
+		Query: []string{"context"},
+	},
+	"dumpGoroutines": {
+		Title: "dump goroutines",
+		Responses: map[string]string{
+			"200": "Ok",
+		},
+	},
+	"enableMaintenance": {
+		Title:   "app maintenance enable",
+		Produce: "application/x-json-stream",
+		Responses: map[string]string{
+			"200": "Ok",
+			"400": "Maintenance not configured",
+			"401": "Unauthorized",
+			"404": "App not found",
+		},
+	},
+	"eventBlockAdd": {
+		Title:   "add event block",
+		Consume: "application/x-www-form-urlencoded",
+		Responses: map[string]string{
+			"200": "OK",
+			"400": "Invalid data or empty reason",
+			"401": "Unauthorized",
+		},
+	},
+	"eventBlockList": {
+		Title:   "event block list",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "OK",
+			"204": "No content",
+			"401": "Unauthorized",
+		},
+		Query: []string{"active"},
+	},
+	"eventBlockRemove": {
+		Title: "remove event block",
+		Responses: map[string]string{
+			"200": "OK",
+			"400": "Invalid uuid",
+			"401": "Unauthorized",
+			"404": "Active block with provided uuid not found",
+		},
+	},
+	"eventCancel": {
+		Title:   "event cancel",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "OK",
+			"400": "Invalid uuid or empty reason",
+			"404": "Not found",
+		},
+	},
+	"eventInfo": {
+		Title:   "event info",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "OK",
+			"400": "Invalid uuid",
+			"401": "Unauthorized",
+			"404": "Not found",
+		},
+	},
+	"eventList": {
+		Title:   "event list",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "OK",
+			"204": "No content",
+		},
+	},
+	"exportEnv": {
+		Title:   "export envs",
+		Produce: "application/json, text/plain",
+		Responses: map[string]string{
+			"200": "OK",
+			"400": "Invalid format",
+			"401": "Unauthorized",
+			"404": "App not found",
+		},
+		Query: []string{"format"},
+	},
+	"forceDeleteLock": {
+		Title:   "app unlock",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "Ok",
+			"401": "Unauthorized",
+			"404": "App not found",
+		},
+	},
+	"getAppQuota": {
+		Title:   "application quota",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "OK",
+			"401": "Unauthorized",
+			"404": "Application not found",
+		},
+	},
+	"getBuildEnv": {
+		Title:   "get build envs",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "OK",
+			"401": "Unauthorized",
+			"404": "App not found",
+		},
+	},
+	"getEnv": {
+		Title:   "get envs",
+		Produce: "application/x-json-stream",
+		Responses: map[string]string{
+			"200": "OK",
+			"401": "Unauthorized",
+			"404": "App not found",
+		},
+	},
+	"getUserQuota": {
+		Title:   "user quota",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "OK",
+			"401": "Unauthorized",
+			"404": "User not found",
+		},
+	},
+	"grantAppAccess": {
+		Title: "grant access to app",
+		Responses: map[string]string{
+			"200": "Access granted",
+			"401": "Unauthorized",
+			"404": "App or team not found",
+			"409": "Grant already exists",
+		},
+	},
+	"grantServiceAccess": {
+		Title: "grant access to a service",
+		Responses: map[string]string{
+			"200": "Service updated",
+			"400": "Team not found",
+			"401": "Unauthorized",
+			"404": "Service not found",
+			"409": "Team already has access to this service",
+		},
+	},
+	"healthcheck": {
+		Title: "healthcheck",
+		Responses: map[string]string{
+			"200": "OK",
+			"500": "Internal server error",
+		},
+	},
+	"imageGC": {
+		Title:   "image gc",
+		Consume: "application/x-www-form-urlencoded",
+		Responses: map[string]string{
+			"200": "Ok",
+			"401": "Unauthorized",
+			"404": "App not found",
+		},
+		Form: []string{"app"},
+	},
+	"importEnv": {
+		Title:   "import envs",
+		Consume: "application/json, text/plain",
+		Produce: "application/x-json-stream",
+		Responses: map[string]string{
+			"200": "Envs updated",
+			"400": "Invalid data",
+			"401": "Unauthorized",
+			"404": "App not found",
+		},
+	},
+	"index": {
+		Title: "index",
+		Responses: map[string]string{
+			"200": "OK",
+		},
+	},
+	"indexHandler": {
+		Title: "profile index handler",
+		Responses: map[string]string{
+			"200": "Ok",
+			"401": "Unauthorized",
+		},
+	},
+	"info": {
+		Title:   "api info",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "OK",
+		},
+	},
+	"infoNodeHandler": {
+		Title:   "node info",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "Ok",
+			"404": "Not found",
+		},
+	},
+	"installHostAdd": {
+		Title:   "add install host",
+		Consume: "application/x-www-form-urlencoded",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"201": "Host added",
+			"401": "Unauthorized",
+		},
+		Form: []string{"driver"},
+	},
+	"installHostInfo": {
+		Title:   "install host info",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "OK",
+			"401": "Unauthorized",
+			"404": "Not Found",
+		},
+	},
+	"installHostList": {
+		Title:   "list install hosts",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "OK",
+			"401": "Unauthorized",
+		},
+	},
+	"kindList": {
+		Title:   "kind list",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "OK",
+			"204": "No content",
+		},
+	},
+	"listAppRouters": {
+		Title:   "list app routers",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "OK",
+			"204": "No content",
+			"404": "App not found",
+		},
+	},
+	"listCertificates": {
+		Title:   "list app certificates",
+		Consume: "application/x-www-form-urlencoded",
+		Responses: map[string]string{
+			"200": "Ok",
+			"401": "Unauthorized",
+			"404": "App not found",
+		},
+	},
+	"listClusters": {
+		Title:   "list provisioner clusters",
+		Consume: "application/x-www-form-urlencoded",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "Ok",
+			"204": "No Content",
+			"401": "Unauthorized",
+		},
+	},
+	"listDefaultRoles": {
+		Title:   "list default roles",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "Ok",
+			"401": "Unauthorized",
+		},
+	},
+	"listKeys": {
+		Title:   "list keys",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "OK",
+			"400": "Invalid data",
+			"401": "Unauthorized",
+		},
+	},
+	"listNodesHandler": {
+		Title:   "list nodes",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "Ok",
+			"204": "No content",
+		},
+	},
+	"listPermissions": {
+		Title:   "list permissions",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "Ok",
+			"401": "Unauthorized",
+		},
+	},
+	"listPlans": {
+		Title:   "plan list",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "OK",
+			"204": "No content",
+		},
+	},
+	"listRoles": {
+		Title:   "role list",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "OK",
+			"401": "Unauthorized",
+		},
+	},
+	"listRouters": {
+		Title:   "router list",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "OK",
+			"204": "No content",
+		},
+	},
+	"listUnitsByApp": {
+		Title:   "list units by app",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "Ok",
+			"204": "No content",
+			"401": "Unauthorized",
+			"404": "Not found",
+		},
+	},
+	"listUnitsByNode": {
+		Title:   "list units by node",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "Ok",
+			"204": "No content",
+			"401": "Unauthorized",
+			"404": "Not found",
+		},
+	},
+	"listUsers": {
+		Title:   "user list",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "OK",
+			"401": "Unauthorized",
+		},
+		Query: []string{"context", "role", "userEmail"},
+	},
+	"login": {
+		Title:   "login",
+		Consume: "application/x-www-form-urlencoded",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "Ok",
+			"400": "Invalid data",
+			"401": "Unauthorized",
+			"403": "Forbidden",
+			"404": "Not found",
+		},
+	},
+	"logout": {
+		Title: "logout",
+		Responses: map[string]string{
+			"200": "Ok",
+		},
+	},
+	"machineDestroy": {
+		Title: "machine destroy",
+		Responses: map[string]string{
+			"200": "OK",
+			"400": "Invalid data",
+			"401": "Unauthorized",
+			"404": "Not found",
+		},
+	},
+	"machinesList": {
+		Title:   "machine list",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "OK",
+			"401": "Unauthorized",
+		},
+	},
+	"nodeContainerCreate": {
+		Title:   "node container create",
+		Consume: "application/x-www-form-urlencoded",
+		Responses: map[string]string{
+			"200": "Ok",
+			"400": "Invald data",
+			"401": "Unauthorized",
+		},
+		Form: []string{"pool"},
+	},
+	"nodeContainerDelete": {
+		Title: "remove node container",
+		Responses: map[string]string{
+			"200": "Ok",
+			"401": "Unauthorized",
+			"404": "Not found",
+		},
+		Query: []string{"kill", "pool"},
+	},
+	"nodeContainerInfo": {
+		Title:   "node container info",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "Ok",
+			"401": "Unauthorized",
+			"404": "Not found",
+		},
+	},
+	"nodeContainerList": {
+		Title:   "remove node container list",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "Ok",
+			"401": "Unauthorized",
+		},
+	},
+	"nodeContainerUpdate": {
+		Title:   "node container update",
+		Consume: "application/x-www-form-urlencoded",
+		Responses: map[string]string{
+			"200": "Ok",
+			"400": "Invald data",
+			"401": "Unauthorized",
+			"404": "Not found",
+		},
+		Form: []string{"pool"},
+	},
+	"nodeContainerUpgrade": {
+		Title:   "node container upgrade",
+		Consume: "application/x-www-form-urlencoded",
+		Produce: "application/x-json-stream",
+		Responses: map[string]string{
+			"200": "Ok",
+			"400": "Invald data",
+			"401": "Unauthorized",
+			"404": "Not found",
+		},
+		Form: []string{"pool"},
+	},
+	"nodeHealingDelete": {
+		Title:   "remove node healing",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "Ok",
+			"401": "Unauthorized",
+		},
+		Query: []string{"pool"},
+	},
+	"nodeHealingRead": {
+		Title:   "node healing info",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "Ok",
+			"401": "Unauthorized",
+		},
+	},
+	"nodeHealingUpdate": {
+		Title:   "node healing update",
+		Consume: "application/x-www-form-urlencoded",
+		Responses: map[string]string{
+			"200": "Ok",
+			"401": "Unauthorized",
+		},
+		Form: []string{"pool"},
+	},
+	"platformAdd": {
+		Title:   "add platform",
+		Consume: "multipart/form-data",
+		Produce: "application/x-json-stream",
+		Responses: map[string]string{
+			"200": "Platform created",
+			"400": "Invalid data",
+			"401": "Unauthorized",
+		},
+		Form: []string{"name"},
+	},
+	"platformList": {
+		Title:   "platform list",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "List platforms",
+			"204": "No content",
+			"401": "Unauthorized",
+		},
+	},
+	"platformRemove": {
+		Title: "remove platform",
+		Responses: map[string]string{
+			"200": "Platform removed",
+			"401": "Unauthorized",
+			"404": "Not found",
+		},
+	},
+	"platformUpdate": {
+		Title:   "update platform",
+		Produce: "application/x-json-stream",
+		Responses: map[string]string{
+			"200": "Platform updated",
+			"401": "Unauthorized",
+			"404": "Not found",
+		},
+	},
+	"poolConstraintList": {
+		Title:   "pool constraints list",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "OK",
+			"204": "No content",
+			"401": "Unauthorized",
+		},
+	},
+	"poolConstraintSet": {
+		Title:   "set a pool constraint",
+		Consume: "application/x-www-form-urlencoded",
+		Responses: map[string]string{
+			"200": "OK",
+			"401": "Unauthorized",
+		},
+		Form: []string{"append"},
+	},
+	"poolList": {
+		Title:   "pool list",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "OK",
+			"204": "No content",
+			"401": "Unauthorized",
+			"404": "User not found",
+		},
+	},
+	"poolUpdateHandler": {
+		Title:   "pool update",
+		Consume: "application/x-www-form-urlencoded",
+		Responses: map[string]string{
+			"200": "Pool updated",
+			"401": "Unauthorized",
+			"404": "Pool not found",
+			"409": "Default pool already defined",
+		},
+	},
+	"profileHandler": {
+		Title: "profile handler",
+		Responses: map[string]string{
+			"200": "Ok",
+			"401": "Unauthorized",
+		},
+	},
+	"queueJobList": {
+		Title:   "queue job list",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "OK",
+			"204": "No content",
+			"401": "Unauthorized",
+		},
+	},
+	"queueJobPurge": {
+		Title: "queue job purge",
+		Responses: map[string]string{
+			"200": "OK",
+			"401": "Unauthorized",
+		},
+	},
+	"queueJobRetry": {
+		Title:   "queue job retry",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "OK",
+			"400": "Job not failed",
+			"401": "Unauthorized",
+			"404": "Job not found",
+		},
+	},
+	"queueStats": {
+		Title:   "queue stats",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "OK",
+			"204": "No content",
+			"401": "Unauthorized",
+		},
+	},
+	"rebalanceNodesHandler": {
+		Title:   "rebalance units in nodes",
+		Consume: "application/x-www-form-urlencoded",
+		Produce: "application/x-json-stream",
+		Responses: map[string]string{
+			"200": "Ok",
+			"400": "Invalid data",
+			"401": "Unauthorized",
+		},
+	},
+	"regenerateAPIToken": {
+		Title:   "regenerate token",
+		Consume: "application/x-www-form-urlencoded",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "OK",
+			"400": "Invalid scope",
+			"401": "Unauthorized",
+			"404": "User not found",
+		},
+		Query: []string{"user"},
+		Form:  []string{"scope"},
+	},
+	"registerUnit": {
+		Title:   "register unit",
+		Consume: "application/x-www-form-urlencoded",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "Ok",
+			"401": "Unauthorized",
+			"404": "App not found",
+		},
+	},
+	"remoteShellHandler": {
+		Title:   "app shell",
+		Produce: "Websocket connection upgrade",
+		Responses: map[string]string{
+			"101": "Switch Protocol to websocket",
+		},
+		Query: []string{"height", "term", "unit", "width"},
+	},
+	"removeAppRouter": {
+		Title:   "delete app router",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "OK",
+			"404": "App or router not found",
+		},
+	},
+	"removeDefaultRole": {
+		Title: "remove default role",
+		Responses: map[string]string{
+			"200": "Ok",
+			"400": "Invalid data",
+			"401": "Unauthorized",
+		},
+	},
+	"removeKeyFromUser": {
+		Title: "remove key",
+		Responses: map[string]string{
+			"200": "Ok",
+			"400": "Invalid data",
+			"401": "Unauthorized",
+			"404": "Not found",
+		},
+	},
+	"removeNodeHandler": {
+		Title: "remove node",
+		Responses: map[string]string{
+			"200": "Ok",
+			"401": "Unauthorized",
+			"404": "Not found",
+		},
+		Query: []string{"no-rebalance", "remove-iaas"},
+	},
+	"removePermissions": {
+		Title: "remove permission",
+		Responses: map[string]string{
+			"200": "Permission removed",
+			"401": "Unauthorized",
+			"404": "Not found",
+		},
+	},
+	"removePlan": {
+		Title: "remove plan",
+		Responses: map[string]string{
+			"200": "Plan removed",
+			"401": "Unauthorized",
+			"404": "Plan not found",
+		},
+	},
+	"removePoolHandler": {
+		Title: "remove pool",
+		Responses: map[string]string{
+			"200": "Pool removed",
+			"401": "Unauthorized",
+			"404": "Pool not found",
+		},
+	},
+	"removeRole": {
+		Title: "remove role",
+		Responses: map[string]string{
+			"200": "Role removed",
+			"401": "Unauthorized",
+			"404": "Role not found",
+		},
+	},
+	"removeScaleSchedule": {
+		Title: "remove app scale schedule",
+		Responses: map[string]string{
+			"200": "Ok",
+			"401": "Unauthorized",
+			"404": "App or schedule not found",
+		},
+	},
+	"removeServiceInstance": {
+		Title:   "remove service instance",
+		Produce: "application/x-json-stream",
+		Responses: map[string]string{
+			"200": "Service removed",
+			"400": "Bad request",
+			"401": "Unauthorized",
+			"404": "Service instance not found",
+		},
+		Query: []string{"unbindall"},
+	},
+	"removeTeam": {
+		Title: "remove team",
+		Responses: map[string]string{
+			"200": "Team removed",
+			"401": "Unauthorized",
+			"403": "Forbidden",
+			"404": "Not found",
+		},
+	},
+	"removeTeamToPoolHandler": {
+		Title: "remove team from pool",
+		Responses: map[string]string{
+			"200": "Pool updated",
+			"400": "Invalid data",
+			"401": "Unauthorized",
+			"404": "Pool not found",
+		},
+	},
+	"removeUnits": {
+		Title:   "remove units",
+		Produce: "application/x-json-stream",
+		Responses: map[string]string{
+			"200": "Units removed",
+			"400": "Invalid data",
+			"401": "Unauthorized",
+			"404": "App not found",
+		},
+		Query: []string{"node", "process"},
+	},
+	"removeUser": {
+		Title: "remove user",
+		Responses: map[string]string{
+			"200": "User removed",
+			"401": "Unauthorized",
+			"404": "Not found",
+		},
+		Query: []string{"user"},
+	},
+	"resetPassword": {
+		Title: "reset password",
+		Responses: map[string]string{
+			"200": "Ok",
+			"400": "Invalid data",
+			"401": "Unauthorized",
+			"403": "Forbidden",
+			"404": "Not found",
+		},
+		Form: []string{"token"},
+	},
+	"restart": {
+		Title:   "app restart",
+		Consume: "application/x-www-form-urlencoded",
+		Produce: "application/x-json-stream",
+		Responses: map[string]string{
+			"200": "Ok",
+			"400": "Invalid data",
+			"401": "Unauthorized",
+			"404": "App not found",
+		},
+		Form: []string{"process"},
+	},
+	"revokeAppAccess": {
+		Title: "revoke access to app",
+		Responses: map[string]string{
+			"200": "Access revoked",
+			"401": "Unauthorized",
+			"403": "Forbidden",
+			"404": "App or team not found",
+		},
+	},
+	"revokeServiceAccess": {
+		Title: "revoke access to a service",
+		Responses: map[string]string{
+			"200": "Access revoked",
+			"400": "Team not found",
+			"401": "Unauthorized",
+			"404": "Service not found",
+			"409": "Team does not has access to this service",
+		},
+	},
+	"roleInfo": {
+		Title:   "role info",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "OK",
+			"401": "Unauthorized",
+			"404": "Role not found",
+		},
+	},
+	"roleUpdate": {
+		Title: "updates a role",
+		Responses: map[string]string{
+			"200": "Ok",
+			"400": "Invalid data",
+			"401": "Unauthorized",
+		},
+		Form: []string{"contextType", "description", "name", "newName"},
+	},
+	"runCommand": {
+		Title:   "run commands",
+		Consume: "application/x-www-form-urlencoded",
+		Produce: "application/x-json-stream",
+		Responses: map[string]string{
+			"200": "Ok",
+			"401": "Unauthorized",
+			"404": "App not found",
+		},
+		Form: []string{"command", "isolated", "once"},
+	},
+	"samlCallbackLogin": {
+		Title: "saml callback",
+		Responses: map[string]string{
+			"200": "Ok",
+			"400": "Invalid data",
+		},
+		Form: []string{"SAMLResponse"},
+	},
+	"samlMetadata": {
+		Title:   "saml metadata",
+		Produce: "application/xml",
+		Responses: map[string]string{
+			"200": "Ok",
+			"400": "Invalid data",
+		},
+	},
+	"serviceAddDoc": {
+		Title:   "change service documentation",
+		Consume: "application/x-www-form-urlencoded",
+		Responses: map[string]string{
+			"200": "Documentation updated",
+			"401": "Unauthorized",
+			"403": "Forbidden (team is not the owner or service with instances)",
+		},
+		Form: []string{"doc"},
+	},
+	"serviceCreate": {
+		Title:   "service create",
+		Consume: "application/x-www-form-urlencoded",
+		Responses: map[string]string{
+			"201": "Service created",
+			"400": "Invalid data",
+			"401": "Unauthorized",
+			"409": "Service already exists",
+		},
+		Form: []string{"endpoint", "id", "password", "team", "username"},
+	},
+	"serviceDelete": {
+		Title: "service delete",
+		Responses: map[string]string{
+			"200": "Service removed",
+			"401": "Unauthorized",
+			"403": "Forbidden (team is not the owner or service with instances)",
+			"404": "Service not found",
+		},
+	},
+	"serviceDoc": {
+		Title: "service doc",
+		Responses: map[string]string{
+			"200": "OK",
+			"401": "Unauthorized",
+			"404": "Not found",
+		},
+	},
+	"serviceInfo": {
+		Title:   "service info",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "OK",
+		},
+	},
+	"serviceInstance": {
+		Title:   "service instance info",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "OK",
+			"401": "Unauthorized",
+			"404": "Service instance not found",
+		},
+	},
+	"serviceInstanceGrantTeam": {
+		Title:   "grant access to service instance",
+		Consume: "application/x-www-form-urlencoded",
+		Responses: map[string]string{
+			"200": "Access granted",
+			"401": "Unauthorized",
+			"404": "Service instance not found",
+		},
+	},
+	"serviceInstanceProxy": {
+		Title: "service instance proxy",
+		Responses: map[string]string{
+			"401": "Unauthorized",
+			"404": "Instance not found",
+		},
+		Query: []string{"callback"},
+	},
+	"serviceInstanceRevokeTeam": {
+		Title: "revoke access to service instance",
+		Responses: map[string]string{
+			"200": "Access revoked",
+			"401": "Unauthorized",
+			"404": "Service instance not found",
+		},
+	},
+	"serviceInstanceStatus": {
+		Title: "service instance status",
+		Responses: map[string]string{
+			"200": "List services instances",
+			"401": "Unauthorized",
+			"404": "Service instance not found",
+		},
+	},
+	"serviceInstances": {
+		Title:   "service instance list",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "List services instances",
+			"204": "No content",
+			"401": "Unauthorized",
+		},
+		Query: []string{"app"},
+	},
+	"serviceList": {
+		Title:   "service list",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "List services",
+			"204": "No content",
+			"401": "Unauthorized",
+		},
+	},
+	"servicePlans": {
+		Title:   "service plans",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "OK",
+			"401": "Unauthorized",
+			"404": "Service not found",
+		},
+	},
+	"serviceProxy": {
+		Title: "service proxy",
+		Responses: map[string]string{
+			"401": "Unauthorized",
+			"404": "Service not found",
+		},
+		Query: []string{"callback"},
+	},
+	"serviceUpdate": {
+		Title:   "service update",
+		Consume: "application/x-www-form-urlencoded",
+		Responses: map[string]string{
+			"200": "Service updated",
+			"400": "Invalid data",
+			"401": "Unauthorized",
+			"403": "Forbidden (team is not the owner)",
+			"404": "Service not found",
+		},
+		Form: []string{"endpoint", "password", "team", "username"},
+	},
+	"setBuildEnv": {
+		Title:   "set build envs",
+		Consume: "application/x-www-form-urlencoded",
+		Produce: "application/x-json-stream",
+		Responses: map[string]string{
+			"200": "Envs updated",
+			"400": "Invalid data",
+			"401": "Unauthorized",
+			"404": "App not found",
+		},
+	},
+	"setCName": {
+		Title:   "set cname",
+		Consume: "application/x-www-form-urlencoded",
+		Responses: map[string]string{
+			"200": "Ok",
+			"400": "Invalid data",
+			"401": "Unauthorized",
+			"404": "App not found",
+		},
+	},
+	"setCertificate": {
+		Title:   "set app certificate",
+		Consume: "application/x-www-form-urlencoded",
+		Responses: map[string]string{
+			"200": "Ok",
+			"400": "Invalid data",
+			"401": "Unauthorized",
+			"404": "App not found",
+		},
+		Form: []string{"certificate", "cname", "key"},
+	},
+	"setEnv": {
+		Title:   "set envs",
+		Consume: "application/x-www-form-urlencoded",
+		Produce: "application/x-json-stream",
+		Responses: map[string]string{
+			"200": "Envs updated",
+			"400": "Invalid data",
+			"401": "Unauthorized",
+			"404": "App not found",
+		},
+	},
+	"setNodeStatus": {
+		Title:   "set node status",
+		Consume: "application/x-www-form-urlencoded",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "Ok",
+			"400": "Invalid data",
+			"401": "Unauthorized",
+			"404": "App or unit not found",
+		},
+	},
+	"setRestartSchedule": {
+		Title:   "app restart schedule",
+		Consume: "application/x-www-form-urlencoded",
+		Responses: map[string]string{
+			"200": "Ok",
+			"400": "Invalid schedule",
+			"401": "Unauthorized",
+			"404": "App not found",
+		},
+		Form: []string{"schedule"},
+	},
+	"setUnitStatus": {
+		Title:   "set unit status",
+		Consume: "application/x-www-form-urlencoded",
+		Responses: map[string]string{
+			"200": "Ok",
+			"400": "Invalid data",
+			"401": "Unauthorized",
+			"404": "App or unit not found",
+		},
+		Form: []string{"status"},
+	},
+	"showAPIToken": {
+		Title:   "show token",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "OK",
+			"401": "Unauthorized",
+			"404": "User not found",
+		},
+		Query: []string{"user"},
+	},
+	"sleep": {
+		Title:   "app sleep",
+		Consume: "application/x-www-form-urlencoded",
+		Produce: "application/x-json-stream",
+		Responses: map[string]string{
+			"200": "Ok",
+			"400": "Invalid data",
+			"401": "Unauthorized",
+			"404": "App not found",
+		},
+		Form: []string{"process", "proxy"},
+	},
+	"start": {
+		Title:   "app start",
+		Consume: "application/x-www-form-urlencoded",
+		Produce: "application/x-json-stream",
+		Responses: map[string]string{
+			"200": "Ok",
+			"401": "Unauthorized",
+			"404": "App not found",
+		},
+		Form: []string{"process"},
+	},
+	"stop": {
+		Title:   "app stop",
+		Consume: "application/x-www-form-urlencoded",
+		Produce: "application/x-json-stream",
+		Responses: map[string]string{
+			"200": "Ok",
+			"401": "Unauthorized",
+			"404": "App not found",
+		},
+		Form: []string{"process"},
+	},
+	"swap": {
+		Title:   "app swap",
+		Consume: "application/x-www-form-urlencoded",
+		Responses: map[string]string{
+			"200": "Ok",
+			"400": "Invalid data",
+			"401": "Unauthorized",
+			"404": "App not found",
+			"409": "App locked",
+			"412": "Number of units or platform don't match",
+		},
+		Form: []string{"app1", "app2", "cnameOnly", "force"},
+	},
+	"symbolHandler": {
+		Title: "profile symbol handler",
+		Responses: map[string]string{
+			"200": "Ok",
+			"401": "Unauthorized",
+		},
+	},
+	"teamInfo": {
+		Title:   "team info",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "Info team",
+			"401": "Unauthorized",
+			"404": "Not found",
+		},
+	},
+	"teamList": {
+		Title:   "team list",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "List teams",
+			"204": "No content",
+			"401": "Unauthorized",
+		},
+	},
+	"templateCreate": {
+		Title:   "template create",
+		Consume: "application/x-www-form-urlencoded",
+		Responses: map[string]string{
+			"201": "Template created",
+			"400": "Invalid data",
+			"401": "Unauthorized",
+			"409": "Existent template",
+		},
+	},
+	"templateDestroy": {
+		Title: "template destroy",
+		Responses: map[string]string{
+			"200": "OK",
+			"401": "Unauthorized",
+			"404": "Not found",
+		},
+	},
+	"templateUpdate": {
+		Title:   "template update",
+		Consume: "application/x-www-form-urlencoded",
+		Responses: map[string]string{
+			"200": "OK",
+			"400": "Invalid data",
+			"401": "Unauthorized",
+			"404": "Not found",
+		},
+		Form: []string{"IaaSName"},
+	},
+	"templatesList": {
+		Title:   "machine template list",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "OK",
+			"401": "Unauthorized",
+		},
+	},
+	"traceHandler": {
+		Title: "profile trace handler",
+		Responses: map[string]string{
+			"200": "Ok",
+			"401": "Unauthorized",
+		},
+	},
+	"unbindServiceInstance": {
+		Title:   "unbind service instance",
+		Produce: "application/x-json-stream",
+		Responses: map[string]string{
+			"200": "Ok",
+			"400": "Invalid data",
+			"401": "Unauthorized",
+			"404": "App not found",
+		},
+		Query: []string{"noRestart"},
+	},
+	"unsetBuildEnv": {
+		Title:   "unset build envs",
+		Produce: "application/x-json-stream",
+		Responses: map[string]string{
+			"200": "Envs removed",
+			"400": "Invalid data",
+			"401": "Unauthorized",
+			"404": "App not found",
+		},
+		Query: []string{"env"},
+	},
+	"unsetCName": {
+		Title: "unset cname",
+		Responses: map[string]string{
+			"200": "Ok",
+			"400": "Invalid data",
+			"401": "Unauthorized",
+			"404": "App not found",
+		},
+	},
+	"unsetCertificate": {
+		Title:   "unset app certificate",
+		Consume: "application/x-www-form-urlencoded",
+		Responses: map[string]string{
+			"200": "Ok",
+			"400": "Invalid data",
+			"401": "Unauthorized",
+			"404": "App not found",
+		},
+		Query: []string{"cname"},
+	},
+	"unsetEnv": {
+		Title:   "unset envs",
+		Produce: "application/x-json-stream",
+		Responses: map[string]string{
+			"200": "Envs removed",
+			"400": "Invalid data",
+			"401": "Unauthorized",
+			"404": "App not found",
+		},
+		Query: []string{"env", "noRestart"},
+	},
+	"updateApp": {
+		Title:   "app update",
+		Consume: "application/x-www-form-urlencoded",
+		Produce: "application/x-json-stream",
+		Responses: map[string]string{
+			"200": "App updated",
+			"400": "Invalid new pool",
+			"401": "Unauthorized",
+			"404": "Not found",
+		},
+		Form: []string{"imageReset", "platform"},
+	},
+	"updateAppRouter": {
+		Title:   "update app router",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "OK",
+			"400": "Invalid request",
+			"404": "App or router not found",
+		},
+	},
+	"updateCluster": {
+		Title:   "update provisioner cluster",
+		Consume: "application/x-www-form-urlencoded",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "Ok",
+			"400": "Invalid data",
+			"401": "Unauthorized",
+			"404": "Cluster not found",
+		},
+	},
+	"updateNodeHandler": {
+		Title:   "update nodes",
+		Consume: "application/x-www-form-urlencoded",
+		Responses: map[string]string{
+			"200": "Ok",
+			"400": "Invalid data",
+			"401": "Unauthorized",
+			"404": "Not found",
+		},
+	},
+	"updateServiceInstance": {
+		Title:   "service instance update",
+		Consume: "application/x-www-form-urlencoded",
+		Responses: map[string]string{
+			"200": "Service instance updated",
+			"400": "Invalid data",
+			"401": "Unauthorized",
+			"404": "Service instance not found",
+		},
+		Form: []string{"description", "plan", "teamowner"},
+	},
+	"updateTeam": {
+		Title:   "team update",
+		Consume: "application/x-www-form-urlencoded",
+		Responses: map[string]string{
+			"200": "Team updated",
+			"400": "Invalid data",
+			"401": "Unauthorized",
+			"404": "Team not found",
+		},
+	},
+	"userInfo": {
+		Title:   "user info",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "OK",
+			"401": "Unauthorized",
+		},
+	},
+	"volumeBind": {
+		Title:   "volume bind",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "Volume binded",
+			"401": "Unauthorized",
+			"404": "Volume not found",
+			"409": "Volume bind already exists",
+		},
+	},
+	"volumeCreate": {
+		Title:   "volume create",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"201": "Volume created",
+			"401": "Unauthorized",
+			"409": "Volume already exists",
+		},
+	},
+	"volumeDelete": {
+		Title:   "volume delete",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "Volume deleted",
+			"401": "Unauthorized",
+			"404": "Volume not found",
+		},
+	},
+	"volumeInfo": {
+		Title:   "volume info",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "Show volume",
+			"401": "Unauthorized",
+			"404": "Volume not found",
+		},
+	},
+	"volumePlansList": {
+		Title:   "volume plan list",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "List volume plans",
+			"401": "Unauthorized",
+		},
+	},
+	"volumeUnbind": {
+		Title:   "volume unbind",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "Volume unbinded",
+			"401": "Unauthorized",
+			"404": "Volume not found",
+		},
+	},
+	"volumeUpdate": {
+		Title:   "volume update",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "Volume updated",
+			"401": "Unauthorized",
+			"404": "Volume not found",
+		},
+	},
+	"volumesList": {
+		Title:   "volume list",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "List volumes",
+			"204": "No content",
+			"401": "Unauthorized",
+		},
+	},
+	"webhookCreate": {
+		Title:   "webhook create",
+		Consume: "application/x-www-form-urlencoded",
+		Responses: map[string]string{
+			"200": "OK",
+			"400": "Invalid data",
+			"401": "Unauthorized",
+			"409": "Webhook already exists",
+		},
+		Form: []string{"error-only", "name", "secret", "success-only", "team", "url"},
+	},
+	"webhookDelete": {
+		Title: "webhook delete",
+		Responses: map[string]string{
+			"200": "OK",
+			"401": "Unauthorized",
+			"404": "Not found",
+		},
+	},
+	"webhookInfo": {
+		Title:   "webhook info",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "OK",
+			"401": "Unauthorized",
+			"404": "Not found",
+		},
+	},
+	"webhookList": {
+		Title:   "webhook list",
+		Produce: "application/json",
+		Responses: map[string]string{
+			"200": "OK",
+			"204": "No content",
+			"401": "Unauthorized",
+		},
+	},
+}
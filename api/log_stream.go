@@ -0,0 +1,135 @@
+// Copyright 2018 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/tsuru/tsuru/api/context"
+	"github.com/tsuru/tsuru/app"
+	"github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/permission"
+)
+
+// title: app log stream
+// path: /apps/{appname}/log/stream
+// method: GET
+// produce: Websocket connection upgrade
+// responses:
+//   101: Switch Protocol to websocket
+func appLogStream(w http.ResponseWriter, r *http.Request) {
+	ws, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		fmt.Fprintf(w, "unable to upgrade ws connection: %v", err)
+		return
+	}
+	var httpErr *errors.HTTP
+	defer func() {
+		if httpErr != nil {
+			ws.WriteMessage(websocket.TextMessage, []byte("Error: "+httpErr.Message+"\n"))
+		}
+		ws.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+		ws.Close()
+	}()
+	token := context.GetAuthToken(r)
+	if token == nil {
+		httpErr = &errors.HTTP{
+			Code:    http.StatusUnauthorized,
+			Message: "no token provided or session expired, please login again",
+		}
+		return
+	}
+	query := r.URL.Query()
+	var lines int
+	if l := query.Get("lines"); l != "" {
+		lines, err = strconv.Atoi(l)
+		if err != nil || lines < 0 {
+			httpErr = &errors.HTTP{Code: http.StatusBadRequest, Message: `Parameter "lines" must be a non negative integer.`}
+			return
+		}
+	}
+	follow := true
+	if f := query.Get("follow"); f != "" {
+		follow, err = strconv.ParseBool(f)
+		if err != nil {
+			httpErr = &errors.HTTP{Code: http.StatusBadRequest, Message: `Parameter "follow" must be a boolean.`}
+			return
+		}
+	}
+	a, err := getAppFromContext(query.Get(":appname"), r)
+	if err != nil {
+		if herr, ok := err.(*errors.HTTP); ok {
+			httpErr = herr
+		} else {
+			httpErr = &errors.HTTP{Code: http.StatusInternalServerError, Message: err.Error()}
+		}
+		return
+	}
+	if !permission.Check(token, permission.PermAppReadLog, contextsForApp(&a)...) {
+		httpErr = permission.ErrUnauthorized
+		return
+	}
+	filterLog := app.Applog{Source: query.Get("source"), Unit: query.Get("unit")}
+	if lines > 0 {
+		logs, err := a.LastLogs(lines, filterLog)
+		if err != nil {
+			httpErr = &errors.HTTP{Code: http.StatusInternalServerError, Message: err.Error()}
+			return
+		}
+		for _, msg := range logs {
+			if ws.WriteJSON(msg) != nil {
+				return
+			}
+		}
+	}
+	if !follow {
+		return
+	}
+	l, err := app.NewLogListener(&a, filterLog)
+	if err != nil {
+		httpErr = &errors.HTTP{Code: http.StatusInternalServerError, Message: err.Error()}
+		return
+	}
+	logTracker.add(l)
+	defer func() {
+		logTracker.remove(l)
+		l.Close()
+	}()
+	ws.SetReadDeadline(time.Now().Add(pongWait))
+	ws.SetPongHandler(func(string) error {
+		ws.SetReadDeadline(time.Now().Add(pongWait))
+		return nil
+	})
+	// Incoming messages are discarded, reading is needed to handle pongs and
+	// to notice when the client goes away.
+	clientGone := make(chan struct{})
+	go func() {
+		defer close(clientGone)
+		for {
+			if _, _, err := ws.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+	ping := time.NewTicker(pingInterval)
+	defer ping.Stop()
+	logChan := l.ListenChan()
+	for {
+		select {
+		case <-clientGone:
+			return
+		case <-ping.C:
+			ws.WriteControl(websocket.PingMessage, nil, time.Now().Add(2*time.Second))
+		case msg, ok := <-logChan:
+			if !ok || ws.WriteJSON(msg) != nil {
+				return
+			}
+		}
+	}
+}
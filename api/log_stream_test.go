@@ -0,0 +1,125 @@
+// Copyright 2018 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/tsuru/tsuru/app"
+	"github.com/tsuru/tsuru/auth"
+	"github.com/tsuru/tsuru/permission"
+	"gopkg.in/check.v1"
+)
+
+func (s *S) dialLogStream(c *check.C, server *httptest.Server, path string, token auth.Token) *websocket.Conn {
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + path
+	header := http.Header{}
+	if token != nil {
+		header.Set("Authorization", "bearer "+token.GetValue())
+	}
+	conn, _, err := websocket.DefaultDialer.Dial(url, header)
+	c.Assert(err, check.IsNil)
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	return conn
+}
+
+func (s *S) TestAppLogStreamPreviousLogs(c *check.C) {
+	a := app.App{Name: "lost1", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(&a, s.user)
+	c.Assert(err, check.IsNil)
+	err = a.Log("first", "web", "unit1")
+	c.Assert(err, check.IsNil)
+	err = a.Log("second", "worker", "unit1")
+	c.Assert(err, check.IsNil)
+	server := httptest.NewServer(s.testServer)
+	defer server.Close()
+	conn := s.dialLogStream(c, server, fmt.Sprintf("/apps/%s/log/stream?lines=10&follow=0&source=web", a.Name), s.token)
+	defer conn.Close()
+	var msg app.Applog
+	err = conn.ReadJSON(&msg)
+	c.Assert(err, check.IsNil)
+	c.Assert(msg.Message, check.Equals, "first")
+	c.Assert(msg.Source, check.Equals, "web")
+	_, _, err = conn.ReadMessage()
+	c.Assert(websocket.IsCloseError(err, websocket.CloseNormalClosure), check.Equals, true)
+}
+
+func (s *S) TestAppLogStreamFollow(c *check.C) {
+	a := app.App{Name: "lost1", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(&a, s.user)
+	c.Assert(err, check.IsNil)
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermAppReadLog,
+		Context: permission.Context(permission.CtxTeam, s.team.Name),
+	})
+	server := httptest.NewServer(s.testServer)
+	defer server.Close()
+	conn := s.dialLogStream(c, server, fmt.Sprintf("/apps/%s/log/stream?unit=unit1", a.Name), token)
+	defer conn.Close()
+	var listener *app.LogListener
+	timeout := time.After(5 * time.Second)
+	for listener == nil {
+		select {
+		case <-timeout:
+			c.Fatal("timeout after 5 seconds")
+		case <-time.After(50 * time.Millisecond):
+		}
+		logTracker.Lock()
+		for listener = range logTracker.conn {
+		}
+		logTracker.Unlock()
+	}
+	err = a.Log("other unit", "web", "unit2")
+	c.Assert(err, check.IsNil)
+	err = a.Log("x", "web", "unit1")
+	c.Assert(err, check.IsNil)
+	var msg app.Applog
+	err = conn.ReadJSON(&msg)
+	c.Assert(err, check.IsNil)
+	c.Assert(msg.Message, check.Equals, "x")
+	c.Assert(msg.Unit, check.Equals, "unit1")
+}
+
+func (s *S) TestAppLogStreamUnauthorized(c *check.C) {
+	a := app.App{Name: "lost1", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(&a, s.user)
+	c.Assert(err, check.IsNil)
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermAppReadLog,
+		Context: permission.Context(permission.CtxTeam, "otherteam"),
+	})
+	server := httptest.NewServer(s.testServer)
+	defer server.Close()
+	conn := s.dialLogStream(c, server, fmt.Sprintf("/apps/%s/log/stream", a.Name), token)
+	defer conn.Close()
+	_, data, err := conn.ReadMessage()
+	c.Assert(err, check.IsNil)
+	c.Assert(string(data), check.Equals, "Error: You don't have permission to do this action\n")
+}
+
+func (s *S) TestAppLogStreamNoToken(c *check.C) {
+	server := httptest.NewServer(s.testServer)
+	defer server.Close()
+	conn := s.dialLogStream(c, server, "/apps/lost1/log/stream", nil)
+	defer conn.Close()
+	_, data, err := conn.ReadMessage()
+	c.Assert(err, check.IsNil)
+	c.Assert(string(data), check.Equals, "Error: no token provided or session expired, please login again\n")
+}
+
+func (s *S) TestAppLogStreamInvalidLines(c *check.C) {
+	server := httptest.NewServer(s.testServer)
+	defer server.Close()
+	conn := s.dialLogStream(c, server, "/apps/lost1/log/stream?lines=abc", s.token)
+	defer conn.Close()
+	_, data, err := conn.ReadMessage()
+	c.Assert(err, check.IsNil)
+	c.Assert(string(data), check.Equals, "Error: Parameter \"lines\" must be a non negative integer.\n")
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/tsuru/config"
 	"github.com/tsuru/tsuru/api/context"
 	"github.com/tsuru/tsuru/app"
+	"github.com/tsuru/tsuru/audit"
 	"github.com/tsuru/tsuru/auth"
 	"github.com/tsuru/tsuru/cmd"
 	tsuruErrors "github.com/tsuru/tsuru/errors"
@@ -74,6 +75,49 @@ func flushingWriterMiddleware(w http.ResponseWriter, r *http.Request, next http.
 	next(&fw, r)
 }
 
+// auditMiddleware records the authenticated requests that may change the
+// state of tsuru in the audit log, once they're finished.
+func auditMiddleware(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	next(w, r)
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return
+	}
+	t := context.GetAuthToken(r)
+	if t == nil {
+		return
+	}
+	entry := audit.Entry{
+		User:   t.GetUserName(),
+		Method: r.Method,
+		Path:   r.URL.Path,
+		App:    r.URL.Query().Get(":app"),
+	}
+	// Deploy and quota routes name the app parameter ":appname".
+	if entry.App == "" {
+		entry.App = r.URL.Query().Get(":appname")
+	}
+	if t.IsAppToken() {
+		entry.User = t.GetAppName()
+		entry.AppToken = true
+	}
+	if rw, ok := w.(negroni.ResponseWriter); ok {
+		entry.StatusCode = rw.Status()
+	}
+	if entry.StatusCode == 0 {
+		entry.StatusCode = http.StatusOK
+	}
+	if err := context.GetRequestError(r); err != nil {
+		entry.Error = err.Error()
+	}
+	if requestIDHeader, _ := config.GetString("request-id-header"); requestIDHeader != "" {
+		entry.RequestID = context.GetRequestID(r, requestIDHeader)
+	}
+	if err := audit.Add(&entry); err != nil {
+		log.Errorf("unable to record audit entry for %s %s: %s", r.Method, r.URL.Path, err)
+	}
+}
+
 func setRequestIDHeaderMiddleware(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
 	requestIDHeader, _ := config.GetString("request-id-header")
 	if requestIDHeader == "" {
@@ -0,0 +1,215 @@
+// Copyright 2018 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"runtime"
+	"strings"
+
+	apiRouter "github.com/tsuru/tsuru/api/router"
+)
+
+//go:generate bash -c "rm -f handlerdocs.go && go run ./generator/main.go -o handlerdocs.go"
+
+var pathParamRegexp = regexp.MustCompile(`\{([^}:]+)(?::[^}]*)?\}`)
+
+type openAPIDocument struct {
+	OpenAPI    string                                 `json:"openapi"`
+	Info       openAPIInfo                            `json:"info"`
+	Paths      map[string]map[string]openAPIOperation `json:"paths"`
+	Components openAPIComponents                      `json:"components"`
+}
+
+type openAPIInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type openAPIOperation struct {
+	OperationID  string                     `json:"operationId,omitempty"`
+	Summary      string                     `json:"summary,omitempty"`
+	Parameters   []openAPIParameter         `json:"parameters,omitempty"`
+	RequestBody  *openAPIRequestBody        `json:"requestBody,omitempty"`
+	Security     []map[string][]string      `json:"security,omitempty"`
+	Responses    map[string]openAPIResponse `json:"responses"`
+	TsuruVersion string                     `json:"x-tsuru-version"`
+}
+
+type openAPIParameter struct {
+	Name     string            `json:"name"`
+	In       string            `json:"in"`
+	Required bool              `json:"required"`
+	Schema   map[string]string `json:"schema"`
+}
+
+type openAPIRequestBody struct {
+	Content map[string]openAPIMediaType `json:"content"`
+}
+
+type openAPIMediaType struct {
+	Schema *openAPISchema `json:"schema,omitempty"`
+}
+
+type openAPISchema struct {
+	Type       string                       `json:"type"`
+	Properties map[string]map[string]string `json:"properties,omitempty"`
+}
+
+type openAPIResponse struct {
+	Description string                      `json:"description"`
+	Content     map[string]openAPIMediaType `json:"content,omitempty"`
+}
+
+// handlerDoc is the documentation of a handler, taken from its doc comment
+// by the generator in api/generator. Query and Form hold the fields the
+// handler reads from the request by name, fields decoded into structs are
+// not listed.
+type handlerDoc struct {
+	Title     string
+	Consume   string
+	Produce   string
+	Responses map[string]string
+	Query     []string
+	Form      []string
+}
+
+type openAPIComponents struct {
+	SecuritySchemes map[string]map[string]string `json:"securitySchemes"`
+}
+
+// title: api schema
+// path: /openapi.json
+// method: GET
+// produce: application/json
+// responses:
+//   200: OK
+func apiSchema(m *apiRouter.DelayedRouter) Handler {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		w.Header().Set("Content-Type", "application/json")
+		return json.NewEncoder(w).Encode(buildOpenAPIDocument(m.Routes()))
+	}
+}
+
+// buildOpenAPIDocument describes the given routes using the OpenAPI 3
+// format. When the same path and method are registered more than once, the
+// first route is used, as it's the one serving requests without version.
+func buildOpenAPIDocument(routes []apiRouter.RouteInfo) *openAPIDocument {
+	doc := &openAPIDocument{
+		OpenAPI: "3.0.0",
+		Info:    openAPIInfo{Title: "tsuru API", Version: Version},
+		Paths:   map[string]map[string]openAPIOperation{},
+		Components: openAPIComponents{
+			SecuritySchemes: map[string]map[string]string{
+				"bearer": {"type": "http", "scheme": "bearer"},
+			},
+		},
+	}
+	usedIDs := map[string]bool{}
+	for _, route := range routes {
+		path := pathParamRegexp.ReplaceAllString(route.Path, "{$1}")
+		item := doc.Paths[path]
+		if item == nil {
+			item = map[string]openAPIOperation{}
+			doc.Paths[path] = item
+		}
+		for _, method := range route.Methods {
+			method = strings.ToLower(method)
+			if _, ok := item[method]; ok {
+				continue
+			}
+			op := openAPIOperation{
+				Responses:    map[string]openAPIResponse{"default": {Description: "tsuru API response"}},
+				TsuruVersion: route.Version,
+			}
+			for _, match := range pathParamRegexp.FindAllStringSubmatch(route.Path, -1) {
+				op.Parameters = append(op.Parameters, openAPIParameter{
+					Name:     match[1],
+					In:       "path",
+					Required: true,
+					Schema:   map[string]string{"type": "string"},
+				})
+			}
+			if _, ok := route.Handler.(AuthorizationRequiredHandler); ok {
+				op.Security = []map[string][]string{{"bearer": {}}}
+			}
+			if name := handlerName(route.Handler); name != "" {
+				op.OperationID = name
+				for i := 2; usedIDs[op.OperationID]; i++ {
+					op.OperationID = fmt.Sprintf("%s%d", name, i)
+				}
+				usedIDs[op.OperationID] = true
+				if hDoc, ok := handlerDocs[name]; ok {
+					describeOperation(&op, hDoc)
+				}
+			}
+			item[method] = op
+		}
+	}
+	return doc
+}
+
+// describeOperation fills the operation with the summary, request fields
+// and responses documented for its handler.
+func describeOperation(op *openAPIOperation, hDoc handlerDoc) {
+	op.Summary = hDoc.Title
+	for _, name := range hDoc.Query {
+		op.Parameters = append(op.Parameters, openAPIParameter{
+			Name:   name,
+			In:     "query",
+			Schema: map[string]string{"type": "string"},
+		})
+	}
+	if len(hDoc.Form) > 0 || hDoc.Consume != "" {
+		consume := hDoc.Consume
+		if consume == "" {
+			consume = "application/x-www-form-urlencoded"
+		}
+		var schema *openAPISchema
+		if len(hDoc.Form) > 0 {
+			schema = &openAPISchema{Type: "object", Properties: map[string]map[string]string{}}
+			for _, name := range hDoc.Form {
+				schema.Properties[name] = map[string]string{"type": "string"}
+			}
+		}
+		op.RequestBody = &openAPIRequestBody{
+			Content: map[string]openAPIMediaType{consume: {Schema: schema}},
+		}
+	}
+	if len(hDoc.Responses) == 0 {
+		return
+	}
+	op.Responses = map[string]openAPIResponse{}
+	for code, description := range hDoc.Responses {
+		response := openAPIResponse{Description: description}
+		if hDoc.Produce != "" && strings.HasPrefix(code, "2") {
+			response.Content = map[string]openAPIMediaType{hDoc.Produce: {}}
+		}
+		op.Responses[code] = response
+	}
+}
+
+// handlerName returns the name of the function implementing the handler,
+// without its package, or an empty string for non-function handlers.
+func handlerName(h http.Handler) string {
+	value := reflect.ValueOf(h)
+	if value.Kind() != reflect.Func {
+		return ""
+	}
+	fn := runtime.FuncForPC(value.Pointer())
+	if fn == nil {
+		return ""
+	}
+	name := fn.Name()
+	name = name[strings.LastIndex(name, ".")+1:]
+	if strings.HasPrefix(name, "func") {
+		return ""
+	}
+	return name
+}
@@ -0,0 +1,75 @@
+// Copyright 2018 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	"gopkg.in/check.v1"
+)
+
+func (s *S) TestAPISchema(c *check.C) {
+	request, err := http.NewRequest("GET", "/openapi.json", nil)
+	c.Assert(err, check.IsNil)
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(recorder.Header().Get("Content-Type"), check.Equals, "application/json")
+	var doc openAPIDocument
+	err = json.NewDecoder(recorder.Body).Decode(&doc)
+	c.Assert(err, check.IsNil)
+	c.Assert(doc.OpenAPI, check.Equals, "3.0.0")
+	c.Assert(doc.Info.Version, check.Equals, Version)
+	op, ok := doc.Paths["/apps/{app}"]["get"]
+	c.Assert(ok, check.Equals, true)
+	c.Assert(op.OperationID, check.Equals, "appInfo")
+	c.Assert(op.TsuruVersion, check.Equals, "1.0")
+	c.Assert(op.Security, check.DeepEquals, []map[string][]string{{"bearer": {}}})
+	c.Assert(op.Parameters, check.DeepEquals, []openAPIParameter{
+		{Name: "app", In: "path", Required: true, Schema: map[string]string{"type": "string"}},
+	})
+	c.Assert(op.Summary, check.Equals, "app info")
+	c.Assert(op.Responses, check.DeepEquals, map[string]openAPIResponse{
+		"200": {Description: "OK", Content: map[string]openAPIMediaType{"application/json": {}}},
+		"401": {Description: "Unauthorized"},
+		"404": {Description: "Not found"},
+	})
+	op, ok = doc.Paths["/apps"]["get"]
+	c.Assert(ok, check.Equals, true)
+	c.Assert(op.RequestBody, check.IsNil)
+	c.Assert(op.Parameters, check.HasLen, 8)
+	c.Assert(op.Parameters[0], check.DeepEquals, openAPIParameter{
+		Name: "locked", In: "query", Schema: map[string]string{"type": "string"},
+	})
+	op, ok = doc.Paths["/users/keys"]["post"]
+	c.Assert(ok, check.Equals, true)
+	c.Assert(op.Parameters, check.IsNil)
+	c.Assert(op.RequestBody, check.DeepEquals, &openAPIRequestBody{
+		Content: map[string]openAPIMediaType{
+			"application/x-www-form-urlencoded": {Schema: &openAPISchema{
+				Type: "object",
+				Properties: map[string]map[string]string{
+					"force": {"type": "string"},
+					"key":   {"type": "string"},
+					"name":  {"type": "string"},
+				},
+			}},
+		},
+	})
+	op, ok = doc.Paths["/info"]["get"]
+	c.Assert(ok, check.Equals, true)
+	c.Assert(op.OperationID, check.Equals, "info")
+	c.Assert(op.Security, check.IsNil)
+	op, ok = doc.Paths["/openapi.json"]["get"]
+	c.Assert(ok, check.Equals, true)
+	c.Assert(op.Responses, check.DeepEquals, map[string]openAPIResponse{
+		"default": {Description: "tsuru API response"},
+	})
+	op, ok = doc.Paths["/node/{address}"]["get"]
+	c.Assert(ok, check.Equals, true)
+	c.Assert(op.Parameters[0].Name, check.Equals, "address")
+}
@@ -0,0 +1,34 @@
+// Copyright 2018 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/tsuru/tsuru/errors"
+)
+
+// totalCountHeader holds the number of items available in paginated lists.
+const totalCountHeader = "X-Total-Count"
+
+// paginationParams returns the values of the limit and skip query string
+// parameters. A zero limit means the client did not ask for pagination.
+func paginationParams(r *http.Request) (limit, skip int, err error) {
+	query := r.URL.Query()
+	if v := query.Get("limit"); v != "" {
+		limit, err = strconv.Atoi(v)
+		if err != nil || limit < 0 {
+			return 0, 0, &errors.HTTP{Code: http.StatusBadRequest, Message: `Parameter "limit" must be a non negative integer.`}
+		}
+	}
+	if v := query.Get("skip"); v != "" {
+		skip, err = strconv.Atoi(v)
+		if err != nil || skip < 0 {
+			return 0, 0, &errors.HTTP{Code: http.StatusBadRequest, Message: `Parameter "skip" must be a non negative integer.`}
+		}
+	}
+	return limit, skip, nil
+}
@@ -0,0 +1,179 @@
+// Copyright 2018 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/tsuru/monsterqueue"
+	"github.com/tsuru/tsuru/auth"
+	"github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/queue"
+)
+
+type queueJob struct {
+	ID       string
+	Task     string
+	Params   monsterqueue.JobParams
+	State    string
+	Enqueued time.Time
+	Started  time.Time
+	Done     time.Time
+	Error    string `json:",omitempty"`
+}
+
+func newQueueJob(j monsterqueue.Job) queueJob {
+	status := j.Status()
+	job := queueJob{
+		ID:       j.ID(),
+		Task:     j.TaskName(),
+		Params:   j.Parameters(),
+		State:    status.State,
+		Enqueued: status.Enqueued,
+		Started:  status.Started,
+		Done:     status.Done,
+	}
+	if status.State == monsterqueue.JobStateDone {
+		if _, err := j.Result(); err != nil {
+			job.Error = err.Error()
+		}
+	}
+	return job
+}
+
+func queueJobFilter(r *http.Request) queue.JobFilter {
+	limit, _ := strconv.Atoi(r.FormValue("limit"))
+	return queue.JobFilter{
+		Task:  r.FormValue("task"),
+		State: r.FormValue("state"),
+		Limit: limit,
+	}
+}
+
+// title: queue stats
+// path: /queue/stats
+// method: GET
+// produce: application/json
+// responses:
+//   200: OK
+//   204: No content
+//   401: Unauthorized
+func queueStats(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	if !permission.Check(t, permission.PermQueueRead) {
+		return permission.ErrUnauthorized
+	}
+	stats, err := queue.Stats()
+	if err != nil {
+		return err
+	}
+	if len(stats) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(stats)
+}
+
+// title: queue job list
+// path: /queue/jobs
+// method: GET
+// produce: application/json
+// responses:
+//   200: OK
+//   204: No content
+//   401: Unauthorized
+func queueJobList(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	if !permission.Check(t, permission.PermQueueRead) {
+		return permission.ErrUnauthorized
+	}
+	jobs, err := queue.FindJobs(queueJobFilter(r))
+	if err != nil {
+		return err
+	}
+	if len(jobs) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	}
+	result := make([]queueJob, len(jobs))
+	for i, j := range jobs {
+		result[i] = newQueueJob(j)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(result)
+}
+
+// title: queue job retry
+// path: /queue/jobs/{id}/retry
+// method: POST
+// produce: application/json
+// responses:
+//   200: OK
+//   400: Job not failed
+//   401: Unauthorized
+//   404: Job not found
+func queueJobRetry(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	if !permission.Check(t, permission.PermQueueUpdateRetry) {
+		return permission.ErrUnauthorized
+	}
+	jobID := r.URL.Query().Get(":id")
+	evt, err := event.New(&event.Opts{
+		Target:     event.Target{Type: event.TargetTypeQueueJob, Value: jobID},
+		Kind:       permission.PermQueueUpdateRetry,
+		Owner:      t,
+		CustomData: event.FormToCustomData(r.Form),
+		Allowed:    event.Allowed(permission.PermQueueReadEvents),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(err) }()
+	job, err := queue.RetryJob(jobID)
+	if err == monsterqueue.ErrNoSuchJob {
+		return &errors.HTTP{Code: http.StatusNotFound, Message: err.Error()}
+	}
+	if err == queue.ErrJobNotFailed {
+		return &errors.HTTP{Code: http.StatusBadRequest, Message: err.Error()}
+	}
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(newQueueJob(job))
+}
+
+// title: queue job purge
+// path: /queue/jobs
+// method: DELETE
+// responses:
+//   200: OK
+//   401: Unauthorized
+func queueJobPurge(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	if !permission.Check(t, permission.PermQueueDelete) {
+		return permission.ErrUnauthorized
+	}
+	filter := queueJobFilter(r)
+	evt, err := event.New(&event.Opts{
+		Target:     event.Target{Type: event.TargetTypeQueueJob, Value: filter.Task},
+		Kind:       permission.PermQueueDelete,
+		Owner:      t,
+		CustomData: event.FormToCustomData(r.Form),
+		Allowed:    event.Allowed(permission.PermQueueReadEvents),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(err) }()
+	removed, err := queue.RemoveDoneJobs(filter)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(map[string]int{"removed": removed})
+}
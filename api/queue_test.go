@@ -0,0 +1,200 @@
+// Copyright 2018 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/tsuru/config"
+	"github.com/tsuru/monsterqueue"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/event/eventtest"
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/queue"
+	check "gopkg.in/check.v1"
+)
+
+type queueTestTask struct {
+	fail bool
+}
+
+func (t *queueTestTask) Run(j monsterqueue.Job) {
+	if t.fail {
+		j.Error(errors.New("task failed"))
+		return
+	}
+	j.Success(nil)
+}
+
+func (t *queueTestTask) Name() string {
+	if t.fail {
+		return "failing-task"
+	}
+	return "ok-task"
+}
+
+func (s *S) setupMemoryQueue(c *check.C) monsterqueue.Queue {
+	config.Set("queue:driver", "memory")
+	queue.ResetQueue()
+	q, err := queue.Queue()
+	c.Assert(err, check.IsNil)
+	err = q.RegisterTask(&queueTestTask{})
+	c.Assert(err, check.IsNil)
+	err = q.RegisterTask(&queueTestTask{fail: true})
+	c.Assert(err, check.IsNil)
+	return q
+}
+
+func (s *S) TestQueueStats(c *check.C) {
+	defer config.Unset("queue:driver")
+	q := s.setupMemoryQueue(c)
+	_, err := q.EnqueueWait("ok-task", nil, time.Minute)
+	c.Assert(err, check.IsNil)
+	_, err = q.EnqueueWait("failing-task", nil, time.Minute)
+	c.Assert(err, check.IsNil)
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermQueueRead,
+		Context: permission.Context(permission.CtxGlobal, ""),
+	})
+	request, err := http.NewRequest("GET", "/queue/stats", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(recorder.Header().Get("Content-Type"), check.Equals, "application/json")
+	var stats []queue.TaskStats
+	err = json.Unmarshal(recorder.Body.Bytes(), &stats)
+	c.Assert(err, check.IsNil)
+	c.Assert(stats, check.DeepEquals, []queue.TaskStats{
+		{Task: "failing-task", Failed: 1},
+		{Task: "ok-task", Done: 1},
+	})
+}
+
+func (s *S) TestQueueStatsNoContent(c *check.C) {
+	defer config.Unset("queue:driver")
+	s.setupMemoryQueue(c)
+	request, err := http.NewRequest("GET", "/queue/stats", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusNoContent)
+}
+
+func (s *S) TestQueueStatsWithoutPermission(c *check.C) {
+	token := userWithPermission(c)
+	request, err := http.NewRequest("GET", "/queue/stats", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+}
+
+func (s *S) TestQueueJobList(c *check.C) {
+	defer config.Unset("queue:driver")
+	q := s.setupMemoryQueue(c)
+	_, err := q.EnqueueWait("ok-task", monsterqueue.JobParams{"app": "myapp"}, time.Minute)
+	c.Assert(err, check.IsNil)
+	failed, err := q.EnqueueWait("failing-task", nil, time.Minute)
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest("GET", "/queue/jobs?state=done&task=failing-task", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	var jobs []queueJob
+	err = json.Unmarshal(recorder.Body.Bytes(), &jobs)
+	c.Assert(err, check.IsNil)
+	c.Assert(jobs, check.HasLen, 1)
+	c.Assert(jobs[0].ID, check.Equals, failed.ID())
+	c.Assert(jobs[0].Task, check.Equals, "failing-task")
+	c.Assert(jobs[0].State, check.Equals, monsterqueue.JobStateDone)
+	c.Assert(jobs[0].Error, check.Equals, "task failed")
+}
+
+func (s *S) TestQueueJobRetry(c *check.C) {
+	defer config.Unset("queue:driver")
+	q := s.setupMemoryQueue(c)
+	failed, err := q.EnqueueWait("failing-task", monsterqueue.JobParams{"app": "myapp"}, time.Minute)
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest("POST", "/queue/jobs/"+failed.ID()+"/retry", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	var job queueJob
+	err = json.Unmarshal(recorder.Body.Bytes(), &job)
+	c.Assert(err, check.IsNil)
+	c.Assert(job.ID, check.Not(check.Equals), failed.ID())
+	c.Assert(job.Task, check.Equals, "failing-task")
+	c.Assert(job.Params, check.DeepEquals, monsterqueue.JobParams{"app": "myapp"})
+	c.Assert(eventtest.EventDesc{
+		Target: event.Target{Type: event.TargetTypeQueueJob, Value: failed.ID()},
+		Owner:  s.token.GetUserName(),
+		Kind:   "queue.update.retry",
+	}, eventtest.HasEvent)
+}
+
+func (s *S) TestQueueJobRetryNotFailed(c *check.C) {
+	defer config.Unset("queue:driver")
+	q := s.setupMemoryQueue(c)
+	j, err := q.EnqueueWait("ok-task", nil, time.Minute)
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest("POST", "/queue/jobs/"+j.ID()+"/retry", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+	c.Assert(recorder.Body.String(), check.Equals, queue.ErrJobNotFailed.Error()+"\n")
+}
+
+func (s *S) TestQueueJobRetryNotFound(c *check.C) {
+	defer config.Unset("queue:driver")
+	s.setupMemoryQueue(c)
+	request, err := http.NewRequest("POST", "/queue/jobs/unknown/retry", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusNotFound)
+}
+
+func (s *S) TestQueueJobPurge(c *check.C) {
+	defer config.Unset("queue:driver")
+	q := s.setupMemoryQueue(c)
+	_, err := q.EnqueueWait("ok-task", nil, time.Minute)
+	c.Assert(err, check.IsNil)
+	_, err = q.EnqueueWait("failing-task", nil, time.Minute)
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest("DELETE", "/queue/jobs?task=ok-task", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(recorder.Body.String(), check.Equals, "{\"removed\":1}\n")
+	jobs, err := queue.FindJobs(queue.JobFilter{})
+	c.Assert(err, check.IsNil)
+	c.Assert(jobs, check.HasLen, 1)
+	c.Assert(jobs[0].TaskName(), check.Equals, "failing-task")
+	c.Assert(eventtest.EventDesc{
+		Target: event.Target{Type: event.TargetTypeQueueJob, Value: "ok-task"},
+		Owner:  s.token.GetUserName(),
+		Kind:   "queue.delete",
+		StartCustomData: []map[string]interface{}{
+			{"name": "task", "value": "ok-task"},
+		},
+	}, eventtest.HasEvent)
+}
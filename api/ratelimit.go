@@ -0,0 +1,150 @@
+// Copyright 2018 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/api/context"
+	"github.com/tsuru/tsuru/errors"
+)
+
+// rateLimiter is a set of token buckets, one for each key, allowing rate
+// requests per minute with bursts of up to rate requests.
+type rateLimiter struct {
+	sync.Mutex
+	rate      int
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(rate int) *rateLimiter {
+	if rate <= 0 {
+		return nil
+	}
+	return &rateLimiter{
+		rate:    rate,
+		buckets: map[string]*tokenBucket{},
+		now:     time.Now,
+	}
+}
+
+// allow takes a token from the bucket of the given key. It returns zero when
+// the request is allowed, otherwise how long until it would be.
+func (l *rateLimiter) allow(key string) time.Duration {
+	if l == nil {
+		return 0
+	}
+	l.Lock()
+	defer l.Unlock()
+	now := l.now()
+	perSecond := float64(l.rate) / 60
+	b := l.buckets[key]
+	if b == nil {
+		b = &tokenBucket{tokens: float64(l.rate), last: now}
+		l.buckets[key] = b
+	}
+	b.tokens = math.Min(float64(l.rate), b.tokens+now.Sub(b.last).Seconds()*perSecond)
+	b.last = now
+	if now.Sub(l.lastSweep) > time.Minute {
+		l.sweep(now)
+	}
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0
+	}
+	return time.Duration((1 - b.tokens) / perSecond * float64(time.Second))
+}
+
+// sweep removes buckets untouched for a minute, they're full again and
+// there's no point keeping them around.
+func (l *rateLimiter) sweep(now time.Time) {
+	for key, b := range l.buckets {
+		if now.Sub(b.last) > time.Minute {
+			delete(l.buckets, key)
+		}
+	}
+	l.lastSweep = now
+}
+
+// rateLimitMiddleware limits requests by token and by client IP. The
+// buckets live in memory, so each API process counts its own requests.
+type rateLimitMiddleware struct {
+	byToken *rateLimiter
+	byIP    *rateLimiter
+	// ipHeader is a header set by a trusted proxy in front of the API, like
+	// X-Forwarded-For, holding the client IP as its last value.
+	ipHeader string
+}
+
+func newRateLimitMiddleware() *rateLimitMiddleware {
+	tokenRate, _ := config.GetInt("server:rate-limit:token")
+	ipRate, _ := config.GetInt("server:rate-limit:ip")
+	ipHeader, _ := config.GetString("server:rate-limit:ip-header")
+	return &rateLimitMiddleware{
+		byToken:  newRateLimiter(tokenRate),
+		byIP:     newRateLimiter(ipRate),
+		ipHeader: ipHeader,
+	}
+}
+
+// clientIP returns the IP of the client, taken from the last value of the
+// configured header, which is the one added by the proxy closest to the API.
+// Values before it may have been sent by the client itself.
+func (m *rateLimitMiddleware) clientIP(r *http.Request) string {
+	if m.ipHeader != "" {
+		values := strings.Split(strings.Join(r.Header[http.CanonicalHeaderKey(m.ipHeader)], ","), ",")
+		if ip := strings.TrimSpace(values[len(values)-1]); ip != "" {
+			return ip
+		}
+	}
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return ip
+}
+
+func (m *rateLimitMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	if strings.TrimSuffix(r.URL.Path, "/") == "/healthcheck" {
+		next(w, r)
+		return
+	}
+	t := context.GetAuthToken(r)
+	// App tokens are used by units and deploy agents, limiting them would
+	// only drop app logs and unit status updates.
+	if t != nil && t.IsAppToken() {
+		next(w, r)
+		return
+	}
+	wait := m.byIP.allow(m.clientIP(r))
+	if wait == 0 && t != nil {
+		wait = m.byToken.allow(t.GetValue())
+	}
+	if wait > 0 {
+		seconds := int(math.Ceil(wait.Seconds()))
+		w.Header().Set("Retry-After", strconv.Itoa(seconds))
+		context.AddRequestError(r, &errors.HTTP{
+			Code:    http.StatusTooManyRequests,
+			Message: fmt.Sprintf("Too many requests, try again in %d seconds.", seconds),
+		})
+		return
+	}
+	next(w, r)
+}
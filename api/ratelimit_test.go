@@ -0,0 +1,191 @@
+// Copyright 2018 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/api/context"
+	"github.com/tsuru/tsuru/app"
+	"github.com/tsuru/tsuru/errors"
+	"gopkg.in/check.v1"
+)
+
+func (s *S) TestRateLimiterAllow(c *check.C) {
+	now := time.Date(2018, 5, 10, 12, 0, 0, 0, time.UTC)
+	l := newRateLimiter(2)
+	l.now = func() time.Time { return now }
+	c.Assert(l.allow("a"), check.Equals, time.Duration(0))
+	c.Assert(l.allow("a"), check.Equals, time.Duration(0))
+	c.Assert(l.allow("a"), check.Equals, 30*time.Second)
+	c.Assert(l.allow("b"), check.Equals, time.Duration(0))
+	now = now.Add(15 * time.Second)
+	c.Assert(l.allow("a"), check.Equals, 15*time.Second)
+	now = now.Add(15 * time.Second)
+	c.Assert(l.allow("a"), check.Equals, time.Duration(0))
+	c.Assert(l.allow("a"), check.Equals, 30*time.Second)
+}
+
+func (s *S) TestRateLimiterDisabled(c *check.C) {
+	l := newRateLimiter(0)
+	c.Assert(l, check.IsNil)
+	for i := 0; i < 100; i++ {
+		c.Assert(l.allow("a"), check.Equals, time.Duration(0))
+	}
+}
+
+func (s *S) TestRateLimiterSweep(c *check.C) {
+	now := time.Date(2018, 5, 10, 12, 0, 0, 0, time.UTC)
+	l := newRateLimiter(10)
+	l.now = func() time.Time { return now }
+	l.allow("a")
+	l.allow("b")
+	c.Assert(l.buckets, check.HasLen, 2)
+	now = now.Add(2 * time.Minute)
+	l.allow("b")
+	c.Assert(l.buckets, check.HasLen, 1)
+	c.Assert(l.buckets["b"], check.NotNil)
+}
+
+func (s *S) TestRateLimitMiddlewareByIP(c *check.C) {
+	m := &rateLimitMiddleware{byIP: newRateLimiter(1)}
+	request, err := http.NewRequest("GET", "/apps", nil)
+	c.Assert(err, check.IsNil)
+	request.RemoteAddr = "10.0.0.1:51000"
+	h, log := doHandler()
+	recorder := httptest.NewRecorder()
+	m.ServeHTTP(recorder, request, h)
+	c.Assert(log.called, check.Equals, true)
+	c.Assert(context.GetRequestError(request), check.IsNil)
+	request, err = http.NewRequest("GET", "/apps", nil)
+	c.Assert(err, check.IsNil)
+	request.RemoteAddr = "10.0.0.1:51001"
+	h, log = doHandler()
+	recorder = httptest.NewRecorder()
+	m.ServeHTTP(recorder, request, h)
+	c.Assert(log.called, check.Equals, false)
+	c.Assert(recorder.Header().Get("Retry-After"), check.Equals, "60")
+	err = context.GetRequestError(request)
+	c.Assert(err, check.NotNil)
+	c.Assert(err.(*errors.HTTP).Code, check.Equals, http.StatusTooManyRequests)
+	c.Assert(err.(*errors.HTTP).Message, check.Equals, "Too many requests, try again in 60 seconds.")
+	request, err = http.NewRequest("GET", "/healthcheck", nil)
+	c.Assert(err, check.IsNil)
+	request.RemoteAddr = "10.0.0.1:51002"
+	h, log = doHandler()
+	m.ServeHTTP(httptest.NewRecorder(), request, h)
+	c.Assert(log.called, check.Equals, true)
+}
+
+func (s *S) TestNewRateLimitMiddleware(c *check.C) {
+	config.Set("server:rate-limit:token", 10)
+	config.Set("server:rate-limit:ip-header", "X-Forwarded-For")
+	defer config.Unset("server:rate-limit")
+	m := newRateLimitMiddleware()
+	c.Assert(m.byToken, check.NotNil)
+	c.Assert(m.byToken.rate, check.Equals, 10)
+	c.Assert(m.byIP, check.IsNil)
+	c.Assert(m.ipHeader, check.Equals, "X-Forwarded-For")
+}
+
+func (s *S) TestRateLimitMiddlewareClientIP(c *check.C) {
+	tests := []struct {
+		header   string
+		values   []string
+		expected string
+	}{
+		{"", []string{"10.0.0.9"}, "10.0.0.1"},
+		{"X-Forwarded-For", nil, "10.0.0.1"},
+		{"X-Forwarded-For", []string{"10.0.0.9"}, "10.0.0.9"},
+		{"X-Forwarded-For", []string{"1.1.1.1, 10.0.0.9"}, "10.0.0.9"},
+		{"X-Forwarded-For", []string{"1.1.1.1", "10.0.0.8"}, "10.0.0.8"},
+		{"X-Forwarded-For", []string{"1.1.1.1, "}, "10.0.0.1"},
+	}
+	for _, tt := range tests {
+		m := &rateLimitMiddleware{ipHeader: tt.header}
+		request, err := http.NewRequest("GET", "/apps", nil)
+		c.Assert(err, check.IsNil)
+		request.RemoteAddr = "10.0.0.1:51000"
+		for _, v := range tt.values {
+			request.Header.Add("X-Forwarded-For", v)
+		}
+		c.Check(m.clientIP(request), check.Equals, tt.expected, check.Commentf("header %q values %v", tt.header, tt.values))
+	}
+}
+
+func (s *S) TestRateLimitMiddlewareByIPHeader(c *check.C) {
+	m := &rateLimitMiddleware{byIP: newRateLimiter(1), ipHeader: "X-Real-IP"}
+	for _, ip := range []string{"10.0.0.8", "10.0.0.9"} {
+		request, err := http.NewRequest("GET", "/apps", nil)
+		c.Assert(err, check.IsNil)
+		request.RemoteAddr = "192.168.0.1:51000"
+		request.Header.Set("X-Real-IP", ip)
+		h, log := doHandler()
+		m.ServeHTTP(httptest.NewRecorder(), request, h)
+		c.Assert(log.called, check.Equals, true)
+	}
+	request, err := http.NewRequest("GET", "/apps", nil)
+	c.Assert(err, check.IsNil)
+	request.RemoteAddr = "192.168.0.1:51000"
+	request.Header.Set("X-Real-IP", "10.0.0.8")
+	h, log := doHandler()
+	m.ServeHTTP(httptest.NewRecorder(), request, h)
+	c.Assert(log.called, check.Equals, false)
+}
+
+func (s *S) TestRateLimitMiddlewareByToken(c *check.C) {
+	m := &rateLimitMiddleware{byToken: newRateLimiter(1)}
+	for i, addr := range []string{"10.0.0.1:51000", "10.0.0.2:51000"} {
+		request, err := http.NewRequest("GET", "/apps", nil)
+		c.Assert(err, check.IsNil)
+		request.RemoteAddr = addr
+		context.SetAuthToken(request, s.token)
+		h, log := doHandler()
+		recorder := httptest.NewRecorder()
+		m.ServeHTTP(recorder, request, h)
+		c.Assert(log.called, check.Equals, i == 0)
+	}
+	request, err := http.NewRequest("GET", "/apps", nil)
+	c.Assert(err, check.IsNil)
+	request.RemoteAddr = "10.0.0.1:51000"
+	h, log := doHandler()
+	m.ServeHTTP(httptest.NewRecorder(), request, h)
+	c.Assert(log.called, check.Equals, true)
+}
+
+func (s *S) TestRateLimitMiddlewareIgnoresAppTokens(c *check.C) {
+	token, err := nativeScheme.AppLogin(app.InternalAppName)
+	c.Assert(err, check.IsNil)
+	m := &rateLimitMiddleware{byToken: newRateLimiter(1), byIP: newRateLimiter(1)}
+	for i := 0; i < 3; i++ {
+		request, err := http.NewRequest("POST", "/apps/myapp/log", nil)
+		c.Assert(err, check.IsNil)
+		request.RemoteAddr = "10.0.0.1:51000"
+		context.SetAuthToken(request, token)
+		h, log := doHandler()
+		m.ServeHTTP(httptest.NewRecorder(), request, h)
+		c.Assert(log.called, check.Equals, true)
+	}
+}
+
+func (s *S) TestRateLimitServerReturnsTooManyRequests(c *check.C) {
+	m := &rateLimitMiddleware{byToken: newRateLimiter(1)}
+	request, err := http.NewRequest("GET", "/apps", nil)
+	c.Assert(err, check.IsNil)
+	context.SetAuthToken(request, s.token)
+	m.ServeHTTP(httptest.NewRecorder(), request, func(w http.ResponseWriter, r *http.Request) {})
+	request, err = http.NewRequest("GET", "/apps", nil)
+	c.Assert(err, check.IsNil)
+	context.SetAuthToken(request, s.token)
+	recorder := httptest.NewRecorder()
+	errorHandlingMiddleware(recorder, request, func(w http.ResponseWriter, r *http.Request) {
+		m.ServeHTTP(w, r, func(w http.ResponseWriter, r *http.Request) {})
+	})
+	c.Assert(recorder.Code, check.Equals, http.StatusTooManyRequests)
+	c.Assert(recorder.Header().Get("Retry-After"), check.Equals, "60")
+}
@@ -20,6 +20,17 @@ const versionMatcher = "/{version:[0-9.]+}"
 type Route struct {
 	route   *mux.Route
 	version string
+	methods []string
+	path    string
+	handler http.Handler
+}
+
+// RouteInfo describes a route registered in the router.
+type RouteInfo struct {
+	Version string
+	Methods []string
+	Path    string
+	Handler http.Handler
 }
 
 func NewRouter() *DelayedRouter {
@@ -30,8 +41,9 @@ func NewRouter() *DelayedRouter {
 }
 
 type DelayedRouter struct {
-	mux    *mux.Router
-	routes map[*mux.Route]*Route
+	mux       *mux.Router
+	routes    map[*mux.Route]*Route
+	routeList []*Route
 }
 
 func (r *DelayedRouter) registerVars(req *http.Request, vars map[string]string) {
@@ -44,8 +56,9 @@ func (r *DelayedRouter) registerVars(req *http.Request, vars map[string]string)
 
 func (r *DelayedRouter) addRoute(version, path string, h http.Handler, methods ...string) *mux.Route {
 	muxRoute := r.mux.NewRoute().Handler(h).Methods(methods...)
-	route := &Route{route: muxRoute, version: version}
+	route := &Route{route: muxRoute, version: version, methods: methods, path: path, handler: h}
 	r.routes[muxRoute] = route
+	r.routeList = append(r.routeList, route)
 	versionRegexp := regexp.MustCompile("/(?P<version>[0-9.]+)/")
 	muxRoute.MatcherFunc(func(httpRequest *http.Request, rm *mux.RouteMatch) bool {
 		d := versionRegexp.FindStringSubmatch(httpRequest.URL.Path)
@@ -64,6 +77,20 @@ func (r *DelayedRouter) AddAll(version, path string, h http.Handler) *mux.Route
 	return r.addRoute(version, path, h, "GET", "POST", "PUT", "DELETE")
 }
 
+// Routes returns the registered routes in the order they were added.
+func (r *DelayedRouter) Routes() []RouteInfo {
+	routes := make([]RouteInfo, len(r.routeList))
+	for i, route := range r.routeList {
+		routes[i] = RouteInfo{
+			Version: route.version,
+			Methods: route.methods,
+			Path:    route.path,
+			Handler: route.handler,
+		}
+	}
+	return routes
+}
+
 func (r *DelayedRouter) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	var match mux.RouteMatch
 	if !r.mux.Match(req, &match) {
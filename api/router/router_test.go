@@ -108,3 +108,18 @@ func (s *S) TestDelayedRouterAddAll(c *check.C) {
 		called = false
 	}
 }
+
+func (s *S) TestRoutes(c *check.C) {
+	router := NewRouter()
+	router.Add("1.0", "GET", "/dream/{world}", http.HandlerFunc(runDelayedHandler))
+	router.AddAll("1.1", "/dreamers", http.HandlerFunc(runDelayedHandler))
+	routes := router.Routes()
+	c.Assert(routes, check.HasLen, 2)
+	c.Assert(routes[0].Version, check.Equals, "1.0")
+	c.Assert(routes[0].Methods, check.DeepEquals, []string{"GET"})
+	c.Assert(routes[0].Path, check.Equals, "/dream/{world}")
+	c.Assert(routes[0].Handler, check.NotNil)
+	c.Assert(routes[1].Version, check.Equals, "1.1")
+	c.Assert(routes[1].Methods, check.DeepEquals, []string{"GET", "POST", "PUT", "DELETE"})
+	c.Assert(routes[1].Path, check.Equals, "/dreamers")
+}
@@ -102,6 +102,7 @@ func RunServer(dry bool) http.Handler {
 		m.Add("1.0", "Get", "/", Handler(index))
 	}
 	m.Add("1.0", "Get", "/info", Handler(info))
+	m.Add("1.6", "GET", "/openapi.json", apiSchema(m))
 
 	m.Add("1.0", "Get", "/services/instances", AuthorizationRequiredHandler(serviceInstances))
 	m.Add("1.0", "Get", "/services/{service}/instances/{instance}", AuthorizationRequiredHandler(serviceInstance))
@@ -135,6 +136,11 @@ func RunServer(dry bool) http.Handler {
 	runHandler := AuthorizationRequiredHandler(runCommand)
 	m.Add("1.0", "Post", "/apps/{app}/run", runHandler)
 	m.Add("1.0", "Post", "/apps/{app}/restart", AuthorizationRequiredHandler(restart))
+	m.Add("1.6", "Put", "/apps/{app}/restart-schedule", AuthorizationRequiredHandler(setRestartSchedule))
+	m.Add("1.6", "Post", "/apps/{app}/scale-schedules", AuthorizationRequiredHandler(addScaleSchedule))
+	m.Add("1.6", "Delete", "/apps/{app}/scale-schedules/{name}", AuthorizationRequiredHandler(removeScaleSchedule))
+	m.Add("1.6", "Post", "/apps/{app}/maintenance", AuthorizationRequiredHandler(enableMaintenance))
+	m.Add("1.6", "Delete", "/apps/{app}/maintenance", AuthorizationRequiredHandler(disableMaintenance))
 	m.Add("1.0", "Post", "/apps/{app}/start", AuthorizationRequiredHandler(start))
 	m.Add("1.0", "Post", "/apps/{app}/stop", AuthorizationRequiredHandler(stop))
 	m.Add("1.0", "Post", "/apps/{app}/sleep", AuthorizationRequiredHandler(sleep))
@@ -144,8 +150,14 @@ func RunServer(dry bool) http.Handler {
 	m.Add("1.0", "Get", "/apps/{app}/env", AuthorizationRequiredHandler(getEnv))
 	m.Add("1.0", "Post", "/apps/{app}/env", AuthorizationRequiredHandler(setEnv))
 	m.Add("1.0", "Delete", "/apps/{app}/env", AuthorizationRequiredHandler(unsetEnv))
+	m.Add("1.6", "Post", "/apps/{app}/env/import", AuthorizationRequiredHandler(importEnv))
+	m.Add("1.6", "Get", "/apps/{app}/env/export", AuthorizationRequiredHandler(exportEnv))
+	m.Add("1.6", "Get", "/apps/{app}/build-env", AuthorizationRequiredHandler(getBuildEnv))
+	m.Add("1.6", "Post", "/apps/{app}/build-env", AuthorizationRequiredHandler(setBuildEnv))
+	m.Add("1.6", "Delete", "/apps/{app}/build-env", AuthorizationRequiredHandler(unsetBuildEnv))
 	m.Add("1.0", "Get", "/apps", AuthorizationRequiredHandler(appList))
 	m.Add("1.0", "Post", "/apps", AuthorizationRequiredHandler(createApp))
+	m.Add("1.6", "Post", "/apps/{app}/clone", AuthorizationRequiredHandler(cloneApp))
 	forceDeleteLockHandler := AuthorizationRequiredHandler(forceDeleteLock)
 	m.Add("1.0", "Delete", "/apps/{app}/lock", forceDeleteLockHandler)
 	m.Add("1.0", "Put", "/apps/{app}/units", AuthorizationRequiredHandler(addUnits))
@@ -160,9 +172,11 @@ func RunServer(dry bool) http.Handler {
 	logPostHandler := AuthorizationRequiredHandler(addLog)
 	m.Add("1.0", "Post", "/apps/{app}/log", logPostHandler)
 	m.Add("1.0", "Post", "/apps/{appname}/deploy/rollback", AuthorizationRequiredHandler(deployRollback))
+	m.Add("1.6", "Post", "/apps/{appname}/deploy/cancel", AuthorizationRequiredHandler(deployCancel))
 	m.Add("1.4", "Put", "/apps/{appname}/deploy/rollback/update", AuthorizationRequiredHandler(deployRollbackUpdate))
 	m.Add("1.3", "Post", "/apps/{appname}/deploy/rebuild", AuthorizationRequiredHandler(deployRebuild))
 	m.Add("1.0", "Get", "/apps/{app}/metric/envs", AuthorizationRequiredHandler(appMetricEnvs))
+	m.Add("1.6", "Get", "/apps/{app}/metrics", AuthorizationRequiredHandler(appUnitsMetrics))
 	m.Add("1.0", "Post", "/apps/{app}/routes", AuthorizationRequiredHandler(appRebuildRoutes))
 	m.Add("1.2", "Get", "/apps/{app}/certificate", AuthorizationRequiredHandler(listCertificates))
 	m.Add("1.2", "Put", "/apps/{app}/certificate", AuthorizationRequiredHandler(setCertificate))
@@ -177,12 +191,18 @@ func RunServer(dry bool) http.Handler {
 
 	m.Add("1.0", "Get", "/deploys", AuthorizationRequiredHandler(deploysList))
 	m.Add("1.0", "Get", "/deploys/{deploy}", AuthorizationRequiredHandler(deployInfo))
+	m.Add("1.6", "Get", "/deploys/{deploy}/changes", AuthorizationRequiredHandler(deployChanges))
 
 	m.Add("1.1", "Get", "/events", AuthorizationRequiredHandler(eventList))
 	m.Add("1.3", "Get", "/events/blocks", AuthorizationRequiredHandler(eventBlockList))
 	m.Add("1.3", "Post", "/events/blocks", AuthorizationRequiredHandler(eventBlockAdd))
 	m.Add("1.3", "Delete", "/events/blocks/{uuid}", AuthorizationRequiredHandler(eventBlockRemove))
 	m.Add("1.1", "Get", "/events/kinds", AuthorizationRequiredHandler(kindList))
+	m.Add("1.6", "GET", "/audit", AuthorizationRequiredHandler(auditList))
+	m.Add("1.6", "GET", "/events/webhooks", AuthorizationRequiredHandler(webhookList))
+	m.Add("1.6", "POST", "/events/webhooks", AuthorizationRequiredHandler(webhookCreate))
+	m.Add("1.6", "GET", "/events/webhooks/{name}", AuthorizationRequiredHandler(webhookInfo))
+	m.Add("1.6", "DELETE", "/events/webhooks/{name}", AuthorizationRequiredHandler(webhookDelete))
 	m.Add("1.1", "Get", "/events/{uuid}", AuthorizationRequiredHandler(eventInfo))
 	m.Add("1.1", "Post", "/events/{uuid}/cancel", AuthorizationRequiredHandler(eventCancel))
 
@@ -203,6 +223,7 @@ func RunServer(dry bool) http.Handler {
 	// Shell also doesn't use {app} on purpose. Middlewares don't play well
 	// with websocket.
 	m.Add("1.0", "Get", "/apps/{appname}/shell", http.HandlerFunc(remoteShellHandler))
+	m.Add("1.6", "GET", "/apps/{appname}/log/stream", http.HandlerFunc(appLogStream))
 
 	m.Add("1.0", "Get", "/users", AuthorizationRequiredHandler(listUsers))
 	m.Add("1.0", "Post", "/users", Handler(createUser))
@@ -274,6 +295,8 @@ func RunServer(dry bool) http.Handler {
 	m.Add("1.0", "Delete", "/role/default", AuthorizationRequiredHandler(removeDefaultRole))
 	m.Add("1.0", "Get", "/permissions", AuthorizationRequiredHandler(listPermissions))
 
+	m.Add("1.6", "Post", "/images/gc", AuthorizationRequiredHandler(imageGC))
+
 	m.Add("1.0", "Get", "/debug/goroutines", AuthorizationRequiredHandler(dumpGoroutines))
 	m.Add("1.0", "Get", "/debug/pprof/", AuthorizationRequiredHandler(indexHandler))
 	m.Add("1.0", "Get", "/debug/pprof/cmdline", AuthorizationRequiredHandler(cmdlineHandler))
@@ -313,6 +336,11 @@ func RunServer(dry bool) http.Handler {
 	m.Add("1.2", "GET", "/install/hosts", AuthorizationRequiredHandler(installHostList))
 	m.Add("1.2", "GET", "/install/hosts/{name}", AuthorizationRequiredHandler(installHostInfo))
 
+	m.Add("1.6", "GET", "/queue/stats", AuthorizationRequiredHandler(queueStats))
+	m.Add("1.6", "GET", "/queue/jobs", AuthorizationRequiredHandler(queueJobList))
+	m.Add("1.6", "DELETE", "/queue/jobs", AuthorizationRequiredHandler(queueJobPurge))
+	m.Add("1.6", "POST", "/queue/jobs/{id}/retry", AuthorizationRequiredHandler(queueJobRetry))
+
 	m.Add("1.2", "GET", "/healing/node", AuthorizationRequiredHandler(nodeHealingRead))
 	m.Add("1.2", "POST", "/healing/node", AuthorizationRequiredHandler(nodeHealingUpdate))
 	m.Add("1.2", "DELETE", "/healing/node", AuthorizationRequiredHandler(nodeHealingDelete))
@@ -371,12 +399,20 @@ func RunServer(dry bool) http.Handler {
 	if !dry {
 		n.Use(newLoggerMiddleware())
 	}
+	if cors := newCORSMiddleware(); cors != nil {
+		n.Use(cors)
+	}
 	n.UseHandler(m)
+	n.Use(negroni.HandlerFunc(auditMiddleware))
+	if gzip := newGzipMiddleware(); gzip != nil {
+		n.Use(gzip)
+	}
 	n.Use(negroni.HandlerFunc(flushingWriterMiddleware))
 	n.Use(negroni.HandlerFunc(setRequestIDHeaderMiddleware))
 	n.Use(negroni.HandlerFunc(errorHandlingMiddleware))
 	n.Use(negroni.HandlerFunc(setVersionHeadersMiddleware))
 	n.Use(negroni.HandlerFunc(authTokenMiddleware))
+	n.Use(newRateLimitMiddleware())
 	n.Use(&appLockMiddleware{excludedHandlers: []http.Handler{
 		logPostHandler,
 		runHandler,
@@ -527,6 +563,10 @@ func startServer(handler http.Handler) {
 	if err != nil {
 		fatal(errors.Wrap(err, "unable to initialize old image gc"))
 	}
+	err = app.InitializeScheduler()
+	if err != nil {
+		fatal(errors.Wrap(err, "unable to initialize app scheduler"))
+	}
 	err = service.InitializeSync(bindAppsLister)
 	if err != nil {
 		fatal(err)
@@ -329,12 +329,20 @@ func readableServices(t auth.Token, contexts []permission.PermissionContext) ([]
 //   204: No content
 //   401: Unauthorized
 func serviceInstances(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	limit, skip, err := paginationParams(r)
+	if err != nil {
+		return err
+	}
 	appName := r.URL.Query().Get("app")
 	contexts := permission.ContextsForPermission(t, permission.PermServiceInstanceRead)
 	instances, err := readableInstances(t, contexts, appName, "")
 	if err != nil {
 		return err
 	}
+	if limit > 0 {
+		w.Header().Set(totalCountHeader, strconv.Itoa(len(instances)))
+		instances = paginateInstances(instances, skip, limit)
+	}
 	contexts = permission.ContextsForPermission(t, permission.PermServiceRead)
 	services, err := readableServices(t, contexts)
 	if err != nil {
@@ -360,6 +368,9 @@ func serviceInstances(w http.ResponseWriter, r *http.Request, t auth.Token) erro
 	result := []service.ServiceModel{}
 	for _, name := range sortedServiceNames(servicesMap) {
 		entry := servicesMap[name]
+		if limit > 0 && len(entry.Instances) == 0 {
+			continue
+		}
 		result = append(result, *entry)
 	}
 	if len(result) == 0 {
@@ -370,6 +381,25 @@ func serviceInstances(w http.ResponseWriter, r *http.Request, t auth.Token) erro
 	return json.NewEncoder(w).Encode(result)
 }
 
+// paginateInstances sorts the instances by service and name, returning at
+// most limit instances after skipping the first skip ones.
+func paginateInstances(instances []service.ServiceInstance, skip, limit int) []service.ServiceInstance {
+	sort.Slice(instances, func(i, j int) bool {
+		if instances[i].ServiceName != instances[j].ServiceName {
+			return instances[i].ServiceName < instances[j].ServiceName
+		}
+		return instances[i].Name < instances[j].Name
+	})
+	if skip > len(instances) {
+		skip = len(instances)
+	}
+	instances = instances[skip:]
+	if len(instances) > limit {
+		instances = instances[:limit]
+	}
+	return instances
+}
+
 // title: service instance status
 // path: /services/{service}/instances/{instance}/status
 // method: GET
@@ -1224,6 +1224,53 @@ func (s *ServiceInstanceSuite) TestListServiceInstancesAppFilter(c *check.C) {
 	c.Assert(instances, check.DeepEquals, expected)
 }
 
+func (s *ServiceInstanceSuite) TestListServiceInstancesPaginated(c *check.C) {
+	err := s.conn.Services().RemoveId(s.service.Name)
+	c.Assert(err, check.IsNil)
+	for _, name := range []string{"redis", "mongodb"} {
+		srv := service.Service{
+			Name:       name,
+			Teams:      []string{s.team.Name},
+			OwnerTeams: []string{s.team.Name},
+			Endpoint:   map[string]string{"production": "http://localhost:1234"},
+			Password:   "abcde",
+		}
+		err = srv.Create()
+		c.Assert(err, check.IsNil)
+	}
+	for _, instance := range []service.ServiceInstance{
+		{Name: "redis-b", ServiceName: "redis", Teams: []string{s.team.Name}},
+		{Name: "redis-a", ServiceName: "redis", Teams: []string{s.team.Name}},
+		{Name: "mongodb-a", ServiceName: "mongodb", Teams: []string{s.team.Name}},
+	} {
+		err = s.conn.ServiceInstances().Insert(instance)
+		c.Assert(err, check.IsNil)
+	}
+	request, err := http.NewRequest("GET", "/services/instances?limit=2&skip=1", nil)
+	c.Assert(err, check.IsNil)
+	recorder := httptest.NewRecorder()
+	err = serviceInstances(recorder, request, s.token)
+	c.Assert(err, check.IsNil)
+	c.Assert(recorder.Header().Get("X-Total-Count"), check.Equals, "3")
+	var instances []service.ServiceModel
+	err = json.Unmarshal(recorder.Body.Bytes(), &instances)
+	c.Assert(err, check.IsNil)
+	c.Assert(instances, check.DeepEquals, []service.ServiceModel{
+		{Service: "redis", Instances: []string{"redis-a", "redis-b"}, Plans: []string{"", ""}},
+	})
+}
+
+func (s *ServiceInstanceSuite) TestListServiceInstancesInvalidPagination(c *check.C) {
+	request, err := http.NewRequest("GET", "/services/instances?limit=-1", nil)
+	c.Assert(err, check.IsNil)
+	recorder := httptest.NewRecorder()
+	err = serviceInstances(recorder, request, s.token)
+	c.Assert(err, check.NotNil)
+	e, ok := err.(*errors.HTTP)
+	c.Assert(ok, check.Equals, true)
+	c.Assert(e.Code, check.Equals, http.StatusBadRequest)
+}
+
 func (s *ServiceInstanceSuite) TestListServiceInstancesReturnsOnlyServicesThatTheUserHasAccess(c *check.C) {
 	err := s.conn.Services().RemoveId(s.service.Name)
 	c.Assert(err, check.IsNil)
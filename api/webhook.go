@@ -0,0 +1,153 @@
+// Copyright 2018 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/tsuru/tsuru/auth"
+	"github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/permission"
+)
+
+// title: webhook list
+// path: /events/webhooks
+// method: GET
+// produce: application/json
+// responses:
+//   200: OK
+//   204: No content
+//   401: Unauthorized
+func webhookList(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	teams, err := permission.ListContextValues(t, permission.PermWebhookRead, true)
+	if err != nil {
+		return err
+	}
+	webhooks, err := event.ListWebhooks(teams)
+	if err != nil {
+		return err
+	}
+	if len(webhooks) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(webhooks)
+}
+
+// title: webhook info
+// path: /events/webhooks/{name}
+// method: GET
+// produce: application/json
+// responses:
+//   200: OK
+//   401: Unauthorized
+//   404: Not found
+func webhookInfo(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	webhook, err := event.GetWebhook(r.URL.Query().Get(":name"))
+	if err != nil {
+		if err == event.ErrWebhookNotFound {
+			return &errors.HTTP{Code: http.StatusNotFound, Message: err.Error()}
+		}
+		return err
+	}
+	if !permission.Check(t, permission.PermWebhookRead, permission.Context(permission.CtxTeam, webhook.TeamOwner)) {
+		return permission.ErrUnauthorized
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(webhook)
+}
+
+// title: webhook create
+// path: /events/webhooks
+// method: POST
+// consume: application/x-www-form-urlencoded
+// responses:
+//   200: OK
+//   400: Invalid data
+//   401: Unauthorized
+//   409: Webhook already exists
+func webhookCreate(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	r.ParseForm()
+	webhook := event.Webhook{
+		Name:      r.FormValue("name"),
+		TeamOwner: r.FormValue("team"),
+		URL:       r.FormValue("url"),
+		Secret:    r.FormValue("secret"),
+		Filter: event.WebhookFilter{
+			KindNames: r.Form["kind"],
+			Apps:      r.Form["app"],
+		},
+	}
+	webhook.Filter.ErrorOnly, _ = strconv.ParseBool(r.FormValue("error-only"))
+	webhook.Filter.SuccessOnly, _ = strconv.ParseBool(r.FormValue("success-only"))
+	if webhook.TeamOwner == "" {
+		webhook.TeamOwner, err = permission.TeamForPermission(t, permission.PermWebhookCreate)
+		if err != nil {
+			return err
+		}
+	}
+	teamCtx := permission.Context(permission.CtxTeam, webhook.TeamOwner)
+	if !permission.Check(t, permission.PermWebhookCreate, teamCtx) {
+		return permission.ErrUnauthorized
+	}
+	customData := event.FormToCustomData(r.Form)
+	for _, item := range customData {
+		if item["name"] == "secret" {
+			item["value"] = "*****"
+		}
+	}
+	evt, err := event.New(&event.Opts{
+		Target:     event.Target{Type: event.TargetTypeWebhook, Value: webhook.Name},
+		Kind:       permission.PermWebhookCreate,
+		Owner:      t,
+		CustomData: customData,
+		Allowed:    event.Allowed(permission.PermWebhookReadEvents, teamCtx),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(err) }()
+	err = event.AddWebhook(&webhook)
+	if err == event.ErrWebhookAlreadyExists {
+		return &errors.HTTP{Code: http.StatusConflict, Message: err.Error()}
+	}
+	return err
+}
+
+// title: webhook delete
+// path: /events/webhooks/{name}
+// method: DELETE
+// responses:
+//   200: OK
+//   401: Unauthorized
+//   404: Not found
+func webhookDelete(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	webhook, err := event.GetWebhook(r.URL.Query().Get(":name"))
+	if err != nil {
+		if err == event.ErrWebhookNotFound {
+			return &errors.HTTP{Code: http.StatusNotFound, Message: err.Error()}
+		}
+		return err
+	}
+	teamCtx := permission.Context(permission.CtxTeam, webhook.TeamOwner)
+	if !permission.Check(t, permission.PermWebhookDelete, teamCtx) {
+		return permission.ErrUnauthorized
+	}
+	evt, err := event.New(&event.Opts{
+		Target:  event.Target{Type: event.TargetTypeWebhook, Value: webhook.Name},
+		Kind:    permission.PermWebhookDelete,
+		Owner:   t,
+		Allowed: event.Allowed(permission.PermWebhookReadEvents, teamCtx),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(err) }()
+	return event.RemoveWebhook(webhook.Name)
+}
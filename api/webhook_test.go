@@ -0,0 +1,180 @@
+// Copyright 2018 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/event/eventtest"
+	"github.com/tsuru/tsuru/permission"
+	"gopkg.in/check.v1"
+)
+
+func (s *S) TestWebhookCreate(c *check.C) {
+	recorder := httptest.NewRecorder()
+	body := strings.NewReader("name=hook1&team=" + s.team.Name + "&url=http://example.com/hook&secret=s3cr3t&kind=app.deploy&app=myapp&error-only=true")
+	request, err := http.NewRequest("POST", "/events/webhooks", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	hook, err := event.GetWebhook("hook1")
+	c.Assert(err, check.IsNil)
+	c.Assert(*hook, check.DeepEquals, event.Webhook{
+		Name:      "hook1",
+		TeamOwner: s.team.Name,
+		URL:       "http://example.com/hook",
+		Secret:    "s3cr3t",
+		Filter: event.WebhookFilter{
+			KindNames: []string{"app.deploy"},
+			Apps:      []string{"myapp"},
+			ErrorOnly: true,
+		},
+	})
+	c.Assert(eventtest.EventDesc{
+		Target: event.Target{Type: event.TargetTypeWebhook, Value: "hook1"},
+		Owner:  s.token.GetUserName(),
+		Kind:   "webhook.create",
+		StartCustomData: []map[string]interface{}{
+			{"name": "name", "value": "hook1"},
+			{"name": "secret", "value": "*****"},
+		},
+	}, eventtest.HasEvent)
+}
+
+func (s *S) TestWebhookCreateInvalid(c *check.C) {
+	recorder := httptest.NewRecorder()
+	body := strings.NewReader("name=hook1&team=" + s.team.Name + "&url=example.com")
+	request, err := http.NewRequest("POST", "/events/webhooks", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+	c.Assert(recorder.Body.String(), check.Equals, "invalid webhook url \"example.com\"\n")
+}
+
+func (s *S) TestWebhookCreateAlreadyExists(c *check.C) {
+	err := event.AddWebhook(&event.Webhook{Name: "hook1", TeamOwner: s.team.Name, URL: "http://example.com"})
+	c.Assert(err, check.IsNil)
+	recorder := httptest.NewRecorder()
+	body := strings.NewReader("name=hook1&team=" + s.team.Name + "&url=http://example.com")
+	request, err := http.NewRequest("POST", "/events/webhooks", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusConflict)
+}
+
+func (s *S) TestWebhookCreateUnauthorized(c *check.C) {
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermWebhookCreate,
+		Context: permission.Context(permission.CtxTeam, "otherteam"),
+	})
+	recorder := httptest.NewRecorder()
+	body := strings.NewReader("name=hook1&team=" + s.team.Name + "&url=http://example.com")
+	request, err := http.NewRequest("POST", "/events/webhooks", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+}
+
+func (s *S) TestWebhookList(c *check.C) {
+	err := event.AddWebhook(&event.Webhook{Name: "hook1", TeamOwner: s.team.Name, URL: "http://example.com", Secret: "s3cr3t"})
+	c.Assert(err, check.IsNil)
+	err = event.AddWebhook(&event.Webhook{Name: "hook2", TeamOwner: "otherteam", URL: "http://example.com"})
+	c.Assert(err, check.IsNil)
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermWebhookRead,
+		Context: permission.Context(permission.CtxTeam, s.team.Name),
+	})
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest("GET", "/events/webhooks", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(recorder.Body.String(), check.Not(check.Matches), ".*s3cr3t.*")
+	var hooks []event.Webhook
+	err = json.NewDecoder(recorder.Body).Decode(&hooks)
+	c.Assert(err, check.IsNil)
+	c.Assert(hooks, check.DeepEquals, []event.Webhook{
+		{Name: "hook1", TeamOwner: s.team.Name, URL: "http://example.com"},
+	})
+}
+
+func (s *S) TestWebhookListEmpty(c *check.C) {
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest("GET", "/events/webhooks", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusNoContent)
+}
+
+func (s *S) TestWebhookInfo(c *check.C) {
+	err := event.AddWebhook(&event.Webhook{Name: "hook1", TeamOwner: s.team.Name, URL: "http://example.com"})
+	c.Assert(err, check.IsNil)
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest("GET", "/events/webhooks/hook1", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	var hook event.Webhook
+	err = json.NewDecoder(recorder.Body).Decode(&hook)
+	c.Assert(err, check.IsNil)
+	c.Assert(hook, check.DeepEquals, event.Webhook{Name: "hook1", TeamOwner: s.team.Name, URL: "http://example.com"})
+}
+
+func (s *S) TestWebhookInfoNotFound(c *check.C) {
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest("GET", "/events/webhooks/hook1", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusNotFound)
+}
+
+func (s *S) TestWebhookDelete(c *check.C) {
+	err := event.AddWebhook(&event.Webhook{Name: "hook1", TeamOwner: s.team.Name, URL: "http://example.com"})
+	c.Assert(err, check.IsNil)
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest("DELETE", "/events/webhooks/hook1", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	_, err = event.GetWebhook("hook1")
+	c.Assert(err, check.Equals, event.ErrWebhookNotFound)
+	c.Assert(eventtest.EventDesc{
+		Target: event.Target{Type: event.TargetTypeWebhook, Value: "hook1"},
+		Owner:  s.token.GetUserName(),
+		Kind:   "webhook.delete",
+	}, eventtest.HasEvent)
+}
+
+func (s *S) TestWebhookDeleteUnauthorized(c *check.C) {
+	err := event.AddWebhook(&event.Webhook{Name: "hook1", TeamOwner: s.team.Name, URL: "http://example.com"})
+	c.Assert(err, check.IsNil)
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermWebhookRead,
+		Context: permission.Context(permission.CtxTeam, s.team.Name),
+	})
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest("DELETE", "/events/webhooks/hook1", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+}
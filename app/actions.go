@@ -20,6 +20,7 @@ import (
 	tsuruErrors "github.com/tsuru/tsuru/errors"
 	"github.com/tsuru/tsuru/log"
 	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/provision"
 	"github.com/tsuru/tsuru/quota"
 	"github.com/tsuru/tsuru/repository"
 	"github.com/tsuru/tsuru/router"
@@ -354,10 +355,21 @@ var provisionAddUnits = action.Action{
 		w, _ := ctx.Params[2].(io.Writer)
 		n := ctx.Previous.(int)
 		process := ctx.Params[3].(string)
+		var node string
+		if len(ctx.Params) > 4 {
+			node, _ = ctx.Params[4].(string)
+		}
 		prov, err := app.getProvisioner()
 		if err != nil {
 			return nil, err
 		}
+		if node != "" {
+			nodeProv, ok := prov.(provision.NodeUnitsProvisioner)
+			if !ok {
+				return nil, provision.ProvisionerNotSupported{Prov: prov, Action: "adding units to a node"}
+			}
+			return nil, nodeProv.AddUnitsOnNode(app, uint(n), process, node, w)
+		}
 		return nil, prov.AddUnits(app, uint(n), process, w)
 	},
 	MinParams: 1,
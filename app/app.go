@@ -12,6 +12,7 @@ import (
 	"io"
 	"io/ioutil"
 	"net/url"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -40,6 +41,7 @@ import (
 	"github.com/tsuru/tsuru/router"
 	"github.com/tsuru/tsuru/router/rebuild"
 	"github.com/tsuru/tsuru/service"
+	"github.com/tsuru/tsuru/set"
 	"github.com/tsuru/tsuru/storage"
 	appTypes "github.com/tsuru/tsuru/types/app"
 	authTypes "github.com/tsuru/tsuru/types/auth"
@@ -76,8 +78,14 @@ const (
 
 	TsuruServicesEnvVar = "TSURU_SERVICES"
 	defaultAppDir       = "/home/application/current"
+
+	redactedEnvValue     = "*****"
+	minRedactedEnvLength = 4
 )
 
+// internalEnvs are the environment variables set by tsuru when creating apps.
+var internalEnvs = set.FromValues("TSURU_APPNAME", "TSURU_APPDIR", "TSURU_APP_TOKEN")
+
 // AppLock stores information about a lock hold on the app
 type AppLock struct {
 	Locked      bool
@@ -131,25 +139,31 @@ func (l *AppLock) GetAcquireDate() time.Time {
 // This struct holds information about the app: its name, address, list of
 // teams that have access to it, used platform, etc.
 type App struct {
-	Env            map[string]bind.EnvVar
-	ServiceEnvs    []bind.ServiceEnvVar
-	Platform       string `bson:"framework"`
-	Name           string
-	CName          []string
-	Teams          []string
-	TeamOwner      string
-	Owner          string
-	Plan           appTypes.Plan
-	UpdatePlatform bool
-	Lock           AppLock
-	Pool           string
-	Description    string
-	Router         string
-	RouterOpts     map[string]string
-	Deploys        uint
-	Tags           []string
-	Error          string
-	Routers        []appTypes.AppRouter
+	Env              map[string]bind.EnvVar
+	ServiceEnvs      []bind.ServiceEnvVar
+	Platform         string `bson:"framework"`
+	Name             string
+	CName            []string
+	Teams            []string
+	TeamOwner        string
+	Owner            string
+	Plan             appTypes.Plan
+	UpdatePlatform   bool
+	Lock             AppLock
+	Pool             string
+	Description      string
+	Router           string
+	RouterOpts       map[string]string
+	Deploys          uint
+	Tags             []string
+	Error            string
+	Routers          []appTypes.AppRouter
+	RestartSchedule  string                 `bson:",omitempty"`
+	Maintenance      bool                   `bson:",omitempty"`
+	DocumentationURL string                 `bson:",omitempty"`
+	Annotations      map[string]string      `bson:",omitempty"`
+	BuildEnv         map[string]bind.EnvVar `bson:",omitempty"`
+	ScaleSchedules   []ScaleSchedule        `bson:",omitempty"`
 
 	quota.Quota
 	builder     builder.Builder
@@ -260,6 +274,11 @@ func (app *App) MarshalJSON() ([]byte, error) {
 	result["lock"] = app.Lock
 	result["tags"] = app.Tags
 	result["routers"] = routers
+	result["restartschedule"] = app.RestartSchedule
+	result["maintenance"] = app.Maintenance
+	result["documentationurl"] = app.DocumentationURL
+	result["annotations"] = app.Annotations
+	result["scaleschedules"] = app.ScaleSchedules
 	if len(errMsgs) > 0 {
 		result["error"] = strings.Join(errMsgs, "\n")
 	}
@@ -442,6 +461,81 @@ func CreateApp(app *App, user *auth.User) error {
 	return nil
 }
 
+// Clone creates a new app with the given name, using the same platform,
+// plan, pool, teams, routers, tags, public environment variables and service
+// instances of the app. The output of service bindings is written to w. The
+// new app is removed if any of these can't be copied.
+func (app *App) Clone(name string, user *auth.User, w io.Writer) (*App, error) {
+	newApp := App{
+		Name:        name,
+		Platform:    app.Platform,
+		Plan:        appTypes.Plan{Name: app.Plan.Name},
+		Pool:        app.Pool,
+		TeamOwner:   app.TeamOwner,
+		Description: app.Description,
+		Tags:        append([]string(nil), app.Tags...),
+	}
+	for _, r := range app.GetRouters() {
+		opts := make(map[string]string, len(r.Opts))
+		for k, v := range r.Opts {
+			opts[k] = v
+		}
+		newApp.Routers = append(newApp.Routers, appTypes.AppRouter{Name: r.Name, Opts: opts})
+	}
+	err := CreateApp(&newApp, user)
+	if err != nil {
+		return nil, err
+	}
+	err = app.copyConfigTo(&newApp, w)
+	if err != nil {
+		if delErr := Delete(&newApp, w); delErr != nil {
+			log.Errorf("[clone-app: %s] unable to remove partially cloned app %q: %s", app.Name, newApp.Name, delErr)
+		}
+		return nil, err
+	}
+	return &newApp, nil
+}
+
+// copyConfigTo grants the teams, sets the public environment variables and
+// binds the service instances of the app to newApp. Private environment
+// variables usually hold secrets and are not copied.
+func (app *App) copyConfigTo(newApp *App, w io.Writer) error {
+	for _, teamName := range app.Teams {
+		if teamName == newApp.TeamOwner {
+			continue
+		}
+		team, err := auth.GetTeam(teamName)
+		if err != nil {
+			return err
+		}
+		err = newApp.Grant(team)
+		if err != nil && err != ErrAlreadyHaveAccess {
+			return err
+		}
+	}
+	var envs []bind.EnvVar
+	for _, env := range app.Env {
+		if env.Public && !internalEnvs.Includes(env.Name) {
+			envs = append(envs, env)
+		}
+	}
+	err := newApp.SetEnvs(bind.SetEnvArgs{Envs: envs})
+	if err != nil {
+		return err
+	}
+	instances, err := service.GetServiceInstancesBoundToApp(app.Name)
+	if err != nil {
+		return err
+	}
+	for _, si := range instances {
+		err = si.BindApp(newApp, false, w)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (app *App) configureCreateRouters() error {
 	if len(app.Routers) > 0 {
 		return nil
@@ -481,6 +575,23 @@ func (app *App) Update(updateData App, w io.Writer) (err error) {
 	if description != "" {
 		app.Description = description
 	}
+	if updateData.DocumentationURL != "" {
+		app.DocumentationURL = updateData.DocumentationURL
+	}
+	if len(updateData.Annotations) > 0 {
+		annotations := make(map[string]string, len(app.Annotations)+len(updateData.Annotations))
+		for k, v := range app.Annotations {
+			annotations[k] = v
+		}
+		for k, v := range updateData.Annotations {
+			if v == "" {
+				delete(annotations, k)
+			} else {
+				annotations[k] = v
+			}
+		}
+		app.Annotations = annotations
+	}
 	if poolName != "" {
 		app.Pool = poolName
 		app.provisioner = nil
@@ -781,6 +892,19 @@ func (app *App) UnbindUnit(unit *provision.Unit) error {
 // AddUnits creates n new units within the provisioner, saves new units in the
 // database and enqueues the apprc serialization.
 func (app *App) AddUnits(n uint, process string, w io.Writer) error {
+	return app.addUnits(n, process, "", w)
+}
+
+// AddUnitsOnNode works like AddUnits, but the new units are created in the
+// node with the given address.
+func (app *App) AddUnitsOnNode(n uint, process, node string, w io.Writer) error {
+	if node == "" {
+		return errors.New("node must be set")
+	}
+	return app.addUnits(n, process, node, w)
+}
+
+func (app *App) addUnits(n uint, process, node string, w io.Writer) error {
 	if n == 0 {
 		return errors.New("Cannot add zero units.")
 	}
@@ -794,10 +918,11 @@ func (app *App) AddUnits(n uint, process string, w io.Writer) error {
 		}
 	}
 	w = app.withLogWriter(w)
+	defer flushLog(w)
 	err = action.NewPipeline(
 		&reserveUnitsToAdd,
 		&provisionAddUnits,
-	).Execute(app, n, w, process)
+	).Execute(app, n, w, process, node)
 	rebuild.RoutesRebuildOrEnqueue(app.Name)
 	return err
 }
@@ -813,6 +938,7 @@ func (app *App) RemoveUnits(n uint, process string, w io.Writer) error {
 		return err
 	}
 	w = app.withLogWriter(w)
+	defer flushLog(w)
 	err = prov.RemoveUnits(app, n, process, w)
 	rebuild.RoutesRebuildOrEnqueue(app.Name)
 	if err != nil {
@@ -825,6 +951,31 @@ func (app *App) RemoveUnits(n uint, process string, w io.Writer) error {
 	return app.SetQuotaInUse(len(units))
 }
 
+// RemoveUnitsOnNode works like RemoveUnits, but only units running in the
+// node with the given address are removed.
+func (app *App) RemoveUnitsOnNode(n uint, process, node string, w io.Writer) error {
+	prov, err := app.getProvisioner()
+	if err != nil {
+		return err
+	}
+	nodeProv, ok := prov.(provision.NodeUnitsProvisioner)
+	if !ok {
+		return provision.ProvisionerNotSupported{Prov: prov, Action: "removing units from a node"}
+	}
+	w = app.withLogWriter(w)
+	defer flushLog(w)
+	err = nodeProv.RemoveUnitsOnNode(app, n, process, node, w)
+	rebuild.RoutesRebuildOrEnqueue(app.Name)
+	if err != nil {
+		return err
+	}
+	units, err := app.Units()
+	if err != nil {
+		return err
+	}
+	return app.SetQuotaInUse(len(units))
+}
+
 // SetUnitStatus changes the status of the given unit.
 func (app *App) SetUnitStatus(unitName string, status provision.Status) error {
 	units, err := app.Units()
@@ -1098,9 +1249,30 @@ func (app *App) validate() error {
 			"starting with a letter."
 		return &tsuruErrors.ValidationError{Message: msg}
 	}
+	err := app.validateMetadata()
+	if err != nil {
+		return err
+	}
 	return app.validatePool()
 }
 
+func (app *App) validateMetadata() error {
+	if app.DocumentationURL != "" {
+		u, err := url.Parse(app.DocumentationURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			msg := fmt.Sprintf("Invalid documentation URL %q, it must be an absolute http or https URL.", app.DocumentationURL)
+			return &tsuruErrors.ValidationError{Message: msg}
+		}
+	}
+	for k := range app.Annotations {
+		if k == "" || strings.HasPrefix(k, "$") || strings.Contains(k, ".") {
+			msg := fmt.Sprintf("Invalid annotation key %q, it must not be empty, start with $ or contain dots.", k)
+			return &tsuruErrors.ValidationError{Message: msg}
+		}
+	}
+	return nil
+}
+
 func (app *App) validatePool() error {
 	pool, err := pool.GetPoolByName(app.Pool)
 	if err != nil {
@@ -1208,6 +1380,7 @@ func (app *App) run(cmd string, w io.Writer, args provision.RunArgs) error {
 // Restart runs the restart hook for the app, writing its output to w.
 func (app *App) Restart(process string, w io.Writer) error {
 	w = app.withLogWriter(w)
+	defer flushLog(w)
 	msg := fmt.Sprintf("---- Restarting process %q ----", process)
 	if process == "" {
 		msg = fmt.Sprintf("---- Restarting the app %q ----", app.Name)
@@ -1217,6 +1390,14 @@ func (app *App) Restart(process string, w io.Writer) error {
 	if err != nil {
 		return err
 	}
+	imageID, err := image.AppCurrentImageName(app.Name)
+	if err != nil && err != image.ErrNoImagesAvailable {
+		return err
+	}
+	err = app.waitServiceDependencies(imageID, w)
+	if err != nil {
+		return err
+	}
 	err = prov.Restart(app, process, w)
 	if err != nil {
 		log.Errorf("[restart] error on restart the app %s - %s", app.Name, err)
@@ -1226,8 +1407,45 @@ func (app *App) Restart(process string, w io.Writer) error {
 	return nil
 }
 
+// RestartUnits restarts only the given units of the app, writing the output
+// to w.
+func (app *App) RestartUnits(unitIDs []string, w io.Writer) error {
+	prov, err := app.getProvisioner()
+	if err != nil {
+		return err
+	}
+	restarterProv, ok := prov.(provision.UnitRestarterProvisioner)
+	if !ok {
+		return provision.ProvisionerNotSupported{Prov: prov, Action: "restarting units"}
+	}
+	w = app.withLogWriter(w)
+	defer flushLog(w)
+	fmt.Fprintf(w, "---- Restarting units %s of the app %q ----\n", strings.Join(unitIDs, ", "), app.Name)
+	err = restarterProv.RestartUnits(app, unitIDs, w)
+	if err != nil {
+		log.Errorf("[restart] error on restart units of the app %s - %s", app.Name, err)
+		return err
+	}
+	rebuild.RoutesRebuildOrEnqueue(app.Name)
+	return nil
+}
+
+// UnitsMetrics returns the resource usage of each unit of the app.
+func (app *App) UnitsMetrics() ([]provision.UnitMetrics, error) {
+	prov, err := app.getProvisioner()
+	if err != nil {
+		return nil, err
+	}
+	metricsProv, ok := prov.(provision.MetricsProvisioner)
+	if !ok {
+		return nil, provision.ProvisionerNotSupported{Prov: prov, Action: "unit metrics"}
+	}
+	return metricsProv.UnitsMetrics(app)
+}
+
 func (app *App) Stop(w io.Writer, process string) error {
 	w = app.withLogWriter(w)
+	defer flushLog(w)
 	msg := fmt.Sprintf("\n ---> Stopping the process %q", process)
 	if process == "" {
 		msg = fmt.Sprintf("\n ---> Stopping the app %q", app.Name)
@@ -1255,12 +1473,17 @@ func (app *App) Sleep(w io.Writer, process string, proxyURL *url.URL) error {
 		return provision.ProvisionerNotSupported{Prov: prov, Action: "sleeping"}
 	}
 	w = app.withLogWriter(w)
+	defer flushLog(w)
 	msg := fmt.Sprintf("\n ---> Putting the process %q to sleep", process)
 	if process == "" {
 		msg = fmt.Sprintf("\n ---> Putting the app %q to sleep", app.Name)
 	}
 	fmt.Fprintf(w, "%s\n", msg)
-	routers := app.GetRouters()
+	// Apps in maintenance keep their routes to the maintenance server.
+	var routers []appTypes.AppRouter
+	if !app.Maintenance {
+		routers = app.GetRouters()
+	}
 	for _, appRouter := range routers {
 		var r router.Router
 		r, err = router.Get(appRouter.Name)
@@ -1597,6 +1820,9 @@ func (app *App) RemoveInstance(removeArgs bind.RemoveInstanceArgs) error {
 // Log adds a log message to the app. Specifying a good source is good so the
 // user can filter where the message come from.
 func (app *App) Log(message, source, unit string) error {
+	if replacer := app.privateEnvsReplacer(); replacer != nil {
+		message = replacer.Replace(message)
+	}
 	messages := strings.Split(message, "\n")
 	logs := make([]interface{}, 0, len(messages))
 	for _, msg := range messages {
@@ -1625,36 +1851,22 @@ func (app *App) Log(message, source, unit string) error {
 // LastLogs returns a list of the last `lines` log of the app, matching the
 // fields in the log instance received as an example.
 func (app *App) LastLogs(lines int, filterLog Applog) ([]Applog, error) {
-	prov, err := app.getProvisioner()
-	if err != nil {
+	return app.PreviousLogs(lines, 0, filterLog)
+}
+
+// PreviousLogs is like LastLogs, but ignores the `skip` most recent logs,
+// allowing clients to page through the log history.
+func (app *App) PreviousLogs(lines, skip int, filterLog Applog) ([]Applog, error) {
+	if err := app.checkLogsEnabled(); err != nil {
 		return nil, err
 	}
-	logsProvisioner, ok := prov.(provision.OptionalLogsProvisioner)
-	if ok {
-		var enabled bool
-		var doc string
-		enabled, doc, err = logsProvisioner.LogsEnabled(app)
-		if err != nil {
-			return nil, err
-		}
-		if !enabled {
-			return nil, errors.New(doc)
-		}
-	}
 	conn, err := db.LogConn()
 	if err != nil {
 		return nil, err
 	}
 	defer conn.Close()
 	logs := []Applog{}
-	q := bson.M{}
-	if filterLog.Source != "" {
-		q["source"] = filterLog.Source
-	}
-	if filterLog.Unit != "" {
-		q["unit"] = filterLog.Unit
-	}
-	err = conn.Logs(app.Name).Find(q).Sort("-$natural").Limit(lines).All(&logs)
+	err = conn.Logs(app.Name).Find(logsQuery(filterLog)).Sort("-$natural").Skip(skip).Limit(lines).All(&logs)
 	if err != nil {
 		return nil, err
 	}
@@ -1665,6 +1877,49 @@ func (app *App) LastLogs(lines int, filterLog Applog) ([]Applog, error) {
 	return logs, nil
 }
 
+// CountLogs returns the number of logs of the app matching the filter.
+func (app *App) CountLogs(filterLog Applog) (int, error) {
+	if err := app.checkLogsEnabled(); err != nil {
+		return 0, err
+	}
+	conn, err := db.LogConn()
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+	return conn.Logs(app.Name).Find(logsQuery(filterLog)).Count()
+}
+
+func (app *App) checkLogsEnabled() error {
+	prov, err := app.getProvisioner()
+	if err != nil {
+		return err
+	}
+	logsProvisioner, ok := prov.(provision.OptionalLogsProvisioner)
+	if !ok {
+		return nil
+	}
+	enabled, doc, err := logsProvisioner.LogsEnabled(app)
+	if err != nil {
+		return err
+	}
+	if !enabled {
+		return errors.New(doc)
+	}
+	return nil
+}
+
+func logsQuery(filterLog Applog) bson.M {
+	q := bson.M{}
+	if filterLog.Source != "" {
+		q["source"] = filterLog.Source
+	}
+	if filterLog.Unit != "" {
+		q["unit"] = filterLog.Unit
+	}
+	return q
+}
+
 type Filter struct {
 	Name        string
 	NameMatches string
@@ -1676,7 +1931,62 @@ type Filter struct {
 	Statuses    []string
 	Locked      bool
 	Tags        []string
+	Team        string
 	Extra       map[string][]string
+	// Sort is the field used to sort the apps, one of name, platform, pool,
+	// teamowner or owner, prefixed with "-" for descending order.
+	Sort string
+}
+
+// appSortFields maps the fields accepted in Filter.Sort to the database
+// fields and to their values, used when apps are sorted in memory.
+var appSortFields = map[string]struct {
+	dbField string
+	value   func(*App) string
+}{
+	"name":      {"name", func(a *App) string { return a.Name }},
+	"platform":  {"framework", func(a *App) string { return a.Platform }},
+	"pool":      {"pool", func(a *App) string { return a.Pool }},
+	"teamowner": {"teamowner", func(a *App) string { return a.TeamOwner }},
+	"owner":     {"owner", func(a *App) string { return a.Owner }},
+}
+
+func (f *Filter) sortFields() ([]string, error) {
+	if f == nil || f.Sort == "" {
+		return nil, nil
+	}
+	name := strings.TrimPrefix(f.Sort, "-")
+	field, ok := appSortFields[name]
+	if !ok {
+		return nil, &tsuruErrors.ValidationError{Message: fmt.Sprintf("invalid sort field %q", name)}
+	}
+	fields := []string{field.dbField}
+	if name != f.Sort {
+		fields[0] = "-" + fields[0]
+	}
+	if name != "name" {
+		fields = append(fields, "name")
+	}
+	return fields, nil
+}
+
+func (f *Filter) sortApps(apps []App) {
+	if f == nil || f.Sort == "" {
+		return
+	}
+	name := strings.TrimPrefix(f.Sort, "-")
+	desc := name != f.Sort
+	value := appSortFields[name].value
+	sort.SliceStable(apps, func(i, j int) bool {
+		vi, vj := value(&apps[i]), value(&apps[j])
+		if vi == vj {
+			return apps[i].Name < apps[j].Name
+		}
+		if desc {
+			return vi > vj
+		}
+		return vi < vj
+	})
 }
 
 func (f *Filter) ExtraIn(name string, value string) {
@@ -1709,6 +2019,9 @@ func (f *Filter) Query() bson.M {
 	if f.TeamOwner != "" {
 		query["teamowner"] = f.TeamOwner
 	}
+	if f.Team != "" {
+		query["teams"] = f.Team
+	}
 	if f.Platform != "" {
 		query["framework"] = f.Platform
 	}
@@ -1797,11 +2110,19 @@ func Units(apps []App) (map[string]AppUnitsResponse, error) {
 func List(filter *Filter) ([]App, error) {
 	apps := []App{}
 	query := filter.Query()
+	sortFields, err := filter.sortFields()
+	if err != nil {
+		return nil, err
+	}
 	conn, err := db.Conn()
 	if err != nil {
 		return nil, err
 	}
-	err = conn.Apps().Find(query).All(&apps)
+	dbQuery := conn.Apps().Find(query)
+	if len(sortFields) > 0 {
+		dbQuery = dbQuery.Sort(sortFields...)
+	}
+	err = dbQuery.All(&apps)
 	conn.Close()
 	if err != nil {
 		return nil, err
@@ -1835,6 +2156,7 @@ func List(filter *Filter) ([]App, error) {
 			apps[i] = *(provisionApps[i].(*App))
 		}
 		apps = apps[:len(provisionApps)]
+		filter.sortApps(apps)
 	}
 	err = loadCachedAddrsInApps(apps)
 	if err != nil {
@@ -1843,6 +2165,61 @@ func List(filter *Filter) ([]App, error) {
 	return apps, nil
 }
 
+// ListPaginated is like List, but returns at most limit apps, sorted by name
+// unless filter.Sort is set, after skipping the first skip ones. The total
+// number of apps matching the filter is also returned.
+func ListPaginated(filter *Filter, skip, limit int) ([]App, int, error) {
+	if filter != nil && len(filter.Statuses) > 0 {
+		// Unit statuses are only known by the provisioners, so apps are
+		// filtered before being paginated.
+		apps, err := List(filter)
+		if err != nil {
+			return nil, 0, err
+		}
+		if filter.Sort == "" {
+			sort.Slice(apps, func(i, j int) bool {
+				return apps[i].Name < apps[j].Name
+			})
+		}
+		total := len(apps)
+		if skip > total {
+			skip = total
+		}
+		apps = apps[skip:]
+		if len(apps) > limit {
+			apps = apps[:limit]
+		}
+		return apps, total, nil
+	}
+	conn, err := db.Conn()
+	if err != nil {
+		return nil, 0, err
+	}
+	defer conn.Close()
+	query := filter.Query()
+	sortFields, err := filter.sortFields()
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(sortFields) == 0 {
+		sortFields = []string{"name"}
+	}
+	total, err := conn.Apps().Find(query).Count()
+	if err != nil {
+		return nil, 0, err
+	}
+	apps := []App{}
+	err = conn.Apps().Find(query).Sort(sortFields...).Skip(skip).Limit(limit).All(&apps)
+	if err != nil {
+		return nil, 0, err
+	}
+	err = loadCachedAddrsInApps(apps)
+	if err != nil {
+		return nil, 0, err
+	}
+	return apps, total, nil
+}
+
 func appRouterAddrKey(appName, routerName string) string {
 	return strings.Join([]string{"app-router-addr", appName, routerName}, "\x00")
 }
@@ -1940,6 +2317,7 @@ func Swap(app1, app2 *App, cnameOnly bool) error {
 // changing the units state to StatusStarted.
 func (app *App) Start(w io.Writer, process string) error {
 	w = app.withLogWriter(w)
+	defer flushLog(w)
 	msg := fmt.Sprintf("\n ---> Starting the process %q", process)
 	if process == "" {
 		msg = fmt.Sprintf("\n ---> Starting the app %q", app.Name)
@@ -2305,6 +2683,13 @@ func (e *ProcfileError) Error() string {
 }
 
 func (app *App) RoutableAddresses() ([]url.URL, error) {
+	if app.Maintenance {
+		addr, err := maintenanceAddress()
+		if err != nil {
+			return nil, err
+		}
+		return []url.URL{*addr}, nil
+	}
 	prov, err := app.getProvisioner()
 	if err != nil {
 		return nil, err
@@ -2327,7 +2712,55 @@ func (app *App) withLogWriter(w io.Writer) io.Writer {
 	} else {
 		w = logWriter
 	}
-	return w
+	return app.redactPrivateEnvs(w)
+}
+
+// privateEnvValues returns the values of the private environment variables
+// of the app, longest first. Values shorter than minRedactedEnvLength are
+// skipped, as masking them would mangle unrelated output.
+func (app *App) privateEnvValues() []string {
+	var values []string
+	for _, e := range app.Env {
+		if !e.Public && len(e.Value) >= minRedactedEnvLength {
+			values = append(values, e.Value)
+		}
+	}
+	for _, e := range app.ServiceEnvs {
+		if !e.Public && len(e.Value) >= minRedactedEnvLength {
+			values = append(values, e.Value)
+		}
+	}
+	sort.Slice(values, func(i, j int) bool {
+		return len(values[i]) > len(values[j])
+	})
+	return values
+}
+
+// privateEnvsReplacer returns a replacer hiding the values of the private
+// environment variables of the app, or nil if there's nothing to hide.
+func (app *App) privateEnvsReplacer() *strings.Replacer {
+	return newRedactReplacer(app.privateEnvValues())
+}
+
+func newRedactReplacer(values []string) *strings.Replacer {
+	if len(values) == 0 {
+		return nil
+	}
+	oldnew := make([]string, 0, len(values)*2)
+	for _, v := range values {
+		oldnew = append(oldnew, v, redactedEnvValue)
+	}
+	return strings.NewReplacer(oldnew...)
+}
+
+// redactPrivateEnvs wraps w hiding the values of the private environment
+// variables of the app.
+func (app *App) redactPrivateEnvs(w io.Writer) io.Writer {
+	values := app.privateEnvValues()
+	if len(values) == 0 {
+		return w
+	}
+	return &redactWriter{w: w, values: values, replacer: newRedactReplacer(values)}
 }
 
 func RenameTeam(oldName, newName string) error {
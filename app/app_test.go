@@ -14,6 +14,7 @@ import (
 	"net/url"
 	"sort"
 	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -442,6 +443,105 @@ func (s *S) TestCreateAppRepositoryManagerFailure(c *check.C) {
 	c.Assert(count, check.Equals, 0)
 }
 
+func (s *S) TestCloneApp(c *check.C) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"DATABASE_HOST":"localhost"}`))
+	}))
+	defer server.Close()
+	srvc := service.Service{Name: "mysql", Endpoint: map[string]string{"production": server.URL}, Password: "abcde", OwnerTeams: []string{s.team.Name}}
+	err := srvc.Create()
+	c.Assert(err, check.IsNil)
+	otherTeam := authTypes.Team{Name: "other-team"}
+	err = auth.TeamService().Insert(otherTeam)
+	c.Assert(err, check.IsNil)
+	a := App{
+		Name:        "myapp",
+		Platform:    "python",
+		TeamOwner:   s.team.Name,
+		Description: "my app",
+		Tags:        []string{"tag1"},
+		Routers:     []appTypes.AppRouter{{Name: "fake", Opts: map[string]string{"a": "b"}}},
+	}
+	err = CreateApp(&a, s.user)
+	c.Assert(err, check.IsNil)
+	err = a.Grant(&otherTeam)
+	c.Assert(err, check.IsNil)
+	err = a.SetEnvs(bind.SetEnvArgs{Envs: []bind.EnvVar{
+		{Name: "PUBLIC", Value: "1", Public: true},
+		{Name: "PRIVATE", Value: "2"},
+	}})
+	c.Assert(err, check.IsNil)
+	instance := service.ServiceInstance{
+		Name:        "my-inst",
+		ServiceName: "mysql",
+		Teams:       []string{s.team.Name},
+		Apps:        []string{a.Name},
+	}
+	err = s.conn.ServiceInstances().Insert(instance)
+	c.Assert(err, check.IsNil)
+	newApp, err := a.Clone("myapp-staging", s.user, nil)
+	c.Assert(err, check.IsNil)
+	dbApp, err := GetByName("myapp-staging")
+	c.Assert(err, check.IsNil)
+	c.Assert(dbApp.Name, check.Equals, newApp.Name)
+	c.Assert(dbApp.Platform, check.Equals, "python")
+	c.Assert(dbApp.Plan.Name, check.Equals, a.Plan.Name)
+	c.Assert(dbApp.Pool, check.Equals, a.Pool)
+	c.Assert(dbApp.Description, check.Equals, "my app")
+	c.Assert(dbApp.Tags, check.DeepEquals, []string{"tag1"})
+	c.Assert(dbApp.TeamOwner, check.Equals, s.team.Name)
+	c.Assert(dbApp.Teams, check.DeepEquals, []string{s.team.Name, otherTeam.Name})
+	c.Assert(dbApp.GetRouters(), check.DeepEquals, []appTypes.AppRouter{{Name: "fake", Opts: map[string]string{"a": "b"}}})
+	c.Assert(dbApp.Env["PUBLIC"], check.DeepEquals, bind.EnvVar{Name: "PUBLIC", Value: "1", Public: true})
+	_, ok := dbApp.Env["PRIVATE"]
+	c.Assert(ok, check.Equals, false)
+	c.Assert(dbApp.Env["TSURU_APPNAME"].Value, check.Equals, "myapp-staging")
+	c.Assert(dbApp.Env["TSURU_APP_TOKEN"].Value, check.Not(check.Equals), a.Env["TSURU_APP_TOKEN"].Value)
+	c.Assert(dbApp.InstanceEnvs("mysql", "my-inst"), check.HasLen, 1)
+	si, err := service.GetServiceInstance("mysql", "my-inst")
+	c.Assert(err, check.IsNil)
+	c.Assert(si.Apps, check.DeepEquals, []string{a.Name, "myapp-staging"})
+}
+
+func (s *S) TestCloneAppRemovesCloneOnError(c *check.C) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("bind failed"))
+	}))
+	defer server.Close()
+	srvc := service.Service{Name: "mysql", Endpoint: map[string]string{"production": server.URL}, Password: "abcde", OwnerTeams: []string{s.team.Name}}
+	err := srvc.Create()
+	c.Assert(err, check.IsNil)
+	a := App{Name: "myapp", Platform: "python", TeamOwner: s.team.Name}
+	err = CreateApp(&a, s.user)
+	c.Assert(err, check.IsNil)
+	instance := service.ServiceInstance{
+		Name:        "my-inst",
+		ServiceName: "mysql",
+		Teams:       []string{s.team.Name},
+		Apps:        []string{a.Name},
+	}
+	err = s.conn.ServiceInstances().Insert(instance)
+	c.Assert(err, check.IsNil)
+	_, err = a.Clone("myapp-staging", s.user, nil)
+	c.Assert(err, check.NotNil)
+	_, err = GetByName("myapp-staging")
+	c.Assert(err, check.Equals, ErrAppNotFound)
+	si, err := service.GetServiceInstance("mysql", "my-inst")
+	c.Assert(err, check.IsNil)
+	c.Assert(si.Apps, check.DeepEquals, []string{a.Name})
+}
+
+func (s *S) TestCloneAppAlreadyExists(c *check.C) {
+	a := App{Name: "myapp", Platform: "python", TeamOwner: s.team.Name}
+	err := CreateApp(&a, s.user)
+	c.Assert(err, check.IsNil)
+	_, err = a.Clone("myapp", s.user, nil)
+	c.Assert(err, check.FitsTypeOf, &AppCreationError{})
+	c.Assert(err.(*AppCreationError).Err, check.Equals, ErrAppAlreadyExists)
+}
+
 func (s *S) TestBindAndUnbindUnit(c *check.C) {
 	var requests []*http.Request
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -2167,6 +2267,45 @@ func (s *S) TestRestart(c *check.C) {
 	c.Assert(restarts, check.Equals, 1)
 }
 
+func (s *S) TestRestartUnits(c *check.C) {
+	a := App{Name: "someapp", Platform: "django", TeamOwner: s.team.Name}
+	err := CreateApp(&a, s.user)
+	c.Assert(err, check.IsNil)
+	s.provisioner.AddUnits(&a, 2, "web", nil)
+	units, err := a.Units()
+	c.Assert(err, check.IsNil)
+	var b bytes.Buffer
+	err = a.RestartUnits([]string{units[1].ID}, &b)
+	c.Assert(err, check.IsNil)
+	c.Assert(b.String(), check.Matches, `(?s).*---- Restarting units `+units[1].ID+` of the app "someapp" ----.*`)
+	c.Assert(s.provisioner.UnitRestarts(&a, units[0].ID), check.Equals, 0)
+	c.Assert(s.provisioner.UnitRestarts(&a, units[1].ID), check.Equals, 1)
+	c.Assert(s.provisioner.Restarts(&a, ""), check.Equals, 0)
+}
+
+func (s *S) TestRestartUnitsNotFound(c *check.C) {
+	a := App{Name: "someapp", Platform: "django", TeamOwner: s.team.Name}
+	err := CreateApp(&a, s.user)
+	c.Assert(err, check.IsNil)
+	err = a.RestartUnits([]string{"unknown"}, nil)
+	c.Assert(err, check.DeepEquals, &provision.UnitNotFoundError{ID: "unknown"})
+}
+
+func (s *S) TestUnitsMetrics(c *check.C) {
+	a := App{Name: "someapp", Platform: "django", TeamOwner: s.team.Name}
+	err := CreateApp(&a, s.user)
+	c.Assert(err, check.IsNil)
+	s.provisioner.AddUnits(&a, 2, "web", nil)
+	units, err := a.Units()
+	c.Assert(err, check.IsNil)
+	metrics, err := a.UnitsMetrics()
+	c.Assert(err, check.IsNil)
+	c.Assert(metrics, check.HasLen, 2)
+	c.Assert(metrics[0].ID, check.Equals, units[0].ID)
+	c.Assert(metrics[1].ID, check.Equals, units[1].ID)
+	c.Assert(metrics[0].ProcessName, check.Equals, "web")
+}
+
 func (s *S) TestStop(c *check.C) {
 	a := App{Name: "app", TeamOwner: s.team.Name}
 	err := CreateApp(&a, s.user)
@@ -2245,6 +2384,118 @@ func (s *S) TestLogShouldAddOneRecordByLine(c *check.C) {
 	c.Assert(logs[1].Message, check.Equals, "first log")
 }
 
+func (s *S) TestLogRedactsPrivateEnvs(c *check.C) {
+	a := App{Name: "new-app", TeamOwner: s.team.Name}
+	err := CreateApp(&a, s.user)
+	c.Assert(err, check.IsNil)
+	defer func() {
+		s.logConn.Logs(a.Name).DropCollection()
+	}()
+	a.Env = map[string]bind.EnvVar{
+		"DB_PASSWORD": {Name: "DB_PASSWORD", Value: "s3cr3t-pass", Public: false},
+		"DB_USER":     {Name: "DB_USER", Value: "admin", Public: true},
+		"SHORT":       {Name: "SHORT", Value: "ab", Public: false},
+	}
+	a.ServiceEnvs = []bind.ServiceEnvVar{
+		{EnvVar: bind.EnvVar{Name: "MYSQL_PASSWORD", Value: "mysql-secret"}, ServiceName: "mysql", InstanceName: "db"},
+	}
+	err = a.Log("connecting admin:s3cr3t-pass ab\nusing mysql-secret", "tsuru", "machine")
+	c.Assert(err, check.IsNil)
+	var logs []Applog
+	err = s.logConn.Logs(a.Name).Find(nil).Sort("$natural").All(&logs)
+	c.Assert(err, check.IsNil)
+	c.Assert(logs, check.HasLen, 2)
+	c.Assert(logs[0].Message, check.Equals, "connecting admin:***** ab")
+	c.Assert(logs[1].Message, check.Equals, "using *****")
+}
+
+func (s *S) TestRedactPrivateEnvs(c *check.C) {
+	a := App{
+		Name: "myapp",
+		Env: map[string]bind.EnvVar{
+			"TOKEN":     {Name: "TOKEN", Value: "abc123", Public: false},
+			"TOKEN_EXT": {Name: "TOKEN_EXT", Value: "abc123456", Public: false},
+		},
+	}
+	var buf bytes.Buffer
+	w := a.redactPrivateEnvs(&buf)
+	n, err := w.Write([]byte("token abc123 and abc123456\n"))
+	c.Assert(err, check.IsNil)
+	c.Assert(n, check.Equals, 27)
+	c.Assert(buf.String(), check.Equals, "token ***** and *****\n")
+}
+
+func (s *S) TestRedactPrivateEnvsSplitWrites(c *check.C) {
+	a := App{
+		Name: "myapp",
+		Env: map[string]bind.EnvVar{
+			"TOKEN": {Name: "TOKEN", Value: "abc123", Public: false},
+		},
+	}
+	var buf bytes.Buffer
+	w := a.redactPrivateEnvs(&buf)
+	n, err := w.Write([]byte("token ab"))
+	c.Assert(err, check.IsNil)
+	c.Assert(n, check.Equals, 8)
+	c.Assert(buf.String(), check.Equals, "token ")
+	n, err = w.Write([]byte("c123 and abx\n"))
+	c.Assert(err, check.IsNil)
+	c.Assert(n, check.Equals, 13)
+	c.Assert(buf.String(), check.Equals, "token ***** and abx\n")
+}
+
+func (s *S) TestRedactPrivateEnvsFlush(c *check.C) {
+	a := App{
+		Name: "myapp",
+		Env: map[string]bind.EnvVar{
+			"TOKEN": {Name: "TOKEN", Value: "abc123", Public: false},
+		},
+	}
+	var buf bytes.Buffer
+	w := a.redactPrivateEnvs(&buf)
+	_, err := w.Write([]byte("token ab"))
+	c.Assert(err, check.IsNil)
+	c.Assert(buf.String(), check.Equals, "token ")
+	flushLog(w)
+	c.Assert(buf.String(), check.Equals, "token ab")
+	flushLog(w)
+	c.Assert(buf.String(), check.Equals, "token ab")
+}
+
+func (s *S) TestRedactPrivateEnvsConcurrentWrites(c *check.C) {
+	a := App{
+		Name: "myapp",
+		Env: map[string]bind.EnvVar{
+			"TOKEN": {Name: "TOKEN", Value: "abc123", Public: false},
+		},
+	}
+	var buf safe.Buffer
+	w := a.redactPrivateEnvs(&buf)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w.Write([]byte("token abc123\n"))
+		}()
+	}
+	wg.Wait()
+	flushLog(w)
+	c.Assert(buf.String(), check.Equals, strings.Repeat("token *****\n", 10))
+}
+
+func (s *S) TestRedactPrivateEnvsNoPrivateEnvs(c *check.C) {
+	a := App{
+		Name: "myapp",
+		Env: map[string]bind.EnvVar{
+			"TOKEN": {Name: "TOKEN", Value: "abc123", Public: true},
+		},
+	}
+	var buf bytes.Buffer
+	w := a.redactPrivateEnvs(&buf)
+	c.Assert(w, check.Equals, &buf)
+}
+
 func (s *S) TestLogShouldNotLogBlankLines(c *check.C) {
 	a := App{Name: "ich", TeamOwner: s.team.Name}
 	err := CreateApp(&a, s.user)
@@ -2337,6 +2588,46 @@ func (s *S) TestLastLogs(c *check.C) {
 	}
 }
 
+func (s *S) TestPreviousLogs(c *check.C) {
+	app := App{
+		Name:      "app3",
+		Platform:  "vougan",
+		TeamOwner: s.team.Name,
+	}
+	err := CreateApp(&app, s.user)
+	c.Assert(err, check.IsNil)
+	for i := 0; i < 15; i++ {
+		app.Log(strconv.Itoa(i), "tsuru", "rdaneel")
+		time.Sleep(1e6) // let the time flow
+	}
+	logs, err := app.PreviousLogs(5, 8, Applog{Source: "tsuru"})
+	c.Assert(err, check.IsNil)
+	c.Assert(logs, check.HasLen, 5)
+	for i := 2; i < 7; i++ {
+		c.Check(logs[i-2].Message, check.Equals, strconv.Itoa(i))
+	}
+}
+
+func (s *S) TestCountLogs(c *check.C) {
+	app := App{
+		Name:      "app3",
+		Platform:  "vougan",
+		TeamOwner: s.team.Name,
+	}
+	err := CreateApp(&app, s.user)
+	c.Assert(err, check.IsNil)
+	for i := 0; i < 5; i++ {
+		app.Log(strconv.Itoa(i), "tsuru", "rdaneel")
+	}
+	app.Log("other", "app", "rdaneel")
+	total, err := app.CountLogs(Applog{Source: "tsuru"})
+	c.Assert(err, check.IsNil)
+	c.Assert(total, check.Equals, 5)
+	total, err = app.CountLogs(Applog{})
+	c.Assert(err, check.IsNil)
+	c.Assert(total, check.Equals, 6)
+}
+
 func (s *S) TestLastLogsUnitFilter(c *check.C) {
 	app := App{
 		Name:      "app3",
@@ -2473,7 +2764,12 @@ func (s *S) TestAppMarshalJSON(c *check.C) {
 				"opts":    map[string]interface{}{"opt1": "val1"},
 			},
 		},
-		"tags": []interface{}{"tag a", "tag b"},
+		"tags":             []interface{}{"tag a", "tag b"},
+		"restartschedule":  "",
+		"maintenance":      false,
+		"documentationurl": "",
+		"annotations":      nil,
+		"scaleschedules":   nil,
 	}
 	data, err := app.MarshalJSON()
 	c.Assert(err, check.IsNil)
@@ -2531,7 +2827,12 @@ func (s *S) TestAppMarshalJSONWithoutRepository(c *check.C) {
 				"opts":    map[string]interface{}{},
 			},
 		},
-		"tags": []interface{}{},
+		"tags":             []interface{}{},
+		"restartschedule":  "",
+		"maintenance":      false,
+		"documentationurl": "",
+		"annotations":      nil,
+		"scaleschedules":   nil,
 	}
 	data, err := app.MarshalJSON()
 	c.Assert(err, check.IsNil)
@@ -2581,7 +2882,12 @@ func (s *S) TestAppMarshalJSONUnitsError(c *check.C) {
 				"opts":    map[string]interface{}{},
 			},
 		},
-		"tags": nil,
+		"tags":             nil,
+		"restartschedule":  "",
+		"maintenance":      false,
+		"documentationurl": "",
+		"annotations":      nil,
+		"scaleschedules":   nil,
 	}
 	data, err := app.MarshalJSON()
 	c.Assert(err, check.IsNil)
@@ -2953,6 +3259,48 @@ func (s *S) TestListAll(c *check.C) {
 	c.Assert(apps, check.HasLen, 2)
 }
 
+func (s *S) TestListPaginated(c *check.C) {
+	for _, name := range []string{"app3", "app1", "app2"} {
+		err := s.conn.Apps().Insert(App{Name: name, Teams: []string{s.team.Name}})
+		c.Assert(err, check.IsNil)
+	}
+	apps, total, err := ListPaginated(nil, 0, 2)
+	c.Assert(err, check.IsNil)
+	c.Assert(total, check.Equals, 3)
+	c.Assert(apps, check.HasLen, 2)
+	c.Assert(apps[0].Name, check.Equals, "app1")
+	c.Assert(apps[1].Name, check.Equals, "app2")
+	apps, total, err = ListPaginated(nil, 2, 2)
+	c.Assert(err, check.IsNil)
+	c.Assert(total, check.Equals, 3)
+	c.Assert(apps, check.HasLen, 1)
+	c.Assert(apps[0].Name, check.Equals, "app3")
+	apps, total, err = ListPaginated(&Filter{Name: "app2"}, 0, 2)
+	c.Assert(err, check.IsNil)
+	c.Assert(total, check.Equals, 1)
+	c.Assert(apps, check.HasLen, 1)
+}
+
+func (s *S) TestListPaginatedFilteringByStatuses(c *check.C) {
+	for _, name := range []string{"app3", "app1", "app2"} {
+		a := App{Name: name, TeamOwner: s.team.Name}
+		err := CreateApp(&a, s.user)
+		c.Assert(err, check.IsNil)
+		err = a.AddUnits(1, "web", nil)
+		c.Assert(err, check.IsNil)
+	}
+	apps, total, err := ListPaginated(&Filter{Statuses: []string{"started"}}, 1, 5)
+	c.Assert(err, check.IsNil)
+	c.Assert(total, check.Equals, 3)
+	c.Assert(apps, check.HasLen, 2)
+	c.Assert(apps[0].Name, check.Equals, "app2")
+	c.Assert(apps[1].Name, check.Equals, "app3")
+	apps, total, err = ListPaginated(&Filter{Statuses: []string{"started"}}, 5, 5)
+	c.Assert(err, check.IsNil)
+	c.Assert(total, check.Equals, 3)
+	c.Assert(apps, check.HasLen, 0)
+}
+
 func (s *S) TestListUsesCachedRouterAddrs(c *check.C) {
 	a := App{
 		Name:      "app1",
@@ -3117,6 +3465,68 @@ func (s *S) TestListFilteringByNameMatch(c *check.C) {
 	c.Assert(apps, check.HasLen, 2)
 }
 
+func (s *S) TestListFilteringByTeam(c *check.C) {
+	a := App{Name: "app1", Teams: []string{s.team.Name}}
+	a2 := App{Name: "app2", Teams: []string{"otherteam", s.team.Name}}
+	a3 := App{Name: "app3", Teams: []string{"otherteam"}}
+	for _, app := range []App{a, a2, a3} {
+		err := s.conn.Apps().Insert(app)
+		c.Assert(err, check.IsNil)
+	}
+	apps, err := List(&Filter{Team: "otherteam"})
+	c.Assert(err, check.IsNil)
+	c.Assert(apps, check.HasLen, 2)
+	names := []string{apps[0].Name, apps[1].Name}
+	sort.Strings(names)
+	c.Assert(names, check.DeepEquals, []string{"app2", "app3"})
+}
+
+func (s *S) TestListSorted(c *check.C) {
+	a := App{Name: "app1", Pool: "pool2", Teams: []string{s.team.Name}}
+	a2 := App{Name: "app2", Pool: "pool1", Teams: []string{s.team.Name}}
+	a3 := App{Name: "app3", Pool: "pool2", Teams: []string{s.team.Name}}
+	for _, app := range []App{a3, a, a2} {
+		err := s.conn.Apps().Insert(app)
+		c.Assert(err, check.IsNil)
+	}
+	tests := []struct {
+		sort     string
+		expected []string
+	}{
+		{"name", []string{"app1", "app2", "app3"}},
+		{"-name", []string{"app3", "app2", "app1"}},
+		{"pool", []string{"app2", "app1", "app3"}},
+		{"-pool", []string{"app1", "app3", "app2"}},
+	}
+	for _, tt := range tests {
+		apps, err := List(&Filter{Sort: tt.sort})
+		c.Assert(err, check.IsNil)
+		var names []string
+		for _, a := range apps {
+			names = append(names, a.Name)
+		}
+		c.Check(names, check.DeepEquals, tt.expected, check.Commentf("sort: %s", tt.sort))
+	}
+	_, err := List(&Filter{Sort: "units"})
+	c.Assert(err, check.FitsTypeOf, &errors.ValidationError{})
+}
+
+func (s *S) TestListSortedFilteringByStatuses(c *check.C) {
+	for _, name := range []string{"app1", "app3", "app2"} {
+		a := App{Name: name, TeamOwner: s.team.Name}
+		err := CreateApp(&a, s.user)
+		c.Assert(err, check.IsNil)
+		err = a.AddUnits(1, "web", nil)
+		c.Assert(err, check.IsNil)
+	}
+	apps, err := List(&Filter{Statuses: []string{"started"}, Sort: "-name"})
+	c.Assert(err, check.IsNil)
+	c.Assert(apps, check.HasLen, 3)
+	c.Assert(apps[0].Name, check.Equals, "app3")
+	c.Assert(apps[1].Name, check.Equals, "app2")
+	c.Assert(apps[2].Name, check.Equals, "app1")
+}
+
 func (s *S) TestListFilteringByNameExact(c *check.C) {
 	a := App{
 		Name:      "app1",
@@ -4302,6 +4712,52 @@ func (s *S) TestUpdateDescription(c *check.C) {
 	c.Assert(dbApp.Description, check.Equals, "bleble")
 }
 
+func (s *S) TestUpdateDocumentationURLAndAnnotations(c *check.C) {
+	app := App{
+		Name:        "example",
+		Platform:    "python",
+		TeamOwner:   s.team.Name,
+		Annotations: map[string]string{"owner": "team-a", "tier": "2"},
+	}
+	err := CreateApp(&app, s.user)
+	c.Assert(err, check.IsNil)
+	updateData := App{
+		Name:             "example",
+		DocumentationURL: "https://wiki.example.com/example",
+		Annotations:      map[string]string{"owner": "team-b", "tier": "", "oncall": "#example"},
+	}
+	err = app.Update(updateData, new(bytes.Buffer))
+	c.Assert(err, check.IsNil)
+	dbApp, err := GetByName(app.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbApp.DocumentationURL, check.Equals, "https://wiki.example.com/example")
+	c.Assert(dbApp.Annotations, check.DeepEquals, map[string]string{"owner": "team-b", "oncall": "#example"})
+}
+
+func (s *S) TestUpdateInvalidDocumentationURL(c *check.C) {
+	app := App{Name: "example", Platform: "python", TeamOwner: s.team.Name}
+	err := CreateApp(&app, s.user)
+	c.Assert(err, check.IsNil)
+	updateData := App{Name: "example", DocumentationURL: "wiki/example"}
+	err = app.Update(updateData, new(bytes.Buffer))
+	c.Assert(err, check.FitsTypeOf, &errors.ValidationError{})
+	dbApp, err := GetByName(app.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbApp.DocumentationURL, check.Equals, "")
+}
+
+func (s *S) TestCreateAppInvalidAnnotation(c *check.C) {
+	app := App{
+		Name:        "example",
+		Platform:    "python",
+		TeamOwner:   s.team.Name,
+		Annotations: map[string]string{"$where": "x"},
+	}
+	err := CreateApp(&app, s.user)
+	c.Assert(err, check.FitsTypeOf, &errors.ValidationError{})
+	c.Assert(err, check.ErrorMatches, `Invalid annotation key "\$where".*`)
+}
+
 func (s *S) TestUpdatePlatformLanguage(c *check.C) {
 	app := App{Name: "example", Platform: "python", TeamOwner: s.team.Name}
 	err := CreateApp(&app, s.user)
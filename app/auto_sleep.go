@@ -0,0 +1,234 @@
+// Copyright 2018 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package app
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+	"github.com/pkg/errors"
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/db"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/log"
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/router"
+)
+
+const autoSleepKind = "auto-sleep"
+
+// sleepPolicy puts apps to sleep after IdleDays days without receiving
+// requests. Pool and Team restrict the apps affected by the policy, empty
+// values match any app.
+type sleepPolicy struct {
+	Pool     string
+	Team     string
+	IdleDays int
+}
+
+func (p *sleepPolicy) match(a *App) bool {
+	return (p.Pool == "" || p.Pool == a.Pool) && (p.Team == "" || p.Team == a.TeamOwner)
+}
+
+// autoSleepConfig loads the auto-sleep settings from the config file. A nil
+// proxy URL is returned when auto-sleep is not configured.
+func autoSleepConfig() (*url.URL, []sleepPolicy, error) {
+	rawProxy, err := config.GetString("auto-sleep:proxy-url")
+	if err != nil {
+		return nil, nil, nil
+	}
+	proxyURL, err := url.Parse(rawProxy)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "invalid auto-sleep:proxy-url")
+	}
+	rawPolicies, _ := config.Get("auto-sleep:policies")
+	list, _ := rawPolicies.([]interface{})
+	policies := make([]sleepPolicy, 0, len(list))
+	for i, item := range list {
+		fields, ok := item.(map[interface{}]interface{})
+		if !ok {
+			return nil, nil, errors.Errorf("invalid auto-sleep policy %d: %v", i, item)
+		}
+		var policy sleepPolicy
+		policy.Pool, _ = fields["pool"].(string)
+		policy.Team, _ = fields["team"].(string)
+		policy.IdleDays, _ = fields["idle-days"].(int)
+		if policy.IdleDays <= 0 {
+			return nil, nil, errors.Errorf("invalid auto-sleep policy %d: idle-days must be greater than zero", i)
+		}
+		policies = append(policies, policy)
+	}
+	return proxyURL, policies, nil
+}
+
+// lastRequest returns the most recent request received by the app in any
+// of its routers. The returned bool is false when none of the routers is
+// able to report the traffic of the app.
+func (app *App) lastRequest() (time.Time, bool, error) {
+	var last time.Time
+	var supported bool
+	for _, appRouter := range app.GetRouters() {
+		r, err := router.Get(appRouter.Name)
+		if err != nil {
+			return last, false, err
+		}
+		trafficRouter, ok := r.(router.TrafficRouter)
+		if !ok {
+			continue
+		}
+		routerLast, err := trafficRouter.LastRequest(app.Name)
+		if err != nil {
+			return last, false, err
+		}
+		supported = true
+		if routerLast.After(last) {
+			last = routerLast
+		}
+	}
+	return last, supported, nil
+}
+
+// lastActivity returns when the app was last created, deployed or started,
+// used as the start of the idle period of apps that never received requests.
+func (app *App) lastActivity() (time.Time, error) {
+	evts, err := event.List(&event.Filter{
+		Target: event.Target{Type: event.TargetTypeApp, Value: app.Name},
+		KindNames: []string{
+			permission.PermAppCreate.FullName(),
+			permission.PermAppDeploy.FullName(),
+			permission.PermAppUpdateStart.FullName(),
+		},
+		KindType: event.KindTypePermission,
+		Limit:    1,
+	})
+	if err != nil || len(evts) == 0 {
+		return time.Time{}, err
+	}
+	return evts[0].StartTime, nil
+}
+
+// idleSince returns the last request received by the app when it has been
+// idle for more than the given number of days. Apps that never received
+// requests are idle since they were last created, deployed or started. A zero
+// time is returned for apps that received requests recently, that have no
+// running units or whose traffic cannot be tracked.
+func (app *App) idleSince(now time.Time, idleDays int) (time.Time, error) {
+	last, supported, err := app.lastRequest()
+	if err != nil || !supported {
+		return time.Time{}, err
+	}
+	if last.IsZero() {
+		last, err = app.lastActivity()
+		if err != nil || last.IsZero() {
+			return time.Time{}, err
+		}
+	}
+	if now.Sub(last) < time.Duration(idleDays)*24*time.Hour {
+		return time.Time{}, nil
+	}
+	units, err := app.Units()
+	if err != nil {
+		return time.Time{}, err
+	}
+	for _, u := range units {
+		if u.Available() {
+			return last, nil
+		}
+	}
+	return time.Time{}, nil
+}
+
+func (s *appScheduler) runIdleCheck(now time.Time) error {
+	proxyURL, policies, err := autoSleepConfig()
+	if err != nil || proxyURL == nil || len(policies) == 0 {
+		return err
+	}
+	now = now.UTC().Truncate(time.Hour)
+	claimed, err := claimIdleCheck(now)
+	if err != nil || !claimed {
+		return err
+	}
+	conn, err := db.Conn()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	var apps []App
+	err = conn.Apps().Find(nil).All(&apps)
+	if err != nil {
+		return err
+	}
+	for i := range apps {
+		a := &apps[i]
+		for _, policy := range policies {
+			if !policy.match(a) {
+				continue
+			}
+			last, err := a.idleSince(now, policy.IdleDays)
+			if err != nil {
+				log.Errorf("[auto sleep] unable to check if app %q is idle: %v", a.Name, err)
+			}
+			if !last.IsZero() {
+				s.running.Add(1)
+				go func(policy sleepPolicy) {
+					defer s.running.Done()
+					if err := runAutoSleep(a, policy, last, proxyURL); err != nil {
+						log.Errorf("[auto sleep] error putting app %q to sleep: %v", a.Name, err)
+					}
+				}(policy)
+			}
+			break
+		}
+	}
+	return nil
+}
+
+// claimIdleCheck marks the idle check in the given hour as taken, ensuring
+// only one tsurud instance runs it.
+func claimIdleCheck(hour time.Time) (bool, error) {
+	conn, err := db.Conn()
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+	_, err = conn.Collection("auto_sleep").Upsert(
+		bson.M{"_id": "idle-check", "last": bson.M{"$lt": hour}},
+		bson.M{"$set": bson.M{"last": hour}},
+	)
+	if mgo.IsDup(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func runAutoSleep(a *App, policy sleepPolicy, lastRequest time.Time, proxyURL *url.URL) (err error) {
+	evt, err := event.NewInternal(&event.Opts{
+		Target:       event.Target{Type: event.TargetTypeApp, Value: a.Name},
+		InternalKind: autoSleepKind,
+		CustomData: map[string]interface{}{
+			"idleDays":    policy.IdleDays,
+			"lastRequest": lastRequest,
+		},
+		Allowed: event.Allowed(permission.PermAppReadEvents, append(permission.Contexts(permission.CtxTeam, a.Teams),
+			permission.Context(permission.CtxApp, a.Name),
+			permission.Context(permission.CtxPool, a.Pool),
+		)...),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(err) }()
+	w := a.withLogWriter(evt)
+	fmt.Fprintf(w, "---- No requests received since %s, more than %d days ago. Run tsuru app-start to wake the app up ----\n",
+		lastRequest.Format(time.RFC3339), policy.IdleDays)
+	flushLog(w)
+	return a.Sleep(evt, "", proxyURL)
+}
@@ -0,0 +1,117 @@
+// Copyright 2018 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package app
+
+import (
+	"time"
+
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/event/eventtest"
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/router/routertest"
+	appTypes "github.com/tsuru/tsuru/types/app"
+	"gopkg.in/check.v1"
+)
+
+func (s *S) TestAutoSleepConfig(c *check.C) {
+	proxyURL, policies, err := autoSleepConfig()
+	c.Assert(err, check.IsNil)
+	c.Assert(proxyURL, check.IsNil)
+	c.Assert(policies, check.IsNil)
+	config.Set("auto-sleep:proxy-url", "http://sleep.tsuru.io")
+	defer config.Unset("auto-sleep")
+	config.Set("auto-sleep:policies", []interface{}{
+		map[interface{}]interface{}{"pool": "dev", "idle-days": 7},
+		map[interface{}]interface{}{"team": "qa", "idle-days": 2},
+	})
+	proxyURL, policies, err = autoSleepConfig()
+	c.Assert(err, check.IsNil)
+	c.Assert(proxyURL.String(), check.Equals, "http://sleep.tsuru.io")
+	c.Assert(policies, check.DeepEquals, []sleepPolicy{
+		{Pool: "dev", IdleDays: 7},
+		{Team: "qa", IdleDays: 2},
+	})
+	config.Set("auto-sleep:policies", []interface{}{
+		map[interface{}]interface{}{"pool": "dev"},
+	})
+	_, _, err = autoSleepConfig()
+	c.Assert(err, check.ErrorMatches, `invalid auto-sleep policy 0: idle-days must be greater than zero`)
+}
+
+func (s *S) TestRunIdleCheck(c *check.C) {
+	config.Set("routers:fake-traffic:type", "fake-traffic")
+	defer config.Unset("routers:fake-traffic")
+	config.Set("auto-sleep:proxy-url", "http://sleep.tsuru.io")
+	config.Set("auto-sleep:policies", []interface{}{
+		map[interface{}]interface{}{"team": s.team.Name, "idle-days": 3},
+	})
+	defer config.Unset("auto-sleep")
+	routertest.TrafficRouter.Reset()
+	idle := App{Name: "idle", TeamOwner: s.team.Name, Routers: []appTypes.AppRouter{{Name: "fake-traffic"}}}
+	err := CreateApp(&idle, s.user)
+	c.Assert(err, check.IsNil)
+	busy := App{Name: "busy", TeamOwner: s.team.Name, Routers: []appTypes.AppRouter{{Name: "fake-traffic"}}}
+	err = CreateApp(&busy, s.user)
+	c.Assert(err, check.IsNil)
+	untracked := App{Name: "untracked", TeamOwner: s.team.Name}
+	err = CreateApp(&untracked, s.user)
+	c.Assert(err, check.IsNil)
+	for _, a := range []*App{&idle, &busy, &untracked} {
+		err = a.AddUnits(1, "web", nil)
+		c.Assert(err, check.IsNil)
+	}
+	now := time.Date(2018, 5, 10, 12, 0, 0, 0, time.UTC)
+	routertest.TrafficRouter.LastRequests["idle"] = now.Add(-4 * 24 * time.Hour)
+	routertest.TrafficRouter.LastRequests["busy"] = now.Add(-time.Hour)
+	scheduler := &appScheduler{}
+	err = scheduler.runIdleCheck(now)
+	c.Assert(err, check.IsNil)
+	scheduler.running.Wait()
+	c.Assert(s.provisioner.Sleeps(&idle, ""), check.Equals, 1)
+	c.Assert(s.provisioner.Sleeps(&busy, ""), check.Equals, 0)
+	c.Assert(s.provisioner.Sleeps(&untracked, ""), check.Equals, 0)
+	routes, err := routertest.TrafficRouter.Routes("idle")
+	c.Assert(err, check.IsNil)
+	c.Assert(routes, check.HasLen, 1)
+	c.Assert(routes[0].String(), check.Equals, "http://sleep.tsuru.io")
+	c.Assert(eventtest.EventDesc{
+		Target: event.Target{Type: event.TargetTypeApp, Value: idle.Name},
+		Kind:   "auto-sleep",
+	}, eventtest.HasEvent)
+	err = scheduler.runIdleCheck(now.Add(10 * time.Minute))
+	c.Assert(err, check.IsNil)
+	scheduler.running.Wait()
+	c.Assert(s.provisioner.Sleeps(&idle, ""), check.Equals, 1)
+}
+
+func (s *S) TestIdleSinceNeverRequested(c *check.C) {
+	config.Set("routers:fake-traffic:type", "fake-traffic")
+	defer config.Unset("routers:fake-traffic")
+	routertest.TrafficRouter.Reset()
+	a := App{Name: "fresh", TeamOwner: s.team.Name, Routers: []appTypes.AppRouter{{Name: "fake-traffic"}}}
+	err := CreateApp(&a, s.user)
+	c.Assert(err, check.IsNil)
+	err = a.AddUnits(1, "web", nil)
+	c.Assert(err, check.IsNil)
+	last, err := a.idleSince(time.Now().Add(4*24*time.Hour), 3)
+	c.Assert(err, check.IsNil)
+	c.Assert(last.IsZero(), check.Equals, true)
+	evt, err := event.New(&event.Opts{
+		Target:   event.Target{Type: event.TargetTypeApp, Value: a.Name},
+		Kind:     permission.PermAppDeploy,
+		RawOwner: event.Owner{Type: event.OwnerTypeUser, Name: s.user.Email},
+		Allowed:  event.Allowed(permission.PermAppReadEvents),
+	})
+	c.Assert(err, check.IsNil)
+	err = evt.Done(nil)
+	c.Assert(err, check.IsNil)
+	last, err = a.idleSince(time.Now().Add(24*time.Hour), 3)
+	c.Assert(err, check.IsNil)
+	c.Assert(last.IsZero(), check.Equals, true)
+	last, err = a.idleSince(time.Now().Add(4*24*time.Hour), 3)
+	c.Assert(err, check.IsNil)
+	c.Assert(last.Unix(), check.Equals, evt.StartTime.Unix())
+}
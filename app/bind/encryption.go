@@ -0,0 +1,190 @@
+// Copyright 2018 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bind
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"io"
+	"strings"
+
+	"github.com/globalsign/mgo/bson"
+	"github.com/pkg/errors"
+	"github.com/tsuru/config"
+)
+
+// encryptedPrefix marks values encrypted before being stored. It's followed
+// by the id of the key used in the encryption and the encrypted value.
+const encryptedPrefix = "tsuru-encrypted:v1:"
+
+var ErrNoEncryptionKey = errors.New("unable to decrypt environment variable: no matching key in env-encryption config")
+
+type encryptionKey struct {
+	id  string
+	key []byte
+}
+
+func newEncryptionKey(secret string) encryptionKey {
+	key := sha256.Sum256([]byte(secret))
+	id := sha256.Sum256(key[:])
+	return encryptionKey{id: hex.EncodeToString(id[:4]), key: key[:]}
+}
+
+// encryptionKeys returns the key used to encrypt new values, which may be
+// nil if encryption is disabled, and every key accepted when decrypting
+// values, including the ones in env-encryption:old-keys.
+func encryptionKeys() (*encryptionKey, []encryptionKey) {
+	var keys []encryptionKey
+	var current *encryptionKey
+	secret, _ := config.GetString("env-encryption:key")
+	if secret != "" {
+		key := newEncryptionKey(secret)
+		current = &key
+		keys = append(keys, key)
+	}
+	oldSecrets, _ := config.GetList("env-encryption:old-keys")
+	for _, s := range oldSecrets {
+		keys = append(keys, newEncryptionKey(s))
+	}
+	return current, keys
+}
+
+func encryptValue(value string) (string, error) {
+	key, _ := encryptionKeys()
+	if key == nil {
+		return value, nil
+	}
+	gcm, err := newGCM(key.key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", errors.WithStack(err)
+	}
+	sealed := gcm.Seal(nonce, nonce, []byte(value), nil)
+	return encryptedPrefix + key.id + ":" + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func decryptValue(value string) (string, error) {
+	if !strings.HasPrefix(value, encryptedPrefix) {
+		return value, nil
+	}
+	parts := strings.SplitN(strings.TrimPrefix(value, encryptedPrefix), ":", 2)
+	if len(parts) != 2 {
+		return "", errors.New("invalid encrypted environment variable")
+	}
+	_, keys := encryptionKeys()
+	for _, key := range keys {
+		if key.id != parts[0] {
+			continue
+		}
+		sealed, err := base64.StdEncoding.DecodeString(parts[1])
+		if err != nil {
+			return "", errors.WithStack(err)
+		}
+		gcm, err := newGCM(key.key)
+		if err != nil {
+			return "", err
+		}
+		if len(sealed) < gcm.NonceSize() {
+			return "", errors.New("invalid encrypted environment variable")
+		}
+		plain, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], nil)
+		if err != nil {
+			return "", errors.Wrap(err, "unable to decrypt environment variable")
+		}
+		return string(plain), nil
+	}
+	return "", ErrNoEncryptionKey
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return gcm, nil
+}
+
+type rawEnvVar struct {
+	Name   string
+	Value  string
+	Public bool
+}
+
+// GetBSON encrypts the value of the variable before storing it, when a key
+// is set in env-encryption:key.
+func (e EnvVar) GetBSON() (interface{}, error) {
+	value, err := encryptValue(e.Value)
+	if err != nil {
+		return nil, err
+	}
+	return rawEnvVar{Name: e.Name, Value: value, Public: e.Public}, nil
+}
+
+// SetBSON decrypts the value of stored variables, accepting plain text values
+// stored before encryption was enabled.
+func (e *EnvVar) SetBSON(raw bson.Raw) error {
+	var env rawEnvVar
+	err := raw.Unmarshal(&env)
+	if err != nil {
+		return err
+	}
+	value, err := decryptValue(env.Value)
+	if err != nil {
+		return errors.Wrapf(err, "env %q", env.Name)
+	}
+	*e = EnvVar{Name: env.Name, Value: value, Public: env.Public}
+	return nil
+}
+
+type rawServiceEnvVar struct {
+	Name         string
+	Value        string
+	Public       bool
+	ServiceName  string
+	InstanceName string
+}
+
+func (e ServiceEnvVar) GetBSON() (interface{}, error) {
+	value, err := encryptValue(e.Value)
+	if err != nil {
+		return nil, err
+	}
+	return rawServiceEnvVar{
+		Name:         e.Name,
+		Value:        value,
+		Public:       e.Public,
+		ServiceName:  e.ServiceName,
+		InstanceName: e.InstanceName,
+	}, nil
+}
+
+func (e *ServiceEnvVar) SetBSON(raw bson.Raw) error {
+	var env rawServiceEnvVar
+	err := raw.Unmarshal(&env)
+	if err != nil {
+		return err
+	}
+	value, err := decryptValue(env.Value)
+	if err != nil {
+		return errors.Wrapf(err, "env %q", env.Name)
+	}
+	*e = ServiceEnvVar{
+		EnvVar:       EnvVar{Name: env.Name, Value: value, Public: env.Public},
+		ServiceName:  env.ServiceName,
+		InstanceName: env.InstanceName,
+	}
+	return nil
+}
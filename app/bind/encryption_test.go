@@ -0,0 +1,109 @@
+// Copyright 2018 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bind
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/globalsign/mgo/bson"
+	"github.com/tsuru/config"
+	"gopkg.in/check.v1"
+)
+
+type S struct{}
+
+var _ = check.Suite(&S{})
+
+func Test(t *testing.T) { check.TestingT(t) }
+
+func (s *S) TearDownTest(c *check.C) {
+	config.Unset("env-encryption")
+}
+
+type envsDoc struct {
+	Env         map[string]EnvVar
+	ServiceEnvs []ServiceEnvVar `bson:",omitempty"`
+}
+
+func (s *S) roundTrip(c *check.C, doc envsDoc) (bson.M, envsDoc) {
+	data, err := bson.Marshal(doc)
+	c.Assert(err, check.IsNil)
+	var stored bson.M
+	err = bson.Unmarshal(data, &stored)
+	c.Assert(err, check.IsNil)
+	var result envsDoc
+	err = bson.Unmarshal(data, &result)
+	c.Assert(err, check.IsNil)
+	return stored, result
+}
+
+func (s *S) TestEnvVarBSONWithoutKey(c *check.C) {
+	doc := envsDoc{
+		Env:         map[string]EnvVar{"A": {Name: "A", Value: "secret", Public: true}},
+		ServiceEnvs: []ServiceEnvVar{{EnvVar: EnvVar{Name: "B", Value: "pass"}, ServiceName: "mysql", InstanceName: "db"}},
+	}
+	stored, result := s.roundTrip(c, doc)
+	c.Assert(stored["env"], check.DeepEquals, bson.M{"A": bson.M{"name": "A", "value": "secret", "public": true}})
+	c.Assert(stored["serviceenvs"], check.DeepEquals, []interface{}{
+		bson.M{"name": "B", "value": "pass", "public": false, "servicename": "mysql", "instancename": "db"},
+	})
+	c.Assert(result, check.DeepEquals, doc)
+}
+
+func (s *S) TestEnvVarBSONEncrypted(c *check.C) {
+	config.Set("env-encryption:key", "my-key")
+	doc := envsDoc{
+		Env:         map[string]EnvVar{"A": {Name: "A", Value: "secret", Public: true}},
+		ServiceEnvs: []ServiceEnvVar{{EnvVar: EnvVar{Name: "B", Value: "pass"}, ServiceName: "mysql", InstanceName: "db"}},
+	}
+	stored, result := s.roundTrip(c, doc)
+	storedEnv := stored["env"].(bson.M)["A"].(bson.M)
+	c.Assert(storedEnv["name"], check.Equals, "A")
+	c.Assert(storedEnv["public"], check.Equals, true)
+	c.Assert(strings.HasPrefix(storedEnv["value"].(string), encryptedPrefix), check.Equals, true)
+	c.Assert(strings.Contains(storedEnv["value"].(string), "secret"), check.Equals, false)
+	storedServiceEnv := stored["serviceenvs"].([]interface{})[0].(bson.M)
+	c.Assert(storedServiceEnv["servicename"], check.Equals, "mysql")
+	c.Assert(storedServiceEnv["instancename"], check.Equals, "db")
+	c.Assert(strings.HasPrefix(storedServiceEnv["value"].(string), encryptedPrefix), check.Equals, true)
+	c.Assert(result, check.DeepEquals, doc)
+}
+
+func (s *S) TestEnvVarBSONReadsPlainValuesWithKey(c *check.C) {
+	doc := envsDoc{Env: map[string]EnvVar{"A": {Name: "A", Value: "secret"}}}
+	data, err := bson.Marshal(doc)
+	c.Assert(err, check.IsNil)
+	config.Set("env-encryption:key", "my-key")
+	var result envsDoc
+	err = bson.Unmarshal(data, &result)
+	c.Assert(err, check.IsNil)
+	c.Assert(result, check.DeepEquals, doc)
+}
+
+func (s *S) TestEnvVarBSONKeyRotation(c *check.C) {
+	config.Set("env-encryption:key", "old-key")
+	doc := envsDoc{Env: map[string]EnvVar{"A": {Name: "A", Value: "secret"}}}
+	data, err := bson.Marshal(doc)
+	c.Assert(err, check.IsNil)
+	config.Set("env-encryption:key", "new-key")
+	var result envsDoc
+	err = bson.Unmarshal(data, &result)
+	c.Assert(err, check.ErrorMatches, `env "A": unable to decrypt environment variable: no matching key in env-encryption config`)
+	config.Set("env-encryption:old-keys", []interface{}{"old-key"})
+	result = envsDoc{}
+	err = bson.Unmarshal(data, &result)
+	c.Assert(err, check.IsNil)
+	c.Assert(result, check.DeepEquals, doc)
+}
+
+func (s *S) TestEnvVarBSONWrongKey(c *check.C) {
+	config.Set("env-encryption:key", "my-key")
+	value, err := encryptValue("secret")
+	c.Assert(err, check.IsNil)
+	tampered := value[:len(value)-4] + "AAA="
+	_, err = decryptValue(tampered)
+	c.Assert(err, check.ErrorMatches, "unable to decrypt environment variable: .*")
+}
@@ -0,0 +1,79 @@
+// Copyright 2018 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package app
+
+import (
+	"fmt"
+
+	"github.com/globalsign/mgo/bson"
+	"github.com/tsuru/tsuru/app/bind"
+	"github.com/tsuru/tsuru/db"
+	"github.com/tsuru/tsuru/provision"
+)
+
+// BuildEnvs returns the environment variables available only while building
+// the images of the app. They're never set in the units running the app.
+func (app *App) BuildEnvs() map[string]bind.EnvVar {
+	envs := make(map[string]bind.EnvVar, len(app.BuildEnv))
+	for name, env := range app.BuildEnv {
+		envs[name] = env
+	}
+	return envs
+}
+
+// SetBuildEnvs saves a list of build environment variables in the app. As
+// they're only used in the next builds, the units are never restarted.
+func (app *App) SetBuildEnvs(setEnvs bind.SetEnvArgs) error {
+	if len(setEnvs.Envs) == 0 {
+		return nil
+	}
+	if setEnvs.Writer != nil {
+		fmt.Fprintf(setEnvs.Writer, "---- Setting %d new build environment variables ----\n", len(setEnvs.Envs))
+	}
+	if app.BuildEnv == nil {
+		app.BuildEnv = make(map[string]bind.EnvVar)
+	}
+	for _, env := range setEnvs.Envs {
+		app.BuildEnv[env.Name] = env
+	}
+	return app.saveBuildEnvs()
+}
+
+// UnsetBuildEnvs removes build environment variables from an app.
+func (app *App) UnsetBuildEnvs(unsetEnvs bind.UnsetEnvArgs) error {
+	if len(unsetEnvs.VariableNames) == 0 {
+		return nil
+	}
+	if unsetEnvs.Writer != nil {
+		fmt.Fprintf(unsetEnvs.Writer, "---- Unsetting %d build environment variables ----\n", len(unsetEnvs.VariableNames))
+	}
+	for _, name := range unsetEnvs.VariableNames {
+		delete(app.BuildEnv, name)
+	}
+	return app.saveBuildEnvs()
+}
+
+func (app *App) saveBuildEnvs() error {
+	conn, err := db.Conn()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	return conn.Apps().Update(bson.M{"name": app.Name}, bson.M{"$set": bson.M{"buildenv": app.BuildEnv}})
+}
+
+// IsBuildUnit returns whether the given unit is building an image for the
+// app, in which case the build environment variables must be sent to it.
+func (app *App) IsBuildUnit(unitID string) (bool, error) {
+	prov, err := app.getProvisioner()
+	if err != nil {
+		return false, err
+	}
+	buildProv, ok := prov.(provision.BuildUnitProvisioner)
+	if !ok {
+		return false, nil
+	}
+	return buildProv.IsBuildUnit(app, unitID)
+}
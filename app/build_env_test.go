@@ -0,0 +1,81 @@
+// Copyright 2018 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package app
+
+import (
+	"bytes"
+
+	"github.com/tsuru/tsuru/app/bind"
+	"github.com/tsuru/tsuru/provision"
+	"gopkg.in/check.v1"
+)
+
+func (s *S) TestSetBuildEnvs(c *check.C) {
+	a := App{
+		Name: "myapp",
+		Env: map[string]bind.EnvVar{
+			"DATABASE_HOST": {Name: "DATABASE_HOST", Value: "localhost", Public: true},
+		},
+		TeamOwner: s.team.Name,
+	}
+	err := CreateApp(&a, s.user)
+	c.Assert(err, check.IsNil)
+	err = s.provisioner.AddUnits(&a, 1, "web", nil)
+	c.Assert(err, check.IsNil)
+	var buf bytes.Buffer
+	err = a.SetBuildEnvs(bind.SetEnvArgs{
+		Envs: []bind.EnvVar{
+			{Name: "NPM_TOKEN", Value: "secret", Public: false},
+		},
+		Writer: &buf,
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(buf.String(), check.Equals, "---- Setting 1 new build environment variables ----\n")
+	newApp, err := GetByName(a.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(newApp.BuildEnvs(), check.DeepEquals, map[string]bind.EnvVar{
+		"NPM_TOKEN": {Name: "NPM_TOKEN", Value: "secret", Public: false},
+	})
+	_, ok := newApp.Envs()["NPM_TOKEN"]
+	c.Assert(ok, check.Equals, false)
+	c.Assert(s.provisioner.Restarts(&a, ""), check.Equals, 0)
+}
+
+func (s *S) TestUnsetBuildEnvs(c *check.C) {
+	a := App{
+		Name: "myapp",
+		BuildEnv: map[string]bind.EnvVar{
+			"NPM_TOKEN":     {Name: "NPM_TOKEN", Value: "secret", Public: false},
+			"PIP_INDEX_URL": {Name: "PIP_INDEX_URL", Value: "https://pypi.example.com", Public: false},
+		},
+		TeamOwner: s.team.Name,
+	}
+	err := CreateApp(&a, s.user)
+	c.Assert(err, check.IsNil)
+	err = a.UnsetBuildEnvs(bind.UnsetEnvArgs{VariableNames: []string{"NPM_TOKEN"}})
+	c.Assert(err, check.IsNil)
+	newApp, err := GetByName(a.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(newApp.BuildEnvs(), check.DeepEquals, map[string]bind.EnvVar{
+		"PIP_INDEX_URL": {Name: "PIP_INDEX_URL", Value: "https://pypi.example.com", Public: false},
+	})
+}
+
+func (s *S) TestIsBuildUnit(c *check.C) {
+	a := App{Name: "myapp", TeamOwner: s.team.Name}
+	err := CreateApp(&a, s.user)
+	c.Assert(err, check.IsNil)
+	err = s.provisioner.AddUnits(&a, 1, "web", nil)
+	c.Assert(err, check.IsNil)
+	s.provisioner.AddUnit(&a, provision.Unit{ID: "building-unit", AppName: a.Name, Status: provision.StatusBuilding})
+	units, err := a.Units()
+	c.Assert(err, check.IsNil)
+	building, err := a.IsBuildUnit(units[0].ID)
+	c.Assert(err, check.IsNil)
+	c.Assert(building, check.Equals, false)
+	building, err = a.IsBuildUnit("building-unit")
+	c.Assert(err, check.IsNil)
+	c.Assert(building, check.Equals, true)
+}
@@ -0,0 +1,68 @@
+// Copyright 2018 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package app
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/tsuru/tsuru/app/image"
+	"github.com/tsuru/tsuru/service"
+)
+
+const defaultDependenciesTimeout = 120 * time.Second
+
+var dependenciesCheckInterval = 5 * time.Second
+
+// waitServiceDependencies blocks until every service instance bound to the
+// app is available, when the tsuru.yaml in the given image sets
+// dependencies:wait_services. Instances reporting themselves as down or
+// pending are checked again until dependencies:timeout_seconds is reached.
+func (app *App) waitServiceDependencies(imageID string, w io.Writer) error {
+	if imageID == "" {
+		return nil
+	}
+	yamlData, err := image.GetImageTsuruYamlData(imageID)
+	if err != nil {
+		return err
+	}
+	if !yamlData.Dependencies.WaitServices {
+		return nil
+	}
+	timeout := defaultDependenciesTimeout
+	if yamlData.Dependencies.TimeoutSeconds > 0 {
+		timeout = time.Duration(yamlData.Dependencies.TimeoutSeconds) * time.Second
+	}
+	instances, err := service.GetServiceInstancesBoundToApp(app.Name)
+	if err != nil {
+		return err
+	}
+	if len(instances) == 0 {
+		return nil
+	}
+	fmt.Fprintf(w, "---- Waiting for %d service instance(s) bound to the app ----\n", len(instances))
+	deadline := time.Now().Add(timeout)
+	for {
+		var pending []string
+		for i := range instances {
+			status, err := instances[i].Status("")
+			if err != nil || status == "down" || status == "pending" {
+				pending = append(pending, fmt.Sprintf("%s/%s", instances[i].ServiceName, instances[i].Name))
+			}
+		}
+		if len(pending) == 0 {
+			fmt.Fprintf(w, " ---> Service instances are available\n")
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return errors.Errorf("timeout after %v waiting for service instances: %s", timeout, strings.Join(pending, ", "))
+		}
+		fmt.Fprintf(w, " ---> Waiting for service instances: %s\n", strings.Join(pending, ", "))
+		time.Sleep(dependenciesCheckInterval)
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright 2018 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package app
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"time"
+
+	"github.com/tsuru/tsuru/app/image"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/service"
+	"gopkg.in/check.v1"
+)
+
+func (s *S) createAppWithDependency(c *check.C, endpoint string, customData map[string]interface{}) *App {
+	srvc := service.Service{Name: "mysql", Endpoint: map[string]string{"production": endpoint}, Password: "abcde", OwnerTeams: []string{s.team.Name}}
+	err := srvc.Create()
+	c.Assert(err, check.IsNil)
+	a := App{Name: "myapp", Platform: "python", TeamOwner: s.team.Name}
+	err = CreateApp(&a, s.user)
+	c.Assert(err, check.IsNil)
+	err = s.conn.ServiceInstances().Insert(service.ServiceInstance{
+		Name:        "my-db",
+		ServiceName: "mysql",
+		Teams:       []string{s.team.Name},
+		Apps:        []string{a.Name},
+	})
+	c.Assert(err, check.IsNil)
+	err = image.SaveImageCustomData("tsuru/app-myapp:v1", customData)
+	c.Assert(err, check.IsNil)
+	err = image.AppendAppImageName(a.Name, "tsuru/app-myapp:v1")
+	c.Assert(err, check.IsNil)
+	return &a
+}
+
+func (s *S) TestRestartWaitsServiceDependencies(c *check.C) {
+	old := dependenciesCheckInterval
+	dependenciesCheckInterval = time.Millisecond
+	defer func() { dependenciesCheckInterval = old }()
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+	a := s.createAppWithDependency(c, ts.URL, map[string]interface{}{
+		"dependencies": map[string]interface{}{"wait_services": true},
+	})
+	var buf bytes.Buffer
+	err := a.Restart("", &buf)
+	c.Assert(err, check.IsNil)
+	c.Assert(atomic.LoadInt32(&calls), check.Equals, int32(3))
+	c.Assert(buf.String(), check.Matches, `(?s).*Waiting for service instances: mysql/my-db.*Service instances are available.*`)
+	c.Assert(s.provisioner.Restarts(a, ""), check.Equals, 1)
+}
+
+func (s *S) TestRestartServiceDependenciesTimeout(c *check.C) {
+	old := dependenciesCheckInterval
+	dependenciesCheckInterval = 100 * time.Millisecond
+	defer func() { dependenciesCheckInterval = old }()
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer ts.Close()
+	a := s.createAppWithDependency(c, ts.URL, map[string]interface{}{
+		"dependencies": map[string]interface{}{"wait_services": true, "timeout_seconds": 1},
+	})
+	err := a.Restart("", nil)
+	c.Assert(err, check.ErrorMatches, `timeout after 1s waiting for service instances: mysql/my-db`)
+	c.Assert(s.provisioner.Restarts(a, ""), check.Equals, 0)
+}
+
+func (s *S) TestRollbackBySuffixWaitsServiceDependencies(c *check.C) {
+	old := dependenciesCheckInterval
+	dependenciesCheckInterval = time.Millisecond
+	defer func() { dependenciesCheckInterval = old }()
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 2 {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+	a := s.createAppWithDependency(c, ts.URL, map[string]interface{}{
+		"dependencies": map[string]interface{}{"wait_services": true},
+	})
+	evt, err := event.New(&event.Opts{
+		Target:   event.Target{Type: "app", Value: a.Name},
+		Kind:     permission.PermAppDeploy,
+		RawOwner: event.Owner{Type: event.OwnerTypeUser, Name: s.user.Email},
+		Allowed:  event.Allowed(permission.PermApp),
+	})
+	c.Assert(err, check.IsNil)
+	var buf bytes.Buffer
+	imgID, err := Deploy(DeployOptions{
+		App:          a,
+		OutputStream: &buf,
+		Image:        "v1",
+		Rollback:     true,
+		Event:        evt,
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(imgID, check.Equals, "tsuru/app-myapp:v1")
+	c.Assert(atomic.LoadInt32(&calls), check.Equals, int32(2))
+	c.Assert(buf.String(), check.Matches, `(?s).*Waiting for service instances: mysql/my-db.*Service instances are available.*Rollback deploy called`)
+}
+
+func (s *S) TestRestartServiceDependenciesNotDeclared(c *check.C) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer ts.Close()
+	a := s.createAppWithDependency(c, ts.URL, map[string]interface{}{})
+	err := a.Restart("", nil)
+	c.Assert(err, check.IsNil)
+	c.Assert(atomic.LoadInt32(&calls), check.Equals, int32(0))
+	c.Assert(s.provisioner.Restarts(a, ""), check.Equals, 1)
+}
@@ -208,6 +208,8 @@ func Build(opts DeployOptions) (string, error) {
 	logWriter.Async()
 	defer logWriter.Close()
 	opts.Event.SetLogWriter(io.MultiWriter(&tsuruIo.NoErrorWriter{Writer: opts.OutputStream}, &logWriter))
+	opts.Event.SetLogRedactor(opts.App.redactPrivateEnvs)
+	defer opts.Event.FlushLog()
 	prov, err := opts.App.getProvisioner()
 	if err != nil {
 		return "", err
@@ -233,17 +235,12 @@ func Deploy(opts DeployOptions) (string, error) {
 	if opts.Event == nil {
 		return "", errors.Errorf("missing event in deploy opts")
 	}
-	if opts.Rollback && !regexp.MustCompile(":v[0-9]+$").MatchString(opts.Image) {
-		imageName, err := image.GetAppImageBySuffix(opts.App.Name, opts.Image)
-		if err != nil {
-			return "", err
-		}
-		opts.Image = imageName
-	}
 	logWriter := LogWriter{App: opts.App}
 	logWriter.Async()
 	defer logWriter.Close()
 	opts.Event.SetLogWriter(io.MultiWriter(&tsuruIo.NoErrorWriter{Writer: opts.OutputStream}, &logWriter))
+	opts.Event.SetLogRedactor(opts.App.redactPrivateEnvs)
+	defer opts.Event.FlushLog()
 	imageID, err := deployToProvisioner(&opts, opts.Event)
 	rebuild.RoutesRebuildOrEnqueue(opts.App.Name)
 	if err != nil {
@@ -289,10 +286,24 @@ func deployToProvisioner(opts *DeployOptions, evt *event.Event) (string, error)
 			if err != nil {
 				return "", err
 			}
+			err = opts.App.waitServiceDependencies(imageID, evt)
+			if err != nil {
+				return "", err
+			}
 			return deployer.Deploy(opts.App, imageID, evt)
 		}
 	} else {
 		if deployer, ok := prov.(provision.RollbackableDeployer); ok {
+			// The image may be only a suffix, like "v3", it must be resolved
+			// before reading the tsuru.yaml data stored for it.
+			opts.Image, err = image.GetAppImageBySuffix(opts.App.Name, opts.Image)
+			if err != nil {
+				return "", err
+			}
+			err = opts.App.waitServiceDependencies(opts.Image, evt)
+			if err != nil {
+				return "", err
+			}
 			return deployer.Rollback(opts.App, opts.Image, evt)
 		}
 	}
@@ -0,0 +1,165 @@
+// Copyright 2018 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package app
+
+import (
+	"regexp"
+	"time"
+
+	tsuruErrors "github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/permission"
+)
+
+var reEnvFormName = regexp.MustCompile(`^Envs\.\d+\.Name$`)
+
+// DeployChanges describes what changed in an app between two of its deploys.
+type DeployChanges struct {
+	From *DeployData
+	To   *DeployData
+	// Deploys lists the deploys made after From, up to and including To,
+	// along with the code diff sent by the client in each of them.
+	Deploys    []DeployData
+	EnvChanges []EnvChange
+}
+
+// EnvChange is a change in the environment variables of the app, only the
+// names of the variables are recorded.
+type EnvChange struct {
+	Timestamp time.Time
+	User      string
+	Action    string
+	Names     []string
+}
+
+// CompareDeploys returns the changes between the deploys identified by fromID
+// and toID. When fromID is empty, the deploy before toID is used.
+func CompareDeploys(fromID, toID string) (*DeployChanges, error) {
+	to, err := GetDeploy(toID)
+	if err != nil {
+		return nil, err
+	}
+	to.Log = ""
+	var from *DeployData
+	if fromID == "" {
+		from, err = previousDeploy(to)
+	} else {
+		from, err = GetDeploy(fromID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if from == nil {
+		return &DeployChanges{To: to, Deploys: []DeployData{*to}}, nil
+	}
+	from.Log = ""
+	if from.App != to.App {
+		return nil, &tsuruErrors.ValidationError{Message: "deploys must belong to the same app"}
+	}
+	if !from.Timestamp.Before(to.Timestamp) {
+		return nil, &tsuruErrors.ValidationError{Message: "the first deploy must be older than the second one"}
+	}
+	changes := DeployChanges{From: from, To: to}
+	evts, err := event.List(&event.Filter{
+		Target:    event.Target{Type: event.TargetTypeApp, Value: to.App},
+		KindNames: []string{permission.PermAppDeploy.FullName()},
+		KindType:  event.KindTypePermission,
+		Since:     from.Timestamp,
+		Until:     to.Timestamp,
+		Sort:      "starttime",
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i := range evts {
+		if evts[i].UniqueID == from.ID {
+			continue
+		}
+		data := eventToDeployData(&evts[i], nil, true)
+		data.Log = ""
+		changes.Deploys = append(changes.Deploys, *data)
+	}
+	changes.EnvChanges, err = envChangesBetween(to.App, from.Timestamp, to.Timestamp)
+	if err != nil {
+		return nil, err
+	}
+	return &changes, nil
+}
+
+func previousDeploy(deploy *DeployData) (*DeployData, error) {
+	evts, err := event.List(&event.Filter{
+		Target:    event.Target{Type: event.TargetTypeApp, Value: deploy.App},
+		KindNames: []string{permission.PermAppDeploy.FullName()},
+		KindType:  event.KindTypePermission,
+		Until:     deploy.Timestamp,
+		Limit:     2,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i := range evts {
+		if evts[i].UniqueID != deploy.ID {
+			return eventToDeployData(&evts[i], nil, false), nil
+		}
+	}
+	return nil, nil
+}
+
+func envChangesBetween(appName string, since, until time.Time) ([]EnvChange, error) {
+	setKind := permission.PermAppUpdateEnvSet.FullName()
+	evts, err := event.List(&event.Filter{
+		Target:    event.Target{Type: event.TargetTypeApp, Value: appName},
+		KindNames: []string{setKind, permission.PermAppUpdateEnvUnset.FullName()},
+		KindType:  event.KindTypePermission,
+		Since:     since,
+		Until:     until,
+		Sort:      "starttime",
+	})
+	if err != nil {
+		return nil, err
+	}
+	var changes []EnvChange
+	for _, evt := range evts {
+		if evt.Error != "" {
+			continue
+		}
+		var form []map[string]interface{}
+		if err = evt.StartData(&form); err != nil {
+			continue
+		}
+		change := EnvChange{
+			Timestamp: evt.StartTime,
+			User:      evt.Owner.Name,
+			Action:    "unset",
+		}
+		if evt.Kind.Name == setKind {
+			change.Action = "set"
+		}
+		for _, field := range form {
+			name, _ := field["name"].(string)
+			if (change.Action == "set" && reEnvFormName.MatchString(name)) || (change.Action == "unset" && name == "env") {
+				change.Names = append(change.Names, formValues(field["value"])...)
+			}
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+func formValues(value interface{}) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+// Copyright 2018 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package app
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/permission"
+	"gopkg.in/check.v1"
+)
+
+func insertEnvEvent(appName string, kind *permission.PermissionScheme, form url.Values, timestamp time.Time, c *check.C) {
+	evt, err := event.New(&event.Opts{
+		Target:     event.Target{Type: event.TargetTypeApp, Value: appName},
+		Kind:       kind,
+		RawOwner:   event.Owner{Type: event.OwnerTypeUser, Name: "me@tsuru.io"},
+		Allowed:    event.Allowed(permission.PermApp),
+		CustomData: event.FormToCustomData(form),
+	})
+	c.Assert(err, check.IsNil)
+	evt.StartTime = timestamp
+	err = evt.Done(nil)
+	c.Assert(err, check.IsNil)
+}
+
+func (s *S) TestCompareDeploys(c *check.C) {
+	a := App{Name: "g1", TeamOwner: s.team.Name}
+	err := CreateApp(&a, s.user)
+	c.Assert(err, check.IsNil)
+	now := time.Now()
+	evts := insertDeploysAsEvents([]DeployData{
+		{App: "g1", Timestamp: now.Add(-3 * time.Hour), Commit: "c1", Image: "tsuru/app-g1:v1", Diff: "diff1", Log: "log1"},
+		{App: "g1", Timestamp: now.Add(-2 * time.Hour), Commit: "c2", Image: "tsuru/app-g1:v2", Diff: "diff2", Log: "log2"},
+		{App: "g1", Timestamp: now.Add(-1 * time.Hour), Commit: "c3", Image: "tsuru/app-g1:v3", Diff: "diff3", Log: "log3"},
+	}, c)
+	insertEnvEvent("g1", permission.PermAppUpdateEnvSet, url.Values{
+		"Envs.0.Name":  {"FOO"},
+		"Envs.0.Value": {"*****"},
+		"Envs.1.Name":  {"BAR"},
+		"Envs.1.Value": {"*****"},
+	}, now.Add(-150*time.Minute), c)
+	insertEnvEvent("g1", permission.PermAppUpdateEnvUnset, url.Values{
+		"env": {"OLD"},
+	}, now.Add(-90*time.Minute), c)
+	insertEnvEvent("g1", permission.PermAppUpdateEnvUnset, url.Values{
+		"env": {"LATER"},
+	}, now.Add(-30*time.Minute), c)
+	changes, err := CompareDeploys(evts[0].UniqueID.Hex(), evts[2].UniqueID.Hex())
+	c.Assert(err, check.IsNil)
+	c.Assert(changes.From.ID, check.Equals, evts[0].UniqueID)
+	c.Assert(changes.From.Commit, check.Equals, "c1")
+	c.Assert(changes.To.ID, check.Equals, evts[2].UniqueID)
+	c.Assert(changes.To.Image, check.Equals, "tsuru/app-g1:v3")
+	c.Assert(changes.To.Log, check.Equals, "")
+	c.Assert(changes.Deploys, check.HasLen, 2)
+	c.Assert(changes.Deploys[0].Commit, check.Equals, "c2")
+	c.Assert(changes.Deploys[0].Diff, check.Equals, "diff2")
+	c.Assert(changes.Deploys[0].Log, check.Equals, "")
+	c.Assert(changes.Deploys[1].Commit, check.Equals, "c3")
+	c.Assert(changes.Deploys[1].Diff, check.Equals, "diff3")
+	c.Assert(changes.EnvChanges, check.HasLen, 2)
+	c.Assert(changes.EnvChanges[0].Action, check.Equals, "set")
+	c.Assert(changes.EnvChanges[0].User, check.Equals, "me@tsuru.io")
+	c.Assert(changes.EnvChanges[0].Names, check.HasLen, 2)
+	c.Assert(changes.EnvChanges[1].Action, check.Equals, "unset")
+	c.Assert(changes.EnvChanges[1].Names, check.DeepEquals, []string{"OLD"})
+}
+
+func (s *S) TestCompareDeploysWithPrevious(c *check.C) {
+	a := App{Name: "g1", TeamOwner: s.team.Name}
+	err := CreateApp(&a, s.user)
+	c.Assert(err, check.IsNil)
+	now := time.Now()
+	evts := insertDeploysAsEvents([]DeployData{
+		{App: "g1", Timestamp: now.Add(-3 * time.Hour), Commit: "c1"},
+		{App: "g1", Timestamp: now.Add(-2 * time.Hour), Commit: "c2"},
+		{App: "g1", Timestamp: now.Add(-1 * time.Hour), Commit: "c3"},
+	}, c)
+	changes, err := CompareDeploys("", evts[2].UniqueID.Hex())
+	c.Assert(err, check.IsNil)
+	c.Assert(changes.From.ID, check.Equals, evts[1].UniqueID)
+	c.Assert(changes.Deploys, check.HasLen, 1)
+	c.Assert(changes.Deploys[0].Commit, check.Equals, "c3")
+	changes, err = CompareDeploys("", evts[0].UniqueID.Hex())
+	c.Assert(err, check.IsNil)
+	c.Assert(changes.From, check.IsNil)
+	c.Assert(changes.Deploys, check.HasLen, 1)
+	c.Assert(changes.Deploys[0].Commit, check.Equals, "c1")
+}
+
+func (s *S) TestCompareDeploysInvalid(c *check.C) {
+	now := time.Now()
+	evts := insertDeploysAsEvents([]DeployData{
+		{App: "g1", Timestamp: now.Add(-2 * time.Hour)},
+		{App: "g1", Timestamp: now.Add(-1 * time.Hour)},
+		{App: "g2", Timestamp: now},
+	}, c)
+	_, err := CompareDeploys(evts[1].UniqueID.Hex(), evts[0].UniqueID.Hex())
+	c.Assert(err, check.FitsTypeOf, &errors.ValidationError{})
+	_, err = CompareDeploys(evts[0].UniqueID.Hex(), evts[2].UniqueID.Hex())
+	c.Assert(err, check.FitsTypeOf, &errors.ValidationError{})
+}
@@ -14,6 +14,7 @@ import (
 	"time"
 
 	"github.com/globalsign/mgo/bson"
+	"github.com/tsuru/tsuru/app/bind"
 	"github.com/tsuru/tsuru/app/image"
 	"github.com/tsuru/tsuru/auth"
 	"github.com/tsuru/tsuru/builder"
@@ -281,6 +282,53 @@ func (s *S) TestBuildApp(c *check.C) {
 	c.Assert(imgID, check.Equals, "registry.somewhere/"+a.TeamOwner+"/app-some-app:v1-builder")
 }
 
+func (s *S) TestDeployRedactsPrivateEnvsFromEventLog(c *check.C) {
+	s.builder.OnBuild = func(p provision.BuilderDeploy, app provision.App, evt *event.Event, opts *builder.BuildOpts) (string, error) {
+		evt.Write([]byte("password is s3cr"))
+		evt.Write([]byte("et-value\n"))
+		evt.Logf("connecting with s3cret-value")
+		return "registry.somewhere/" + s.team.Name + "/app-some-app:v1-builder", nil
+	}
+	a := App{
+		Name:      "some-app",
+		Platform:  "django",
+		Teams:     []string{s.team.Name},
+		TeamOwner: s.team.Name,
+		Router:    "fake",
+	}
+	err := CreateApp(&a, s.user)
+	c.Assert(err, check.IsNil)
+	a.Env = map[string]bind.EnvVar{
+		"PASSWORD": {Name: "PASSWORD", Value: "s3cret-value", Public: false},
+	}
+	evt, err := event.New(&event.Opts{
+		Target:   event.Target{Type: "app", Value: a.Name},
+		Kind:     permission.PermAppDeploy,
+		RawOwner: event.Owner{Type: event.OwnerTypeUser, Name: s.user.Email},
+		Allowed:  event.Allowed(permission.PermApp),
+	})
+	c.Assert(err, check.IsNil)
+	buf := strings.NewReader("my file")
+	writer := &bytes.Buffer{}
+	_, err = Deploy(DeployOptions{
+		App:          &a,
+		File:         ioutil.NopCloser(buf),
+		FileSize:     int64(buf.Len()),
+		OutputStream: writer,
+		Event:        evt,
+	})
+	c.Assert(err, check.IsNil)
+	evt.Write([]byte("done with s3cr"))
+	err = evt.Done(nil)
+	c.Assert(err, check.IsNil)
+	expected := "password is *****\nconnecting with *****\nBuilder deploy calleddone with s3cr"
+	c.Assert(evt.Log, check.Equals, expected)
+	c.Assert(writer.String(), check.Equals, "password is *****\nconnecting with *****\nBuilder deploy called")
+	dbEvt, err := event.GetByID(evt.UniqueID)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbEvt.Log, check.Equals, expected)
+}
+
 func (s *S) TestDeployAppUpload(c *check.C) {
 	a := App{
 		Name:      "some-app",
@@ -799,6 +847,8 @@ func (s *S) TestRollbackWithNameImage(c *check.C) {
 	}
 	err := CreateApp(&a, s.user)
 	c.Assert(err, check.IsNil)
+	err = image.AppendAppImageName("otherapp", "registry.somewhere/tsuru/app-example:v2")
+	c.Assert(err, check.IsNil)
 	writer := &bytes.Buffer{}
 	evt, err := event.New(&event.Opts{
 		Target:   event.Target{Type: "app", Value: a.Name},
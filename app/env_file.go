@@ -0,0 +1,186 @@
+// Copyright 2018 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package app
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/tsuru/tsuru/app/bind"
+	tsuruErrors "github.com/tsuru/tsuru/errors"
+)
+
+const (
+	EnvFormatJSON   = "json"
+	EnvFormatDotenv = "dotenv"
+
+	// dotenvPrivateComment marks private variables in the dotenv format.
+	dotenvPrivateComment = "private"
+)
+
+var (
+	reEnvName       = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+	reUnquotedValue = regexp.MustCompile(`^[a-zA-Z0-9_./:@%+,-]*$`)
+)
+
+// ParseEnvs parses a list of environment variables in the given format. The
+// json format is the same list returned by ExportEnvs, while dotenv expects
+// one NAME=value pair per line. Variables parsed from dotenv are public, unless
+// followed by a "# private" comment.
+func ParseEnvs(data []byte, format string) ([]bind.EnvVar, error) {
+	var envs []bind.EnvVar
+	switch format {
+	case EnvFormatJSON:
+		if err := json.Unmarshal(data, &envs); err != nil {
+			return nil, &tsuruErrors.ValidationError{Message: fmt.Sprintf("invalid json envs: %v", err)}
+		}
+	case EnvFormatDotenv:
+		var err error
+		envs, err = parseDotenv(data)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, &tsuruErrors.ValidationError{Message: fmt.Sprintf("invalid envs format %q", format)}
+	}
+	for _, env := range envs {
+		if !reEnvName.MatchString(env.Name) {
+			return nil, &tsuruErrors.ValidationError{Message: fmt.Sprintf("invalid environment variable name %q", env.Name)}
+		}
+		if internalEnvs.Includes(env.Name) {
+			return nil, &tsuruErrors.ValidationError{Message: fmt.Sprintf("cannot set internal environment variable %q", env.Name)}
+		}
+	}
+	return envs, nil
+}
+
+func parseDotenv(data []byte) ([]bind.EnvVar, error) {
+	var envs []bind.EnvVar
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) != 2 {
+			return nil, &tsuruErrors.ValidationError{Message: fmt.Sprintf("line %d: expected NAME=value", lineNumber)}
+		}
+		value, comment, err := parseDotenvValue(strings.TrimSpace(parts[1]))
+		if err != nil {
+			return nil, &tsuruErrors.ValidationError{Message: fmt.Sprintf("line %d: %v", lineNumber, err)}
+		}
+		envs = append(envs, bind.EnvVar{
+			Name:   strings.TrimSpace(parts[0]),
+			Value:  value,
+			Public: comment != dotenvPrivateComment,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return envs, nil
+}
+
+// parseDotenvValue returns the value and the comment following it.
+func parseDotenvValue(value string) (string, string, error) {
+	if value == "" {
+		return "", "", nil
+	}
+	switch value[0] {
+	case '\'':
+		end := strings.IndexByte(value[1:], '\'')
+		if end < 0 {
+			return "", "", fmt.Errorf("unterminated quoted value")
+		}
+		return value[1 : end+1], dotenvComment(value[end+2:]), nil
+	case '"':
+		var buf bytes.Buffer
+		for i := 1; i < len(value); i++ {
+			switch value[i] {
+			case '"':
+				return buf.String(), dotenvComment(value[i+1:]), nil
+			case '\\':
+				i++
+				if i == len(value) {
+					return "", "", fmt.Errorf("unterminated quoted value")
+				}
+				switch value[i] {
+				case 'n':
+					buf.WriteByte('\n')
+				case 'r':
+					buf.WriteByte('\r')
+				case 't':
+					buf.WriteByte('\t')
+				default:
+					buf.WriteByte(value[i])
+				}
+			default:
+				buf.WriteByte(value[i])
+			}
+		}
+		return "", "", fmt.Errorf("unterminated quoted value")
+	}
+	var comment string
+	if idx := strings.Index(value, " #"); idx >= 0 {
+		comment = dotenvComment(value[idx:])
+		value = value[:idx]
+	}
+	return strings.TrimSpace(value), comment, nil
+}
+
+func dotenvComment(rest string) string {
+	rest = strings.TrimSpace(rest)
+	if !strings.HasPrefix(rest, "#") {
+		return ""
+	}
+	return strings.TrimSpace(rest[1:])
+}
+
+// ExportEnvs returns the environment variables set by users in the app,
+// sorted by name, in the given format. Variables set by tsuru and by bound
+// service instances are not exported, so the result may be imported in
+// another app. Private variables are followed by a "# private" comment in the
+// dotenv format.
+func (app *App) ExportEnvs(format string) ([]byte, error) {
+	envs := make([]bind.EnvVar, 0, len(app.Env))
+	for _, env := range app.Env {
+		if !internalEnvs.Includes(env.Name) {
+			envs = append(envs, env)
+		}
+	}
+	sort.Slice(envs, func(i, j int) bool {
+		return envs[i].Name < envs[j].Name
+	})
+	switch format {
+	case EnvFormatJSON:
+		return json.Marshal(envs)
+	case EnvFormatDotenv:
+		var buf bytes.Buffer
+		for _, env := range envs {
+			fmt.Fprintf(&buf, "%s=%s", env.Name, formatDotenvValue(env.Value))
+			if !env.Public {
+				fmt.Fprintf(&buf, " # %s", dotenvPrivateComment)
+			}
+			buf.WriteByte('\n')
+		}
+		return buf.Bytes(), nil
+	}
+	return nil, &tsuruErrors.ValidationError{Message: fmt.Sprintf("invalid envs format %q", format)}
+}
+
+func formatDotenvValue(value string) string {
+	if value != "" && reUnquotedValue.MatchString(value) {
+		return value
+	}
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+	return `"` + replacer.Replace(value) + `"`
+}
@@ -0,0 +1,94 @@
+// Copyright 2018 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package app
+
+import (
+	"github.com/tsuru/tsuru/app/bind"
+	"github.com/tsuru/tsuru/errors"
+	"gopkg.in/check.v1"
+)
+
+func (s *S) TestParseEnvsDotenv(c *check.C) {
+	data := []byte(`# database config
+DATABASE_HOST=localhost
+export DATABASE_USER = root
+DATABASE_PASSWORD="s3cr\"et\nline"
+GREETING='hello # world'
+EMPTY=
+PORT=8080 # http port
+API_KEY=abc123 # private
+TOKEN="x y" # private
+`)
+	envs, err := ParseEnvs(data, EnvFormatDotenv)
+	c.Assert(err, check.IsNil)
+	c.Assert(envs, check.DeepEquals, []bind.EnvVar{
+		{Name: "DATABASE_HOST", Value: "localhost", Public: true},
+		{Name: "DATABASE_USER", Value: "root", Public: true},
+		{Name: "DATABASE_PASSWORD", Value: "s3cr\"et\nline", Public: true},
+		{Name: "GREETING", Value: "hello # world", Public: true},
+		{Name: "EMPTY", Value: "", Public: true},
+		{Name: "PORT", Value: "8080", Public: true},
+		{Name: "API_KEY", Value: "abc123", Public: false},
+		{Name: "TOKEN", Value: "x y", Public: false},
+	})
+}
+
+func (s *S) TestParseEnvsJSON(c *check.C) {
+	data := []byte(`[{"name": "DATABASE_HOST", "value": "localhost", "public": true}, {"name": "DATABASE_PASSWORD", "value": "secret"}]`)
+	envs, err := ParseEnvs(data, EnvFormatJSON)
+	c.Assert(err, check.IsNil)
+	c.Assert(envs, check.DeepEquals, []bind.EnvVar{
+		{Name: "DATABASE_HOST", Value: "localhost", Public: true},
+		{Name: "DATABASE_PASSWORD", Value: "secret", Public: false},
+	})
+}
+
+func (s *S) TestParseEnvsInvalid(c *check.C) {
+	tests := []struct {
+		data   string
+		format string
+	}{
+		{"DATABASE_HOST", EnvFormatDotenv},
+		{"DATABASE_HOST=\"localhost", EnvFormatDotenv},
+		{"DATABASE-HOST=localhost", EnvFormatDotenv},
+		{"TSURU_APP_TOKEN=123", EnvFormatDotenv},
+		{"{", EnvFormatJSON},
+		{"A=b", "yaml"},
+	}
+	for _, tt := range tests {
+		_, err := ParseEnvs([]byte(tt.data), tt.format)
+		c.Check(err, check.FitsTypeOf, &errors.ValidationError{}, check.Commentf("data: %q", tt.data))
+	}
+}
+
+func (s *S) TestExportEnvs(c *check.C) {
+	a := App{
+		Name: "myapp",
+		Env: map[string]bind.EnvVar{
+			"TSURU_APPNAME":     {Name: "TSURU_APPNAME", Value: "myapp"},
+			"DATABASE_USER":     {Name: "DATABASE_USER", Value: "root", Public: true},
+			"DATABASE_PASSWORD": {Name: "DATABASE_PASSWORD", Value: "s3cr\"et\nline"},
+			"EMPTY_SECRET":      {Name: "EMPTY_SECRET", Value: ""},
+		},
+		ServiceEnvs: []bind.ServiceEnvVar{
+			{EnvVar: bind.EnvVar{Name: "MYSQL_HOST", Value: "10.0.0.1"}, ServiceName: "mysql", InstanceName: "db"},
+		},
+	}
+	data, err := a.ExportEnvs(EnvFormatDotenv)
+	c.Assert(err, check.IsNil)
+	c.Assert(string(data), check.Equals, "DATABASE_PASSWORD=\"s3cr\\\"et\\nline\" # private\nDATABASE_USER=root\nEMPTY_SECRET=\"\" # private\n")
+	envs, err := ParseEnvs(data, EnvFormatDotenv)
+	c.Assert(err, check.IsNil)
+	c.Assert(envs, check.DeepEquals, []bind.EnvVar{
+		{Name: "DATABASE_PASSWORD", Value: "s3cr\"et\nline", Public: false},
+		{Name: "DATABASE_USER", Value: "root", Public: true},
+		{Name: "EMPTY_SECRET", Value: "", Public: false},
+	})
+	data, err = a.ExportEnvs(EnvFormatJSON)
+	c.Assert(err, check.IsNil)
+	c.Assert(string(data), check.Equals, `[{"name":"DATABASE_PASSWORD","value":"s3cr\"et\nline","public":false},{"name":"DATABASE_USER","value":"root","public":true},{"name":"EMPTY_SECRET","value":"","public":false}]`)
+	_, err = a.ExportEnvs("yaml")
+	c.Assert(err, check.FitsTypeOf, &errors.ValidationError{})
+}
@@ -22,6 +22,8 @@ const (
 	imageGCRunInterval = 5 * time.Minute
 )
 
+var runMu sync.Mutex
+
 func Initialize() error {
 	gc := &imgGC{once: &sync.Once{}}
 	gc.start()
@@ -53,7 +55,7 @@ func (g *imgGC) Shutdown(ctx context.Context) error {
 
 func (g *imgGC) spin() {
 	for {
-		err := removeOldImages()
+		err := Run("")
 		if err != nil {
 			log.Errorf("[image gc] errors running GC: %v", err)
 		}
@@ -65,6 +67,15 @@ func (g *imgGC) spin() {
 	}
 }
 
+// Run removes old images from the nodes and from the registry, keeping the
+// number of images configured in docker:image-history-size for each app. If
+// appName is not empty only the images of the given app are removed.
+func Run(appName string) error {
+	runMu.Lock()
+	defer runMu.Unlock()
+	return removeOldImages(appName)
+}
+
 func CleanImage(appName string, imgName string, removeFromRegistry bool) {
 	a, err := app.GetByName(appName)
 	if err != nil {
@@ -101,7 +112,7 @@ func cleanImageForApp(a *app.App, appName string, imgName string, removeFromRegi
 	}
 }
 
-func removeOldImages() error {
+func removeOldImages(onlyApp string) error {
 	log.Debugf("[image gc] starting image gc process")
 	defer log.Debugf("[image gc] finished image gc process")
 	allAppImages, err := image.ListAllAppImages()
@@ -111,6 +122,9 @@ func removeOldImages() error {
 	historySize := image.ImageHistorySize()
 	multi := tsuruErrors.NewMultiError()
 	for appName, appImages := range allAppImages {
+		if onlyApp != "" && appName != onlyApp {
+			continue
+		}
 		log.Debugf("[image gc] processing %d images for app %q", len(appImages.BuilderImages)+len(appImages.DeployImages), appName)
 		a, err := app.GetByName(appName)
 		if err != nil && err != app.ErrAppNotFound {
@@ -322,3 +322,15 @@ func (s *S) TestGCStartWithAppStressNotFound(c *check.C) {
 		u.Host + "/tsuru/app-myapp:v11-builder",
 	})
 }
+
+func (s *S) TestRunOnlyApp(c *check.C) {
+	for i := 0; i < 12; i++ {
+		err := image.AppendAppImageName("myapp", fmt.Sprintf("tsuru/app-myapp:v%d", i))
+		c.Assert(err, check.IsNil)
+	}
+	err := Run("otherapp")
+	c.Assert(err, check.IsNil)
+	appImgs, err := image.ListAppImages("myapp")
+	c.Assert(err, check.IsNil)
+	c.Assert(appImgs, check.HasLen, 12)
+}
@@ -5,6 +5,7 @@
 package image
 
 import (
+	"encoding/json"
 	"fmt"
 	"regexp"
 	"strings"
@@ -116,6 +117,12 @@ func customDataToImageMetadata(imageName string, customData map[string]interface
 		}
 		delete(customData, "procfile")
 	}
+	if data, ok := customData["restart_policy"]; ok {
+		err := validateRestartPolicy(data)
+		if err != nil {
+			return nil, err
+		}
+	}
 	data := ImageMetadata{
 		Name:       imageName,
 		Processes:  processes,
@@ -127,6 +134,19 @@ func customDataToImageMetadata(imageName string, customData map[string]interface
 	return &data, nil
 }
 
+func validateRestartPolicy(data interface{}) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	var policy provision.TsuruYamlRestartPolicy
+	err = json.Unmarshal(raw, &policy)
+	if err != nil {
+		return errors.Wrap(err, "invalid restart_policy")
+	}
+	return policy.Validate()
+}
+
 func SaveImageCustomData(imageName string, customData map[string]interface{}) error {
 	data, err := customDataToImageMetadata(imageName, customData)
 	if err != nil {
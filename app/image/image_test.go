@@ -312,6 +312,24 @@ func (s *S) TestSaveImageCustomData(c *check.C) {
 	})
 }
 
+func (s *S) TestSaveImageCustomDataInvalidRestartPolicy(c *check.C) {
+	tests := []map[string]interface{}{
+		{"policy": "on-faliure"},
+		{"policy": "on-failure", "max_restarts": -1},
+	}
+	for _, policy := range tests {
+		err := SaveImageCustomData("tsuru/app-myapp:v1", map[string]interface{}{"restart_policy": policy})
+		c.Check(err, check.NotNil)
+	}
+	err := SaveImageCustomData("tsuru/app-myapp:v1", map[string]interface{}{
+		"restart_policy": provision.TsuruYamlRestartPolicy{Policy: "on-failure", MaxRestarts: 3},
+	})
+	c.Assert(err, check.IsNil)
+	yamlData, err := GetImageTsuruYamlData("tsuru/app-myapp:v1")
+	c.Assert(err, check.IsNil)
+	c.Assert(yamlData.RestartPolicy, check.Equals, provision.TsuruYamlRestartPolicy{Policy: "on-failure", MaxRestarts: 3})
+}
+
 func (s *S) TestSaveImageCustomDataProcfile(c *check.C) {
 	img1 := "tsuru/app-myapp:v1"
 	customData1 := map[string]interface{}{
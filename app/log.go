@@ -26,6 +26,8 @@ var (
 	bulkMaxNumberMsgs    = 1000
 	bulkQueueMaxSize     = 10000
 
+	logRedactRefreshInterval = 10 * time.Second
+
 	buckets = append([]float64{0.1, 0.5}, prometheus.ExponentialBuckets(1, 1.6, 15)...)
 
 	logsInQueue = prometheus.NewGauge(prometheus.GaugeOpts{
@@ -288,6 +290,8 @@ func (d *LogDispatcher) Shutdown(ctx context.Context) error {
 type appLogDispatcher struct {
 	appName string
 	*bulkProcessor
+	replacer        *strings.Replacer
+	replacerExpires time.Time
 }
 
 func newAppLogDispatcher(appName string) *appLogDispatcher {
@@ -300,7 +304,30 @@ func newAppLogDispatcher(appName string) *appLogDispatcher {
 	return d
 }
 
+// redact hides the values of the private environment variables of the app
+// from the messages. The variables are reloaded every
+// logRedactRefreshInterval, so new values are also hidden.
+func (d *appLogDispatcher) redact(msgs []interface{}) {
+	if now := time.Now(); now.After(d.replacerExpires) {
+		a, err := GetByName(d.appName)
+		if err != nil {
+			log.Errorf("[log flusher] unable to get app %q to hide private envs: %s", d.appName, err)
+		} else {
+			d.replacer = a.privateEnvsReplacer()
+		}
+		d.replacerExpires = now.Add(logRedactRefreshInterval)
+	}
+	if d.replacer == nil {
+		return
+	}
+	for _, msg := range msgs {
+		applog := msg.(*Applog)
+		applog.Message = d.replacer.Replace(applog.Message)
+	}
+}
+
 func (d *appLogDispatcher) flush(msgs []interface{}, lastMessage *msgWithTS) bool {
+	d.redact(msgs)
 	conn, err := db.LogConn()
 	if err != nil {
 		log.Errorf("[log flusher] unable to connect to mongodb: %s", err)
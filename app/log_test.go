@@ -12,6 +12,7 @@ import (
 
 	dto "github.com/prometheus/client_model/go"
 	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/app/bind"
 	"github.com/tsuru/tsuru/db"
 	"gopkg.in/check.v1"
 )
@@ -258,6 +259,24 @@ func (s *S) TestLogDispatcherSend(c *check.C) {
 	compareLogs(c, []Applog{recvMsg}, []Applog{logMsg})
 }
 
+func (s *S) TestLogDispatcherSendRedactsPrivateEnvs(c *check.C) {
+	app := App{Name: "myapp1", Platform: "zend", TeamOwner: s.team.Name}
+	err := CreateApp(&app, s.user)
+	c.Assert(err, check.IsNil)
+	err = app.SetEnvs(bind.SetEnvArgs{Envs: []bind.EnvVar{{Name: "DB_PASSWORD", Value: "s3cr3t-pass"}}})
+	c.Assert(err, check.IsNil)
+	dispatcher := NewlogDispatcher(2000000)
+	logMsg := Applog{
+		Date: time.Now(), Message: "connecting with s3cr3t-pass", Source: "web", AppName: "myapp1", Unit: "unit1",
+	}
+	dispatcher.Send(&logMsg)
+	dispatcher.Shutdown(context.Background())
+	logs, err := app.LastLogs(1, Applog{})
+	c.Assert(err, check.IsNil)
+	c.Assert(logs, check.HasLen, 1)
+	c.Assert(logs[0].Message, check.Equals, "connecting with *****")
+}
+
 func (s *S) TestLogDispatcherSendConcurrent(c *check.C) {
 	app1 := App{Name: "myapp1", Platform: "zend", TeamOwner: s.team.Name}
 	err := CreateApp(&app1, s.user)
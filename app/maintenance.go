@@ -0,0 +1,104 @@
+// Copyright 2018 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package app
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+	"github.com/pkg/errors"
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/db"
+	"github.com/tsuru/tsuru/log"
+	"github.com/tsuru/tsuru/router/rebuild"
+)
+
+var ErrMaintenanceNotConfigured = errors.New("maintenance mode is not available: maintenance:address is not set")
+
+// maintenanceAddress returns the address of the server responding for apps
+// in maintenance mode.
+func maintenanceAddress() (*url.URL, error) {
+	addr, _ := config.GetString("maintenance:address")
+	if addr == "" {
+		return nil, ErrMaintenanceNotConfigured
+	}
+	u, err := url.Parse(addr)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid maintenance:address")
+	}
+	return u, nil
+}
+
+// EnableMaintenance routes the requests to the app to the maintenance server,
+// defined in maintenance:address. Units of the app are kept untouched.
+func (app *App) EnableMaintenance(w io.Writer) error {
+	if _, err := maintenanceAddress(); err != nil {
+		return err
+	}
+	return app.setMaintenance(true, w)
+}
+
+// DisableMaintenance routes the requests to the app back to its units.
+func (app *App) DisableMaintenance(w io.Writer) error {
+	return app.setMaintenance(false, w)
+}
+
+// InMaintenance returns whether requests to the app are being routed to the
+// maintenance server. Routes are only added through the routes rebuild, which
+// uses RoutableAddresses, or by provisioners checking provision.InMaintenance.
+func (app *App) InMaintenance() bool {
+	return app.Maintenance
+}
+
+func (app *App) setMaintenance(enabled bool, w io.Writer) error {
+	if w == nil {
+		w = ioutil.Discard
+	}
+	msg := "---- Enabling maintenance mode for the app %q ----\n"
+	if !enabled {
+		msg = "---- Disabling maintenance mode for the app %q ----\n"
+	}
+	fmt.Fprintf(w, msg, app.Name)
+	err := app.updateMaintenance(enabled)
+	if err != nil {
+		return err
+	}
+	_, err = rebuild.RebuildRoutes(app, false)
+	if err != nil {
+		// Keeps the flag consistent with the routes, as the routes of the
+		// app are only changed by the rebuild.
+		if rollbackErr := app.updateMaintenance(!enabled); rollbackErr != nil {
+			log.Errorf("unable to roll back maintenance mode of app %q: %v", app.Name, rollbackErr)
+		}
+		rebuild.RoutesRebuildOrEnqueue(app.Name)
+		return err
+	}
+	return nil
+}
+
+func (app *App) updateMaintenance(enabled bool) error {
+	conn, err := db.Conn()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	update := bson.M{"$set": bson.M{"maintenance": true}}
+	if !enabled {
+		update = bson.M{"$unset": bson.M{"maintenance": ""}}
+	}
+	err = conn.Apps().Update(bson.M{"name": app.Name}, update)
+	if err == mgo.ErrNotFound {
+		return ErrAppNotFound
+	}
+	if err != nil {
+		return err
+	}
+	app.Maintenance = enabled
+	return nil
+}
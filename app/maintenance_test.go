@@ -0,0 +1,104 @@
+// Copyright 2018 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package app
+
+import (
+	"bytes"
+	"net/url"
+
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/router/routertest"
+	appTypes "github.com/tsuru/tsuru/types/app"
+	"gopkg.in/check.v1"
+)
+
+func (s *S) TestEnableMaintenance(c *check.C) {
+	config.Set("maintenance:address", "http://maintenance.example.com:8080")
+	defer config.Unset("maintenance:address")
+	a := App{Name: "myapp", TeamOwner: s.team.Name, Routers: []appTypes.AppRouter{{Name: "fake"}}}
+	err := CreateApp(&a, s.user)
+	c.Assert(err, check.IsNil)
+	s.provisioner.AddUnits(&a, 2, "web", nil)
+	units, err := a.Units()
+	c.Assert(err, check.IsNil)
+	var buf bytes.Buffer
+	err = a.EnableMaintenance(&buf)
+	c.Assert(err, check.IsNil)
+	c.Assert(buf.String(), check.Equals, "---- Enabling maintenance mode for the app \"myapp\" ----\n")
+	c.Assert(a.Maintenance, check.Equals, true)
+	dbApp, err := GetByName(a.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbApp.Maintenance, check.Equals, true)
+	routes, err := routertest.FakeRouter.Routes(a.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(routes, check.HasLen, 1)
+	c.Assert(routes[0].String(), check.Equals, "http://maintenance.example.com:8080")
+	c.Assert(s.provisioner.GetUnits(&a), check.HasLen, len(units))
+	buf.Reset()
+	err = dbApp.DisableMaintenance(&buf)
+	c.Assert(err, check.IsNil)
+	c.Assert(buf.String(), check.Equals, "---- Disabling maintenance mode for the app \"myapp\" ----\n")
+	dbApp, err = GetByName(a.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbApp.Maintenance, check.Equals, false)
+	routes, err = routertest.FakeRouter.Routes(a.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(routes, check.HasLen, 2)
+	for _, u := range units {
+		c.Assert(routertest.FakeRouter.HasRoute(a.Name, u.Address.String()), check.Equals, true)
+	}
+}
+
+func (s *S) TestEnableMaintenanceNotConfigured(c *check.C) {
+	a := App{Name: "myapp", TeamOwner: s.team.Name}
+	err := CreateApp(&a, s.user)
+	c.Assert(err, check.IsNil)
+	err = a.EnableMaintenance(nil)
+	c.Assert(err, check.Equals, ErrMaintenanceNotConfigured)
+	dbApp, err := GetByName(a.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbApp.Maintenance, check.Equals, false)
+}
+
+func (s *S) TestEnableMaintenanceRebuildFailure(c *check.C) {
+	config.Set("maintenance:address", "http://maintenance.example.com:8080")
+	defer config.Unset("maintenance:address")
+	a := App{Name: "myapp", TeamOwner: s.team.Name, Routers: []appTypes.AppRouter{{Name: "fake"}}}
+	err := CreateApp(&a, s.user)
+	c.Assert(err, check.IsNil)
+	s.provisioner.AddUnits(&a, 1, "web", nil)
+	routertest.FakeRouter.FailForIp("maintenance.example.com:8080")
+	defer routertest.FakeRouter.RemoveFailForIp("maintenance.example.com:8080")
+	err = a.EnableMaintenance(nil)
+	c.Assert(err, check.Equals, routertest.ErrForcedFailure)
+	c.Assert(a.Maintenance, check.Equals, false)
+	dbApp, err := GetByName(a.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbApp.Maintenance, check.Equals, false)
+	c.Assert(routertest.FakeRouter.HasRoute(a.Name, "http://maintenance.example.com:8080"), check.Equals, false)
+}
+
+func (s *S) TestMaintenanceKeepsRoutesOnRebuild(c *check.C) {
+	config.Set("maintenance:address", "http://maintenance.example.com:8080")
+	defer config.Unset("maintenance:address")
+	a := App{Name: "myapp", TeamOwner: s.team.Name, Routers: []appTypes.AppRouter{{Name: "fake"}}}
+	err := CreateApp(&a, s.user)
+	c.Assert(err, check.IsNil)
+	err = a.EnableMaintenance(nil)
+	c.Assert(err, check.IsNil)
+	// Like the swarm and kubernetes provisioners, the fake provisioner
+	// relies on the routes rebuild to route new units.
+	s.provisioner.AddUnits(&a, 2, "web", nil)
+	err = a.Restart("", nil)
+	c.Assert(err, check.IsNil)
+	proxyURL, err := url.Parse("http://sleep.example.com")
+	c.Assert(err, check.IsNil)
+	err = a.Sleep(nil, "", proxyURL)
+	c.Assert(err, check.IsNil)
+	routes, err := routertest.FakeRouter.Routes(a.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(routes, check.HasLen, 1)
+	c.Assert(routes[0].String(), check.Equals, "http://maintenance.example.com:8080")
+}
@@ -143,3 +143,34 @@ func MigrateAppPlanIDToPlanName() error {
 	}
 	return nil
 }
+
+type appWithEnvs struct {
+	Name        string
+	Env         map[string]bind.EnvVar
+	ServiceEnvs []bind.ServiceEnvVar
+}
+
+// MigrateEncryptAppEnvs rewrites the environment variables of every app,
+// encrypting them with the current key in env-encryption:key. It can be run
+// again after changing the key, as long as the previous one is listed in
+// env-encryption:old-keys.
+func MigrateEncryptAppEnvs() error {
+	conn, err := db.Conn()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	iter := conn.Apps().Find(nil).Select(bson.M{"name": 1, "env": 1, "serviceenvs": 1}).Iter()
+	var a appWithEnvs
+	for iter.Next(&a) {
+		err = conn.Apps().Update(bson.M{"name": a.Name}, bson.M{"$set": bson.M{
+			"env":         a.Env,
+			"serviceenvs": a.ServiceEnvs,
+		}})
+		if err != nil {
+			return err
+		}
+		a = appWithEnvs{}
+	}
+	return iter.Close()
+}
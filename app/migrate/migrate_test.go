@@ -269,3 +269,30 @@ func (s *S) TestMigrateAppPlanIDToPlanName(c *check.C) {
 	c.Assert(err, check.IsNil)
 	c.Assert(a.Plan.Name, check.Equals, "plan-id")
 }
+
+func (s *S) TestMigrateEncryptAppEnvs(c *check.C) {
+	a := &app.App{
+		Name: "myapp",
+		Env:  map[string]bind.EnvVar{"A": {Name: "A", Value: "secret", Public: true}},
+		ServiceEnvs: []bind.ServiceEnvVar{
+			{EnvVar: bind.EnvVar{Name: "B", Value: "pass"}, ServiceName: "mysql", InstanceName: "db"},
+		},
+	}
+	err := s.conn.Apps().Insert(a)
+	c.Assert(err, check.IsNil)
+	config.Set("env-encryption:key", "my-key")
+	defer config.Unset("env-encryption")
+	err = MigrateEncryptAppEnvs()
+	c.Assert(err, check.IsNil)
+	var raw bson.M
+	err = s.conn.Apps().Find(bson.M{"name": "myapp"}).One(&raw)
+	c.Assert(err, check.IsNil)
+	storedEnv := raw["env"].(bson.M)["A"].(bson.M)
+	c.Assert(storedEnv["value"], check.Matches, "tsuru-encrypted:v1:.*")
+	storedServiceEnv := raw["serviceenvs"].([]interface{})[0].(bson.M)
+	c.Assert(storedServiceEnv["value"], check.Matches, "tsuru-encrypted:v1:.*")
+	dbApp, err := app.GetByName("myapp")
+	c.Assert(err, check.IsNil)
+	c.Assert(dbApp.Env, check.DeepEquals, a.Env)
+	c.Assert(dbApp.ServiceEnvs, check.DeepEquals, a.ServiceEnvs)
+}
@@ -0,0 +1,246 @@
+// Copyright 2018 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package app
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+	"github.com/pkg/errors"
+	"github.com/tsuru/tsuru/app/image"
+	"github.com/tsuru/tsuru/db"
+	tsuruErrors "github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/log"
+	"github.com/tsuru/tsuru/permission"
+)
+
+const scheduledScaleKind = "scale-schedule"
+
+var ErrScaleScheduleNotFound = errors.New("scale schedule not found")
+
+// ScaleSchedule sets the number of units of a process of the app to Units
+// every time the cron expression in Schedule matches. Combining schedules
+// allows apps to follow predictable traffic patterns, e.g. scaling up in the
+// morning and down in the evening.
+type ScaleSchedule struct {
+	Name     string
+	Schedule string
+	Process  string
+	Units    uint
+}
+
+func parseScaleSchedule(expr string) (*cronSchedule, error) {
+	sched, err := parseCronSchedule(expr)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid scale schedule %q", strings.TrimSpace(expr))
+	}
+	return sched, nil
+}
+
+// AddScaleSchedule adds a new scale schedule to the app, replacing any
+// schedule with the same name.
+func (app *App) AddScaleSchedule(sched ScaleSchedule) error {
+	sched.Name = strings.TrimSpace(sched.Name)
+	sched.Schedule = strings.TrimSpace(sched.Schedule)
+	if sched.Name == "" {
+		return &tsuruErrors.ValidationError{Message: "scale schedule name is required"}
+	}
+	if _, err := parseScaleSchedule(sched.Schedule); err != nil {
+		return &tsuruErrors.ValidationError{Message: err.Error()}
+	}
+	if err := app.validateScaleScheduleUnits(&sched); err != nil {
+		return err
+	}
+	schedules := []ScaleSchedule{sched}
+	for _, s := range app.ScaleSchedules {
+		if s.Name != sched.Name {
+			schedules = append(schedules, s)
+		}
+	}
+	return app.saveScaleSchedules(schedules)
+}
+
+// validateScaleScheduleUnits checks the process of the schedule is one of the
+// processes of the app, defaulting to the only process if none is given, and
+// that the number of units fits in the app quota.
+func (app *App) validateScaleScheduleUnits(sched *ScaleSchedule) error {
+	processes, err := image.AllAppProcesses(app.Name)
+	if err != nil {
+		if errors.Cause(err) == image.ErrNoImagesAvailable {
+			return &tsuruErrors.ValidationError{Message: "scale schedules can only be added after the first deploy"}
+		}
+		return err
+	}
+	if sched.Process == "" {
+		if len(processes) != 1 {
+			return &tsuruErrors.ValidationError{Message: "process is required, the app has more than one process"}
+		}
+		sched.Process = processes[0]
+	}
+	var found bool
+	for _, p := range processes {
+		if p == sched.Process {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return &tsuruErrors.ValidationError{Message: fmt.Sprintf("process %q not found in app %q", sched.Process, app.Name)}
+	}
+	if !app.Quota.Unlimited() && int(sched.Units) > app.Quota.Limit {
+		return &tsuruErrors.ValidationError{Message: fmt.Sprintf("cannot scale to %d units, the app quota is %d units", sched.Units, app.Quota.Limit)}
+	}
+	return nil
+}
+
+// RemoveScaleSchedule removes the scale schedule with the given name.
+func (app *App) RemoveScaleSchedule(name string) error {
+	var schedules []ScaleSchedule
+	for _, s := range app.ScaleSchedules {
+		if s.Name != name {
+			schedules = append(schedules, s)
+		}
+	}
+	if len(schedules) == len(app.ScaleSchedules) {
+		return ErrScaleScheduleNotFound
+	}
+	return app.saveScaleSchedules(schedules)
+}
+
+func (app *App) saveScaleSchedules(schedules []ScaleSchedule) error {
+	conn, err := db.Conn()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	update := bson.M{"$set": bson.M{"scaleschedules": schedules}}
+	if len(schedules) == 0 {
+		update = bson.M{"$unset": bson.M{"scaleschedules": ""}}
+	}
+	err = conn.Apps().Update(bson.M{"name": app.Name}, update)
+	if err == mgo.ErrNotFound {
+		return ErrAppNotFound
+	}
+	if err != nil {
+		return err
+	}
+	app.ScaleSchedules = schedules
+	return nil
+}
+
+// scale adds or removes units of the given process until it has the given
+// number of units.
+func (app *App) scale(process string, n uint, w io.Writer) error {
+	units, err := app.Units()
+	if err != nil {
+		return err
+	}
+	var current uint
+	for _, u := range units {
+		if process == "" || u.ProcessName == process {
+			current++
+		}
+	}
+	switch {
+	case n > current:
+		return app.AddUnits(n-current, process, w)
+	case n < current:
+		return app.RemoveUnits(current-n, process, w)
+	}
+	return nil
+}
+
+func (s *appScheduler) runScheduledScales(now time.Time) error {
+	now = now.UTC().Truncate(time.Minute)
+	conn, err := db.Conn()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	var apps []App
+	query := bson.M{"scaleschedules": bson.M{"$exists": true}}
+	err = conn.Apps().Find(query).All(&apps)
+	if err != nil {
+		return err
+	}
+	for i := range apps {
+		a := &apps[i]
+		var toRun []ScaleSchedule
+		for _, sched := range a.ScaleSchedules {
+			cron, err := parseScaleSchedule(sched.Schedule)
+			if err != nil {
+				log.Errorf("[scale scheduler] ignoring schedule %q of app %q: %v", sched.Name, a.Name, err)
+				continue
+			}
+			if !cron.match(now) {
+				continue
+			}
+			claimed, err := claimScheduledScale(a.Name, sched.Name, now)
+			if err != nil {
+				log.Errorf("[scale scheduler] unable to claim schedule %q of app %q: %v", sched.Name, a.Name, err)
+				continue
+			}
+			if claimed {
+				toRun = append(toRun, sched)
+			}
+		}
+		if len(toRun) == 0 {
+			continue
+		}
+		s.running.Add(1)
+		go func() {
+			defer s.running.Done()
+			for _, sched := range toRun {
+				if err := runScheduledScale(a, sched); err != nil {
+					log.Errorf("[scale scheduler] error scaling app %q with schedule %q: %v", a.Name, sched.Name, err)
+				}
+			}
+		}()
+	}
+	return nil
+}
+
+// claimScheduledScale marks the run of the app schedule in the given minute
+// as taken, ensuring only one tsurud instance runs it.
+func claimScheduledScale(appName, schedName string, minute time.Time) (bool, error) {
+	conn, err := db.Conn()
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+	_, err = conn.Collection("scheduled_scales").Upsert(
+		bson.M{"_id": appName + "/" + schedName, "last": bson.M{"$lt": minute}},
+		bson.M{"$set": bson.M{"last": minute}},
+	)
+	if mgo.IsDup(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func runScheduledScale(a *App, sched ScaleSchedule) (err error) {
+	evt, err := event.NewInternal(&event.Opts{
+		Target:       event.Target{Type: event.TargetTypeApp, Value: a.Name},
+		InternalKind: scheduledScaleKind,
+		CustomData:   sched,
+		Allowed: event.Allowed(permission.PermAppReadEvents, append(permission.Contexts(permission.CtxTeam, a.Teams),
+			permission.Context(permission.CtxApp, a.Name),
+			permission.Context(permission.CtxPool, a.Pool),
+		)...),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(err) }()
+	return a.scale(sched.Process, sched.Units, evt)
+}
@@ -0,0 +1,162 @@
+// Copyright 2018 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package app
+
+import (
+	"time"
+
+	"github.com/tsuru/tsuru/app/image"
+	"github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/event/eventtest"
+	"gopkg.in/check.v1"
+)
+
+func saveAppProcesses(c *check.C, appName string, processes ...string) {
+	procs := map[string]interface{}{}
+	for _, p := range processes {
+		procs[p] = "run " + p
+	}
+	imageName := "tsuru/app-" + appName + ":v1"
+	err := image.SaveImageCustomData(imageName, map[string]interface{}{"processes": procs})
+	c.Assert(err, check.IsNil)
+	err = image.AppendAppImageName(appName, imageName)
+	c.Assert(err, check.IsNil)
+}
+
+func (s *S) TestAddScaleSchedule(c *check.C) {
+	a := App{Name: "myapp", TeamOwner: s.team.Name}
+	err := CreateApp(&a, s.user)
+	c.Assert(err, check.IsNil)
+	saveAppProcesses(c, a.Name, "web", "worker")
+	err = a.AddScaleSchedule(ScaleSchedule{Name: "day", Schedule: "0 8 * * 1-5", Process: "web", Units: 10})
+	c.Assert(err, check.IsNil)
+	err = a.AddScaleSchedule(ScaleSchedule{Name: "night", Schedule: "0 20 * * 1-5", Process: "web", Units: 3})
+	c.Assert(err, check.IsNil)
+	err = a.AddScaleSchedule(ScaleSchedule{Name: "day", Schedule: "0 9 * * 1-5", Process: "web", Units: 8})
+	c.Assert(err, check.IsNil)
+	dbApp, err := GetByName(a.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbApp.ScaleSchedules, check.DeepEquals, []ScaleSchedule{
+		{Name: "day", Schedule: "0 9 * * 1-5", Process: "web", Units: 8},
+		{Name: "night", Schedule: "0 20 * * 1-5", Process: "web", Units: 3},
+	})
+}
+
+func (s *S) TestAddScaleScheduleInvalid(c *check.C) {
+	a := App{Name: "myapp", TeamOwner: s.team.Name}
+	err := CreateApp(&a, s.user)
+	c.Assert(err, check.IsNil)
+	saveAppProcesses(c, a.Name, "web")
+	err = a.AddScaleSchedule(ScaleSchedule{Schedule: "0 8 * * *", Units: 10})
+	c.Assert(err, check.FitsTypeOf, &errors.ValidationError{})
+	err = a.AddScaleSchedule(ScaleSchedule{Name: "day", Schedule: "0 25 * * *", Units: 10})
+	c.Assert(err, check.FitsTypeOf, &errors.ValidationError{})
+	c.Assert(err, check.ErrorMatches, `invalid scale schedule "0 25 \* \* \*": value "25" out of range 0-23`)
+	dbApp, err := GetByName(a.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbApp.ScaleSchedules, check.HasLen, 0)
+}
+
+func (s *S) TestAddScaleScheduleDefaultProcess(c *check.C) {
+	a := App{Name: "myapp", TeamOwner: s.team.Name}
+	err := CreateApp(&a, s.user)
+	c.Assert(err, check.IsNil)
+	saveAppProcesses(c, a.Name, "web")
+	err = a.AddScaleSchedule(ScaleSchedule{Name: "day", Schedule: "0 8 * * 1-5", Units: 10})
+	c.Assert(err, check.IsNil)
+	c.Assert(a.ScaleSchedules, check.DeepEquals, []ScaleSchedule{
+		{Name: "day", Schedule: "0 8 * * 1-5", Process: "web", Units: 10},
+	})
+}
+
+func (s *S) TestAddScaleScheduleInvalidProcess(c *check.C) {
+	a := App{Name: "myapp", TeamOwner: s.team.Name}
+	err := CreateApp(&a, s.user)
+	c.Assert(err, check.IsNil)
+	err = a.AddScaleSchedule(ScaleSchedule{Name: "day", Schedule: "0 8 * * 1-5", Process: "web", Units: 10})
+	c.Assert(err, check.FitsTypeOf, &errors.ValidationError{})
+	c.Assert(err, check.ErrorMatches, "scale schedules can only be added after the first deploy")
+	saveAppProcesses(c, a.Name, "web", "worker")
+	err = a.AddScaleSchedule(ScaleSchedule{Name: "day", Schedule: "0 8 * * 1-5", Units: 10})
+	c.Assert(err, check.FitsTypeOf, &errors.ValidationError{})
+	c.Assert(err, check.ErrorMatches, "process is required, the app has more than one process")
+	err = a.AddScaleSchedule(ScaleSchedule{Name: "day", Schedule: "0 8 * * 1-5", Process: "api", Units: 10})
+	c.Assert(err, check.FitsTypeOf, &errors.ValidationError{})
+	c.Assert(err, check.ErrorMatches, `process "api" not found in app "myapp"`)
+	dbApp, err := GetByName(a.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbApp.ScaleSchedules, check.HasLen, 0)
+}
+
+func (s *S) TestAddScaleScheduleQuotaExceeded(c *check.C) {
+	a := App{Name: "myapp", TeamOwner: s.team.Name}
+	err := CreateApp(&a, s.user)
+	c.Assert(err, check.IsNil)
+	saveAppProcesses(c, a.Name, "web")
+	a.Quota.Limit = 5
+	err = a.AddScaleSchedule(ScaleSchedule{Name: "day", Schedule: "0 8 * * 1-5", Process: "web", Units: 10})
+	c.Assert(err, check.FitsTypeOf, &errors.ValidationError{})
+	c.Assert(err, check.ErrorMatches, "cannot scale to 10 units, the app quota is 5 units")
+}
+
+func (s *S) TestRemoveScaleSchedule(c *check.C) {
+	a := App{Name: "myapp", TeamOwner: s.team.Name}
+	err := CreateApp(&a, s.user)
+	c.Assert(err, check.IsNil)
+	saveAppProcesses(c, a.Name, "web")
+	err = a.AddScaleSchedule(ScaleSchedule{Name: "day", Schedule: "0 8 * * 1-5", Units: 10})
+	c.Assert(err, check.IsNil)
+	err = a.RemoveScaleSchedule("night")
+	c.Assert(err, check.Equals, ErrScaleScheduleNotFound)
+	err = a.RemoveScaleSchedule("day")
+	c.Assert(err, check.IsNil)
+	dbApp, err := GetByName(a.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbApp.ScaleSchedules, check.HasLen, 0)
+}
+
+func (s *S) TestRunScheduledScales(c *check.C) {
+	a := App{Name: "myapp", TeamOwner: s.team.Name}
+	err := CreateApp(&a, s.user)
+	c.Assert(err, check.IsNil)
+	saveAppProcesses(c, a.Name, "web")
+	err = a.AddUnits(3, "web", nil)
+	c.Assert(err, check.IsNil)
+	err = a.AddScaleSchedule(ScaleSchedule{Name: "day", Schedule: "0 8 * * 1-5", Process: "web", Units: 5})
+	c.Assert(err, check.IsNil)
+	err = a.AddScaleSchedule(ScaleSchedule{Name: "night", Schedule: "0 20 * * 1-5", Process: "web", Units: 2})
+	c.Assert(err, check.IsNil)
+	scheduler := &appScheduler{}
+	morning := time.Date(2018, 3, 5, 8, 0, 5, 0, time.UTC)
+	err = scheduler.runScheduledScales(morning)
+	c.Assert(err, check.IsNil)
+	scheduler.running.Wait()
+	units, err := a.Units()
+	c.Assert(err, check.IsNil)
+	c.Assert(units, check.HasLen, 5)
+	c.Assert(eventtest.EventDesc{
+		Target: event.Target{Type: event.TargetTypeApp, Value: a.Name},
+		Kind:   "scale-schedule",
+		StartCustomData: map[string]interface{}{
+			"name":     "day",
+			"schedule": "0 8 * * 1-5",
+			"process":  "web",
+			"units":    5,
+		},
+	}, eventtest.HasEvent)
+	err = scheduler.runScheduledScales(morning.Add(30 * time.Second))
+	c.Assert(err, check.IsNil)
+	scheduler.running.Wait()
+	units, err = a.Units()
+	c.Assert(err, check.IsNil)
+	c.Assert(units, check.HasLen, 5)
+	err = scheduler.runScheduledScales(time.Date(2018, 3, 5, 20, 0, 0, 0, time.UTC))
+	c.Assert(err, check.IsNil)
+	scheduler.running.Wait()
+	units, err = a.Units()
+	c.Assert(err, check.IsNil)
+	c.Assert(units, check.HasLen, 2)
+}
@@ -0,0 +1,319 @@
+// Copyright 2018 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package app
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+	"github.com/pkg/errors"
+	"github.com/tsuru/tsuru/api/shutdown"
+	"github.com/tsuru/tsuru/db"
+	tsuruErrors "github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/log"
+	"github.com/tsuru/tsuru/permission"
+)
+
+const scheduledRestartKind = "restart-schedule"
+
+var scheduleMacros = map[string]string{
+	"@hourly":  "0 * * * *",
+	"@daily":   "0 0 * * *",
+	"@weekly":  "0 0 * * 0",
+	"@monthly": "0 0 1 * *",
+}
+
+type scheduleField struct {
+	min, max int
+}
+
+var scheduleFields = []scheduleField{
+	{0, 59}, // minute
+	{0, 23}, // hour
+	{1, 31}, // day of month
+	{1, 12}, // month
+	{0, 7},  // day of week, both 0 and 7 are sunday
+}
+
+// cronSchedule is a parsed cron expression, in the standard five fields
+// format: minute, hour, day of month, month and day of week. Times are
+// matched in UTC.
+type cronSchedule struct {
+	fields [5]uint64
+	// anyDay is set when either the day of month or the day of week is *.
+	// When both are restricted a time matches if any of them matches.
+	anyDay bool
+}
+
+func parseRestartSchedule(expr string) (*cronSchedule, error) {
+	sched, err := parseCronSchedule(expr)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid restart schedule %q", strings.TrimSpace(expr))
+	}
+	return sched, nil
+}
+
+func parseCronSchedule(expr string) (*cronSchedule, error) {
+	expr = strings.TrimSpace(expr)
+	if macro, ok := scheduleMacros[expr]; ok {
+		expr = macro
+	}
+	parts := strings.Fields(expr)
+	if len(parts) != len(scheduleFields) {
+		return nil, errors.Errorf("expected 5 fields, got %d", len(parts))
+	}
+	var sched cronSchedule
+	for i, part := range parts {
+		bits, err := parseScheduleField(part, scheduleFields[i])
+		if err != nil {
+			return nil, err
+		}
+		sched.fields[i] = bits
+	}
+	if sched.fields[4]&(1<<7) != 0 {
+		sched.fields[4] |= 1
+	}
+	sched.anyDay = parts[2] == "*" || parts[4] == "*"
+	return &sched, nil
+}
+
+func parseScheduleField(value string, field scheduleField) (uint64, error) {
+	var bits uint64
+	for _, item := range strings.Split(value, ",") {
+		step := 1
+		if idx := strings.Index(item, "/"); idx != -1 {
+			var err error
+			step, err = strconv.Atoi(item[idx+1:])
+			if err != nil || step < 1 {
+				return 0, errors.Errorf("invalid step in %q", item)
+			}
+			item = item[:idx]
+		}
+		start, end := field.min, field.max
+		if item != "*" {
+			var err error
+			bounds := strings.SplitN(item, "-", 2)
+			start, err = strconv.Atoi(bounds[0])
+			if err != nil {
+				return 0, errors.Errorf("invalid value %q", item)
+			}
+			end = start
+			if len(bounds) == 2 {
+				end, err = strconv.Atoi(bounds[1])
+				if err != nil {
+					return 0, errors.Errorf("invalid value %q", item)
+				}
+			}
+		}
+		if start < field.min || end > field.max || start > end {
+			return 0, errors.Errorf("value %q out of range %d-%d", item, field.min, field.max)
+		}
+		for i := start; i <= end; i += step {
+			bits |= 1 << uint(i)
+		}
+	}
+	return bits, nil
+}
+
+func (s *cronSchedule) match(t time.Time) bool {
+	t = t.UTC()
+	has := func(field, value int) bool {
+		return s.fields[field]&(1<<uint(value)) != 0
+	}
+	if !has(0, t.Minute()) || !has(1, t.Hour()) || !has(3, int(t.Month())) {
+		return false
+	}
+	dom, dow := has(2, t.Day()), has(4, int(t.Weekday()))
+	if s.anyDay {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+// SetRestartSchedule sets the cron expression used to periodically restart
+// the app. An empty schedule disables the scheduled restarts.
+func (app *App) SetRestartSchedule(schedule string) error {
+	schedule = strings.TrimSpace(schedule)
+	if schedule != "" {
+		if _, err := parseRestartSchedule(schedule); err != nil {
+			return &tsuruErrors.ValidationError{Message: err.Error()}
+		}
+	}
+	conn, err := db.Conn()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	update := bson.M{"$set": bson.M{"restartschedule": schedule}}
+	if schedule == "" {
+		update = bson.M{"$unset": bson.M{"restartschedule": ""}}
+	}
+	err = conn.Apps().Update(bson.M{"name": app.Name}, update)
+	if err == mgo.ErrNotFound {
+		return ErrAppNotFound
+	}
+	if err != nil {
+		return err
+	}
+	app.RestartSchedule = schedule
+	return nil
+}
+
+// InitializeScheduler starts the process restarting and scaling apps according
+// to their restart and scale schedules, and putting idle apps to sleep.
+func InitializeScheduler() error {
+	s := &appScheduler{once: &sync.Once{}}
+	s.start()
+	shutdown.Register(s)
+	return nil
+}
+
+type appScheduler struct {
+	once    *sync.Once
+	stopCh  chan struct{}
+	running sync.WaitGroup
+}
+
+func (s *appScheduler) start() {
+	s.once.Do(func() {
+		s.stopCh = make(chan struct{})
+		go s.spin(s.stopCh)
+	})
+}
+
+func (s *appScheduler) Shutdown(ctx context.Context) error {
+	if s.stopCh == nil {
+		return nil
+	}
+	close(s.stopCh)
+	s.stopCh = nil
+	s.once = &sync.Once{}
+	done := make(chan struct{})
+	go func() {
+		s.running.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+	return ctx.Err()
+}
+
+func (s *appScheduler) String() string {
+	return "app scheduler"
+}
+
+func (s *appScheduler) spin(stopCh chan struct{}) {
+	for {
+		now := time.Now()
+		next := now.Truncate(time.Minute).Add(time.Minute)
+		select {
+		case <-stopCh:
+			return
+		case <-time.After(next.Sub(now)):
+		}
+		err := s.runScheduledRestarts(next)
+		if err != nil {
+			log.Errorf("[restart scheduler] error running scheduled restarts: %v", err)
+		}
+		err = s.runScheduledScales(next)
+		if err != nil {
+			log.Errorf("[scale scheduler] error running scheduled scales: %v", err)
+		}
+		if next.Minute() == 0 {
+			err = s.runIdleCheck(next)
+			if err != nil {
+				log.Errorf("[auto sleep] error checking idle apps: %v", err)
+			}
+		}
+	}
+}
+
+func (s *appScheduler) runScheduledRestarts(now time.Time) error {
+	now = now.UTC().Truncate(time.Minute)
+	conn, err := db.Conn()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	var apps []App
+	query := bson.M{"restartschedule": bson.M{"$exists": true, "$ne": ""}}
+	err = conn.Apps().Find(query).All(&apps)
+	if err != nil {
+		return err
+	}
+	for i := range apps {
+		a := &apps[i]
+		sched, err := parseRestartSchedule(a.RestartSchedule)
+		if err != nil {
+			log.Errorf("[restart scheduler] ignoring app %q: %v", a.Name, err)
+			continue
+		}
+		if !sched.match(now) {
+			continue
+		}
+		claimed, err := claimScheduledRestart(a.Name, now)
+		if err != nil {
+			log.Errorf("[restart scheduler] unable to claim restart for app %q: %v", a.Name, err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+		s.running.Add(1)
+		go func() {
+			defer s.running.Done()
+			if err := runScheduledRestart(a); err != nil {
+				log.Errorf("[restart scheduler] error restarting app %q: %v", a.Name, err)
+			}
+		}()
+	}
+	return nil
+}
+
+// claimScheduledRestart marks the restart of the app in the given minute as
+// taken, ensuring only one tsurud instance runs it.
+func claimScheduledRestart(appName string, minute time.Time) (bool, error) {
+	conn, err := db.Conn()
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+	_, err = conn.Collection("scheduled_restarts").Upsert(
+		bson.M{"_id": appName, "last": bson.M{"$lt": minute}},
+		bson.M{"$set": bson.M{"last": minute}},
+	)
+	if mgo.IsDup(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func runScheduledRestart(a *App) (err error) {
+	evt, err := event.NewInternal(&event.Opts{
+		Target:       event.Target{Type: event.TargetTypeApp, Value: a.Name},
+		InternalKind: scheduledRestartKind,
+		CustomData:   map[string]string{"schedule": a.RestartSchedule},
+		Allowed: event.Allowed(permission.PermAppReadEvents, append(permission.Contexts(permission.CtxTeam, a.Teams),
+			permission.Context(permission.CtxApp, a.Name),
+			permission.Context(permission.CtxPool, a.Pool),
+		)...),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(err) }()
+	return a.Restart("", evt)
+}
@@ -0,0 +1,134 @@
+// Copyright 2018 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package app
+
+import (
+	"time"
+
+	"github.com/globalsign/mgo/bson"
+	"github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/event/eventtest"
+	"gopkg.in/check.v1"
+)
+
+func (s *S) TestParseRestartSchedule(c *check.C) {
+	date := func(value string) time.Time {
+		t, err := time.Parse("2006-01-02 15:04", value)
+		c.Assert(err, check.IsNil)
+		return t
+	}
+	tests := []struct {
+		expr  string
+		time  time.Time
+		match bool
+	}{
+		{"* * * * *", date("2018-03-05 10:31"), true},
+		{"30 3 * * *", date("2018-03-05 03:30"), true},
+		{"30 3 * * *", date("2018-03-05 03:31"), false},
+		{"*/15 * * * *", date("2018-03-05 10:45"), true},
+		{"*/15 * * * *", date("2018-03-05 10:46"), false},
+		{"0 8-18/2 * * *", date("2018-03-05 14:00"), true},
+		{"0 8-18/2 * * *", date("2018-03-05 15:00"), false},
+		{"0 0 1,15 * *", date("2018-03-15 00:00"), true},
+		{"0 0 * 3 *", date("2018-04-01 00:00"), false},
+		{"0 0 * * 1", date("2018-03-05 00:00"), true},
+		{"0 0 * * 7", date("2018-03-04 00:00"), true},
+		{"0 0 1 * 1", date("2018-03-05 00:00"), true},
+		{"0 0 1 * 1", date("2018-03-01 00:00"), true},
+		{"0 0 1 * 1", date("2018-03-06 00:00"), false},
+		{"@daily", date("2018-03-06 00:00"), true},
+		{"@hourly", date("2018-03-06 11:01"), false},
+	}
+	for i, tt := range tests {
+		sched, err := parseRestartSchedule(tt.expr)
+		c.Assert(err, check.IsNil, check.Commentf("test %d", i))
+		c.Assert(sched.match(tt.time), check.Equals, tt.match, check.Commentf("test %d", i))
+	}
+}
+
+func (s *S) TestParseRestartScheduleInvalid(c *check.C) {
+	tests := []struct {
+		expr string
+		err  string
+	}{
+		{"* * * *", `invalid restart schedule "\* \* \* \*": expected 5 fields, got 4`},
+		{"60 * * * *", `invalid restart schedule "60 \* \* \* \*": value "60" out of range 0-59`},
+		{"* * 0 * *", `.*value "0" out of range 1-31`},
+		{"*/0 * * * *", `.*invalid step in "\*/0"`},
+		{"a * * * *", `.*invalid value "a"`},
+		{"5-1 * * * *", `.*value "5-1" out of range 0-59`},
+	}
+	for _, tt := range tests {
+		_, err := parseRestartSchedule(tt.expr)
+		c.Assert(err, check.ErrorMatches, tt.err)
+	}
+}
+
+func (s *S) TestSetRestartSchedule(c *check.C) {
+	a := App{Name: "myapp", TeamOwner: s.team.Name}
+	err := CreateApp(&a, s.user)
+	c.Assert(err, check.IsNil)
+	err = a.SetRestartSchedule("0 3 * * *")
+	c.Assert(err, check.IsNil)
+	c.Assert(a.RestartSchedule, check.Equals, "0 3 * * *")
+	dbApp, err := GetByName(a.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbApp.RestartSchedule, check.Equals, "0 3 * * *")
+	err = a.SetRestartSchedule("")
+	c.Assert(err, check.IsNil)
+	dbApp, err = GetByName(a.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbApp.RestartSchedule, check.Equals, "")
+}
+
+func (s *S) TestSetRestartScheduleInvalid(c *check.C) {
+	a := App{Name: "myapp", TeamOwner: s.team.Name}
+	err := CreateApp(&a, s.user)
+	c.Assert(err, check.IsNil)
+	err = a.SetRestartSchedule("0 25 * * *")
+	c.Assert(err, check.FitsTypeOf, &errors.ValidationError{})
+	dbApp, err := GetByName(a.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbApp.RestartSchedule, check.Equals, "")
+}
+
+func (s *S) TestRunScheduledRestarts(c *check.C) {
+	a := App{Name: "myapp", TeamOwner: s.team.Name}
+	err := CreateApp(&a, s.user)
+	c.Assert(err, check.IsNil)
+	err = a.SetRestartSchedule("30 3 * * *")
+	c.Assert(err, check.IsNil)
+	other := App{Name: "otherapp", TeamOwner: s.team.Name}
+	err = CreateApp(&other, s.user)
+	c.Assert(err, check.IsNil)
+	err = other.SetRestartSchedule("0 4 * * *")
+	c.Assert(err, check.IsNil)
+	scheduler := &appScheduler{}
+	now := time.Date(2018, 3, 5, 3, 30, 12, 0, time.UTC)
+	err = scheduler.runScheduledRestarts(now)
+	c.Assert(err, check.IsNil)
+	scheduler.running.Wait()
+	c.Assert(s.provisioner.Restarts(&a, ""), check.Equals, 1)
+	c.Assert(s.provisioner.Restarts(&other, ""), check.Equals, 0)
+	c.Assert(eventtest.EventDesc{
+		Target: event.Target{Type: event.TargetTypeApp, Value: a.Name},
+		Kind:   "restart-schedule",
+		StartCustomData: map[string]interface{}{
+			"schedule": "30 3 * * *",
+		},
+	}, eventtest.HasEvent)
+	err = scheduler.runScheduledRestarts(now.Add(20 * time.Second))
+	c.Assert(err, check.IsNil)
+	scheduler.running.Wait()
+	c.Assert(s.provisioner.Restarts(&a, ""), check.Equals, 1)
+	var claim struct{ Last time.Time }
+	err = s.conn.Collection("scheduled_restarts").FindId(a.Name).One(&claim)
+	c.Assert(err, check.IsNil)
+	c.Assert(claim.Last.Equal(now.Truncate(time.Minute)), check.Equals, true)
+	count, err := s.conn.Collection("scheduled_restarts").Find(bson.M{"_id": other.Name}).Count()
+	c.Assert(err, check.IsNil)
+	c.Assert(count, check.Equals, 0)
+}
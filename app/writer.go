@@ -5,6 +5,8 @@
 package app
 
 import (
+	"io"
+	"strings"
 	"sync"
 	"time"
 
@@ -83,3 +85,77 @@ func (w *LogWriter) write(data []byte) error {
 	}
 	return w.App.Log(string(data), source, "api")
 }
+
+// redactWriter hides the values of private environment variables from data
+// written to the underlying writer. When data doesn't end a line and ends with
+// the beginning of a value, that part is held until the next call to Write,
+// so values split between two calls are also hidden. Flush must be called
+// when no more data will be written, to send what's still held.
+type redactWriter struct {
+	mu       sync.Mutex
+	w        io.Writer
+	values   []string
+	replacer *strings.Replacer
+	pending  string
+}
+
+func (w *redactWriter) Write(data []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	text := w.replacer.Replace(w.pending + string(data))
+	w.pending = ""
+	if !strings.HasSuffix(text, "\n") {
+		if n := w.partialValueLen(text); n > 0 {
+			w.pending = text[len(text)-n:]
+			text = text[:len(text)-n]
+		}
+	}
+	if text == "" {
+		return len(data), nil
+	}
+	_, err := io.WriteString(w.w, text)
+	if err != nil {
+		return 0, err
+	}
+	return len(data), nil
+}
+
+// Flush writes the data held waiting for the rest of a value.
+func (w *redactWriter) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.pending == "" {
+		return nil
+	}
+	pending := w.pending
+	w.pending = ""
+	_, err := io.WriteString(w.w, pending)
+	return err
+}
+
+// flushLog writes the data held by a writer returned by withLogWriter or
+// redactPrivateEnvs.
+func flushLog(w io.Writer) {
+	if rw, ok := w.(*redactWriter); ok {
+		rw.Flush()
+	}
+}
+
+// partialValueLen returns the length of the longest suffix of text that is
+// the beginning of one of the values.
+func (w *redactWriter) partialValueLen(text string) int {
+	var longest int
+	for _, v := range w.values {
+		n := len(v) - 1
+		if n > len(text) {
+			n = len(text)
+		}
+		for ; n > longest; n-- {
+			if strings.HasSuffix(text, v[:n]) {
+				longest = n
+				break
+			}
+		}
+	}
+	return longest
+}
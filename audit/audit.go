@@ -0,0 +1,106 @@
+// Copyright 2018 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package audit records the mutating calls made to the tsuru API.
+package audit
+
+import (
+	"time"
+
+	"github.com/globalsign/mgo/bson"
+	"github.com/tsuru/tsuru/db"
+)
+
+const maxLimit = 100
+
+// Entry is a single authenticated call to the API that could change the
+// state of tsuru. User holds the app name for calls made with app tokens.
+type Entry struct {
+	ID         bson.ObjectId `bson:"_id"`
+	Time       time.Time
+	User       string
+	AppToken   bool `bson:",omitempty"`
+	Method     string
+	Path       string
+	App        string `bson:",omitempty"`
+	StatusCode int
+	Error      string `bson:",omitempty"`
+	RequestID  string `bson:",omitempty"`
+}
+
+// Filter restricts the entries returned by List. Zero values match any
+// entry.
+type Filter struct {
+	User  string
+	App   string
+	Since time.Time
+	Until time.Time
+	Skip  int
+	Limit int
+}
+
+func (f *Filter) toQuery() bson.M {
+	query := bson.M{}
+	if f.User != "" {
+		query["user"] = f.User
+	}
+	if f.App != "" {
+		query["app"] = f.App
+	}
+	timeQuery := bson.M{}
+	if !f.Since.IsZero() {
+		timeQuery["$gte"] = f.Since
+	}
+	if !f.Until.IsZero() {
+		timeQuery["$lte"] = f.Until
+	}
+	if len(timeQuery) > 0 {
+		query["time"] = timeQuery
+	}
+	return query
+}
+
+// Add stores a new entry in the audit log.
+func Add(e *Entry) error {
+	conn, err := db.Conn()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if e.ID == "" {
+		e.ID = bson.NewObjectId()
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now().UTC()
+	}
+	return conn.Audit().Insert(e)
+}
+
+// List returns the entries matching the filter, most recent first, and the
+// total number of matching entries.
+func List(f *Filter) ([]Entry, int, error) {
+	if f == nil {
+		f = &Filter{}
+	}
+	limit := f.Limit
+	if limit <= 0 || limit > maxLimit {
+		limit = maxLimit
+	}
+	conn, err := db.Conn()
+	if err != nil {
+		return nil, 0, err
+	}
+	defer conn.Close()
+	query := conn.Audit().Find(f.toQuery())
+	total, err := query.Count()
+	if err != nil {
+		return nil, 0, err
+	}
+	var entries []Entry
+	err = query.Sort("-time", "-_id").Skip(f.Skip).Limit(limit).All(&entries)
+	if err != nil {
+		return nil, 0, err
+	}
+	return entries, total, nil
+}
@@ -0,0 +1,66 @@
+// Copyright 2018 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package audit
+
+import (
+	"time"
+
+	"gopkg.in/check.v1"
+)
+
+func (s *S) TestAdd(c *check.C) {
+	entry := Entry{User: "me@tsuru.io", Method: "POST", Path: "/apps/myapp/restart", App: "myapp", StatusCode: 200}
+	err := Add(&entry)
+	c.Assert(err, check.IsNil)
+	c.Assert(entry.ID, check.Not(check.Equals), "")
+	c.Assert(entry.Time.IsZero(), check.Equals, false)
+	var dbEntry Entry
+	err = s.conn.Audit().FindId(entry.ID).One(&dbEntry)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbEntry.User, check.Equals, "me@tsuru.io")
+	c.Assert(dbEntry.Path, check.Equals, "/apps/myapp/restart")
+	c.Assert(dbEntry.App, check.Equals, "myapp")
+	c.Assert(dbEntry.StatusCode, check.Equals, 200)
+}
+
+func (s *S) TestList(c *check.C) {
+	base := time.Date(2018, 5, 10, 12, 0, 0, 0, time.UTC)
+	entries := []Entry{
+		{Time: base, User: "a@tsuru.io", Method: "POST", Path: "/apps", StatusCode: 201},
+		{Time: base.Add(time.Hour), User: "a@tsuru.io", Method: "POST", Path: "/apps/app1/restart", App: "app1", StatusCode: 200},
+		{Time: base.Add(2 * time.Hour), User: "b@tsuru.io", Method: "DELETE", Path: "/apps/app1", App: "app1", StatusCode: 200},
+		{Time: base.Add(3 * time.Hour), User: "b@tsuru.io", Method: "POST", Path: "/apps/app2/restart", App: "app2", StatusCode: 403},
+	}
+	for i := range entries {
+		err := Add(&entries[i])
+		c.Assert(err, check.IsNil)
+	}
+	tests := []struct {
+		filter   *Filter
+		expected []int
+		total    int
+	}{
+		{nil, []int{3, 2, 1, 0}, 4},
+		{&Filter{User: "a@tsuru.io"}, []int{1, 0}, 2},
+		{&Filter{App: "app1"}, []int{2, 1}, 2},
+		{&Filter{Since: base.Add(time.Hour), Until: base.Add(2 * time.Hour)}, []int{2, 1}, 2},
+		{&Filter{User: "b@tsuru.io", App: "app1"}, []int{2}, 1},
+		{&Filter{Limit: 2, Skip: 1}, []int{2, 1}, 4},
+	}
+	for i, tt := range tests {
+		result, total, err := List(tt.filter)
+		c.Assert(err, check.IsNil)
+		c.Check(total, check.Equals, tt.total, check.Commentf("test %d", i))
+		var paths []string
+		for _, e := range result {
+			paths = append(paths, e.Path)
+		}
+		var expected []string
+		for _, idx := range tt.expected {
+			expected = append(expected, entries[idx].Path)
+		}
+		c.Check(paths, check.DeepEquals, expected, check.Commentf("test %d", i))
+	}
+}
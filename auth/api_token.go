@@ -12,8 +12,9 @@ import (
 )
 
 type APIToken struct {
-	Token     string `json:"token" bson:"apikey"`
-	UserEmail string `json:"email" bson:"email"`
+	Token     string     `json:"token" bson:"apikey"`
+	UserEmail string     `json:"email" bson:"email"`
+	Scope     TokenScope `json:"scope" bson:"apikeyscope"`
 }
 
 func (t *APIToken) GetValue() string {
@@ -37,9 +38,11 @@ func (t *APIToken) GetAppName() string {
 }
 
 func (t *APIToken) Permissions() ([]permission.Permission, error) {
-	// TODO(cezarsa): Allow creation of api tokens with a subset of user's
-	// permissions.
-	return BaseTokenPermission(t)
+	perms, err := BaseTokenPermission(t)
+	if err != nil {
+		return nil, err
+	}
+	return t.Scope.Filter(perms)
 }
 
 func getAPIToken(header string) (*APIToken, error) {
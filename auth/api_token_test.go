@@ -4,7 +4,11 @@
 
 package auth
 
-import "gopkg.in/check.v1"
+import (
+	"github.com/globalsign/mgo/bson"
+	"github.com/tsuru/tsuru/errors"
+	"gopkg.in/check.v1"
+)
 
 func (s *S) TestGetAPIToken(c *check.C) {
 	user := User{Email: "para@xmen.com", APIKey: "Quenço"}
@@ -36,3 +40,34 @@ func (s *S) TestGetAPITokenInvalid(c *check.C) {
 	c.Assert(t, check.IsNil)
 	c.Assert(err, check.Equals, ErrInvalidToken)
 }
+
+func (s *S) TestGetAPITokenScoped(c *check.C) {
+	user := User{Email: "para@xmen.com"}
+	err := user.Create()
+	c.Assert(err, check.IsNil)
+	defer user.Delete()
+	err = s.conn.Apps().Insert(bson.M{"name": "myapp"})
+	c.Assert(err, check.IsNil)
+	APIKey, err := user.RegenerateScopedAPIKey(TokenScope{Kind: TokenScopeReadOnly, Apps: []string{"myapp"}})
+	c.Assert(err, check.IsNil)
+	t, err := getAPIToken("bearer " + APIKey)
+	c.Assert(err, check.IsNil)
+	c.Assert(t.Scope.Kind, check.Equals, TokenScopeReadOnly)
+	c.Assert(t.Scope.Apps, check.DeepEquals, []string{"myapp"})
+	c.Assert(t.Scope.AppIDs, check.HasLen, 1)
+	APIKey, err = user.RegenerateAPIKey()
+	c.Assert(err, check.IsNil)
+	t, err = getAPIToken("bearer " + APIKey)
+	c.Assert(err, check.IsNil)
+	c.Assert(t.Scope.IsEmpty(), check.Equals, true)
+}
+
+func (s *S) TestRegenerateScopedAPIKeyInvalidScope(c *check.C) {
+	user := User{Email: "para@xmen.com", APIKey: "Quenço"}
+	err := user.Create()
+	c.Assert(err, check.IsNil)
+	defer user.Delete()
+	_, err = user.RegenerateScopedAPIKey(TokenScope{Kind: "all"})
+	c.Assert(err, check.FitsTypeOf, &errors.ValidationError{})
+	c.Assert(user.APIKey, check.Equals, "Quenço")
+}
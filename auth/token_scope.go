@@ -0,0 +1,202 @@
+// Copyright 2018 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package auth
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/globalsign/mgo/bson"
+	"github.com/tsuru/tsuru/db"
+	tsuruErrors "github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/permission"
+)
+
+const (
+	// TokenScopeReadOnly restricts a token to permissions used to read data.
+	TokenScopeReadOnly = "read-only"
+	// TokenScopeDeployOnly restricts a token to permissions needed to build
+	// and deploy apps.
+	TokenScopeDeployOnly = "deploy-only"
+)
+
+// TokenScope limits the permissions of an API token to a subset of the
+// permissions of its owner. An empty scope grants all of them. Apps are
+// tracked by their identity, so a new app created with the name of a removed
+// one is not part of the scope.
+type TokenScope struct {
+	Kind   string          `json:"kind,omitempty"`
+	Apps   []string        `json:"apps,omitempty"`
+	AppIDs []bson.ObjectId `json:"-" bson:",omitempty"`
+}
+
+func (s *TokenScope) IsEmpty() bool {
+	return s.Kind == "" && len(s.Apps) == 0
+}
+
+func (s *TokenScope) Validate() error {
+	switch s.Kind {
+	case "", TokenScopeReadOnly, TokenScopeDeployOnly:
+		return nil
+	}
+	return &tsuruErrors.ValidationError{
+		Message: fmt.Sprintf("invalid token scope %q, must be one of: %s, %s", s.Kind, TokenScopeReadOnly, TokenScopeDeployOnly),
+	}
+}
+
+// resolveApps stores the identity of the scope apps, failing if any of them
+// doesn't exist.
+func (s *TokenScope) resolveApps() error {
+	s.AppIDs = nil
+	if len(s.Apps) == 0 {
+		return nil
+	}
+	conn, err := db.Conn()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	var apps []struct {
+		ID   bson.ObjectId `bson:"_id"`
+		Name string
+	}
+	err = conn.Apps().Find(bson.M{"name": bson.M{"$in": s.Apps}}).Select(bson.M{"_id": 1, "name": 1}).All(&apps)
+	if err != nil {
+		return err
+	}
+	found := make(map[string]bool, len(apps))
+	for _, a := range apps {
+		found[a.Name] = true
+		s.AppIDs = append(s.AppIDs, a.ID)
+	}
+	for _, name := range s.Apps {
+		if !found[name] {
+			return &tsuruErrors.ValidationError{Message: fmt.Sprintf("app %q not found", name)}
+		}
+	}
+	return nil
+}
+
+func (s *TokenScope) allows(scheme *permission.PermissionScheme) bool {
+	switch s.Kind {
+	case TokenScopeReadOnly:
+		return strings.Contains("."+scheme.FullName()+".", ".read.")
+	case TokenScopeDeployOnly:
+		for _, allowed := range []*permission.PermissionScheme{permission.PermAppBuild, permission.PermAppDeploy, permission.PermAppRead} {
+			if allowed.IsParent(scheme) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// Filter removes from perms everything not allowed by the scope. Permissions
+// whose scheme is broader than the scope are narrowed down to the allowed
+// schemes below it and contexts broader than the scope apps are narrowed
+// down to the apps they include.
+func (s *TokenScope) Filter(perms []permission.Permission) ([]permission.Permission, error) {
+	if s.IsEmpty() {
+		return perms, nil
+	}
+	if s.Kind != "" {
+		perms = s.filterSchemes(perms)
+	}
+	if len(s.Apps) > 0 {
+		return s.filterApps(perms)
+	}
+	return perms, nil
+}
+
+func (s *TokenScope) filterSchemes(perms []permission.Permission) []permission.Permission {
+	var result []permission.Permission
+	for _, perm := range perms {
+		for _, scheme := range narrowScheme(perm.Scheme, s.allows) {
+			result = append(result, permission.Permission{Scheme: scheme, Context: perm.Context})
+		}
+	}
+	return result
+}
+
+func (s *TokenScope) filterApps(perms []permission.Permission) ([]permission.Permission, error) {
+	conn, err := db.Conn()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	var apps []struct {
+		Name  string
+		Pool  string
+		Teams []string
+	}
+	err = conn.Apps().Find(bson.M{"_id": bson.M{"$in": s.AppIDs}}).Select(bson.M{"name": 1, "pool": 1, "teams": 1}).All(&apps)
+	if err != nil {
+		return nil, err
+	}
+	var result []permission.Permission
+	for _, perm := range perms {
+		schemes := narrowScheme(perm.Scheme, allowsAppContext)
+		for _, a := range apps {
+			var included bool
+			switch perm.Context.CtxType {
+			case permission.CtxGlobal:
+				included = true
+			case permission.CtxApp:
+				included = perm.Context.Value == a.Name
+			case permission.CtxPool:
+				included = perm.Context.Value == a.Pool
+			case permission.CtxTeam:
+				for _, team := range a.Teams {
+					if perm.Context.Value == team {
+						included = true
+						break
+					}
+				}
+			}
+			if !included {
+				continue
+			}
+			for _, scheme := range schemes {
+				result = append(result, permission.Permission{
+					Scheme:  scheme,
+					Context: permission.Context(permission.CtxApp, a.Name),
+				})
+			}
+		}
+	}
+	return result, nil
+}
+
+func allowsAppContext(scheme *permission.PermissionScheme) bool {
+	for _, ctxType := range scheme.AllowedContexts() {
+		if ctxType == permission.CtxApp {
+			return true
+		}
+	}
+	return false
+}
+
+// narrowScheme returns the scheme itself when it's allowed, otherwise the
+// topmost allowed schemes below it.
+func narrowScheme(scheme *permission.PermissionScheme, allowed func(*permission.PermissionScheme) bool) []*permission.PermissionScheme {
+	if allowed(scheme) {
+		return []*permission.PermissionScheme{scheme}
+	}
+	var result []*permission.PermissionScheme
+schemesLoop:
+	for _, child := range permission.PermissionRegistry.Permissions() {
+		if !scheme.IsParent(child) || !allowed(child) {
+			continue
+		}
+		for _, parent := range result {
+			if parent.IsParent(child) {
+				continue schemesLoop
+			}
+		}
+		result = append(result, child)
+	}
+	return result
+}
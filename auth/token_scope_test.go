@@ -0,0 +1,119 @@
+// Copyright 2018 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package auth
+
+import (
+	"github.com/globalsign/mgo/bson"
+	"github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/permission"
+	"gopkg.in/check.v1"
+)
+
+func (s *S) TestTokenScopeValidate(c *check.C) {
+	for _, kind := range []string{"", TokenScopeReadOnly, TokenScopeDeployOnly} {
+		scope := TokenScope{Kind: kind}
+		c.Check(scope.Validate(), check.IsNil)
+	}
+	scope := TokenScope{Kind: "admin-only"}
+	c.Assert(scope.Validate(), check.FitsTypeOf, &errors.ValidationError{})
+}
+
+func (s *S) TestTokenScopeFilterEmpty(c *check.C) {
+	perms := []permission.Permission{
+		{Scheme: permission.PermAll, Context: permission.Context(permission.CtxGlobal, "")},
+	}
+	scope := TokenScope{}
+	filtered, err := scope.Filter(perms)
+	c.Assert(err, check.IsNil)
+	c.Assert(filtered, check.DeepEquals, perms)
+}
+
+func (s *S) TestTokenScopeFilterReadOnly(c *check.C) {
+	scope := TokenScope{Kind: TokenScopeReadOnly}
+	filtered, err := scope.Filter([]permission.Permission{
+		{Scheme: permission.PermAll, Context: permission.Context(permission.CtxGlobal, "")},
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(filtered, check.Not(check.HasLen), 0)
+	ctx := permission.Context(permission.CtxApp, "myapp")
+	c.Assert(permission.CheckFromPermList(filtered, permission.PermAppRead, ctx), check.Equals, true)
+	c.Assert(permission.CheckFromPermList(filtered, permission.PermAppReadEnv, ctx), check.Equals, true)
+	c.Assert(permission.CheckFromPermList(filtered, permission.PermTeamRead), check.Equals, true)
+	c.Assert(permission.CheckFromPermList(filtered, permission.PermAppDeploy, ctx), check.Equals, false)
+	c.Assert(permission.CheckFromPermList(filtered, permission.PermAppUpdateEnvSet, ctx), check.Equals, false)
+	c.Assert(permission.CheckFromPermList(filtered, permission.PermTeamCreate), check.Equals, false)
+}
+
+func (s *S) TestTokenScopeFilterDeployOnly(c *check.C) {
+	scope := TokenScope{Kind: TokenScopeDeployOnly}
+	filtered, err := scope.Filter([]permission.Permission{
+		{Scheme: permission.PermApp, Context: permission.Context(permission.CtxTeam, "team1")},
+		{Scheme: permission.PermTeam, Context: permission.Context(permission.CtxTeam, "team1")},
+	})
+	c.Assert(err, check.IsNil)
+	ctx := permission.Context(permission.CtxTeam, "team1")
+	c.Assert(permission.CheckFromPermList(filtered, permission.PermAppDeploy, ctx), check.Equals, true)
+	c.Assert(permission.CheckFromPermList(filtered, permission.PermAppBuild, ctx), check.Equals, true)
+	c.Assert(permission.CheckFromPermList(filtered, permission.PermAppRead, ctx), check.Equals, true)
+	c.Assert(permission.CheckFromPermList(filtered, permission.PermAppDelete, ctx), check.Equals, false)
+	c.Assert(permission.CheckFromPermList(filtered, permission.PermAppUpdateEnvSet, ctx), check.Equals, false)
+	c.Assert(permission.CheckFromPermList(filtered, permission.PermTeamUpdate, ctx), check.Equals, false)
+}
+
+func (s *S) TestTokenScopeFilterApps(c *check.C) {
+	err := s.conn.Apps().Insert(
+		bson.M{"name": "myapp", "pool": "pool1", "teams": []string{"team1"}},
+		bson.M{"name": "otherapp", "pool": "pool2", "teams": []string{"team2"}},
+	)
+	c.Assert(err, check.IsNil)
+	perms := []permission.Permission{
+		{Scheme: permission.PermAll, Context: permission.Context(permission.CtxGlobal, "")},
+	}
+	scope := TokenScope{Apps: []string{"myapp"}}
+	err = scope.resolveApps()
+	c.Assert(err, check.IsNil)
+	filtered, err := scope.Filter(perms)
+	c.Assert(err, check.IsNil)
+	myapp := permission.Context(permission.CtxApp, "myapp")
+	otherapp := permission.Context(permission.CtxApp, "otherapp")
+	c.Assert(permission.CheckFromPermList(filtered, permission.PermAppDeploy, myapp), check.Equals, true)
+	c.Assert(permission.CheckFromPermList(filtered, permission.PermAppDeploy, otherapp), check.Equals, false)
+	c.Assert(permission.CheckFromPermList(filtered, permission.PermAppCreate, permission.Context(permission.CtxTeam, "team1")), check.Equals, false)
+	c.Assert(permission.CheckFromPermList(filtered, permission.PermTeamCreate), check.Equals, false)
+	scope = TokenScope{Kind: TokenScopeDeployOnly, Apps: []string{"myapp", "otherapp"}}
+	err = scope.resolveApps()
+	c.Assert(err, check.IsNil)
+	filtered, err = scope.Filter([]permission.Permission{
+		{Scheme: permission.PermApp, Context: permission.Context(permission.CtxPool, "pool1")},
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(permission.CheckFromPermList(filtered, permission.PermAppDeploy, myapp), check.Equals, true)
+	c.Assert(permission.CheckFromPermList(filtered, permission.PermAppDeploy, otherapp), check.Equals, false)
+	c.Assert(permission.CheckFromPermList(filtered, permission.PermAppDelete, myapp), check.Equals, false)
+}
+
+func (s *S) TestTokenScopeFilterAppsRecreated(c *check.C) {
+	err := s.conn.Apps().Insert(bson.M{"name": "myapp", "pool": "pool1", "teams": []string{"team1"}})
+	c.Assert(err, check.IsNil)
+	scope := TokenScope{Apps: []string{"myapp"}}
+	err = scope.resolveApps()
+	c.Assert(err, check.IsNil)
+	c.Assert(scope.AppIDs, check.HasLen, 1)
+	err = s.conn.Apps().Remove(bson.M{"name": "myapp"})
+	c.Assert(err, check.IsNil)
+	err = s.conn.Apps().Insert(bson.M{"name": "myapp", "pool": "pool1", "teams": []string{"team1"}})
+	c.Assert(err, check.IsNil)
+	filtered, err := scope.Filter([]permission.Permission{
+		{Scheme: permission.PermAll, Context: permission.Context(permission.CtxGlobal, "")},
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(filtered, check.HasLen, 0)
+}
+
+func (s *S) TestTokenScopeResolveAppsNotFound(c *check.C) {
+	scope := TokenScope{Apps: []string{"unknown"}}
+	err := scope.resolveApps()
+	c.Assert(err, check.FitsTypeOf, &errors.ValidationError{})
+}
@@ -36,10 +36,11 @@ type RoleInstance struct {
 
 type User struct {
 	quota.Quota
-	Email    string
-	Password string
-	APIKey   string
-	Roles    []RoleInstance `bson:",omitempty"`
+	Email       string
+	Password    string
+	APIKey      string
+	APIKeyScope TokenScope     `bson:",omitempty"`
+	Roles       []RoleInstance `bson:",omitempty"`
 }
 
 func listUsers(filter bson.M) ([]User, error) {
@@ -209,6 +210,19 @@ func (u *User) ShowAPIKey() (string, error) {
 }
 
 func (u *User) RegenerateAPIKey() (string, error) {
+	return u.RegenerateScopedAPIKey(TokenScope{})
+}
+
+// RegenerateScopedAPIKey generates a new API key for the user, limiting the
+// permissions granted by it to the given scope. Each user has a single API
+// key, so the scope replaces the one of the previous key.
+func (u *User) RegenerateScopedAPIKey(scope TokenScope) (string, error) {
+	if err := scope.Validate(); err != nil {
+		return "", err
+	}
+	if err := scope.resolveApps(); err != nil {
+		return "", err
+	}
 	random_byte := make([]byte, 32)
 	_, err := rand.Read(random_byte)
 	if err != nil {
@@ -219,6 +233,7 @@ func (u *User) RegenerateAPIKey() (string, error) {
 	h.Write(random_byte)
 	h.Write([]byte(time.Now().Format(time.RFC3339Nano)))
 	u.APIKey = fmt.Sprintf("%x", h.Sum(nil))
+	u.APIKeyScope = scope
 	return u.APIKey, u.Update()
 }
 
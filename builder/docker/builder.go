@@ -207,10 +207,17 @@ func tsuruYamlToCustomData(yaml *provision.TsuruYamlData) map[string]interface{}
 		return nil
 	}
 
-	return map[string]interface{}{
+	customData := map[string]interface{}{
 		"healthcheck": yaml.Healthcheck,
 		"hooks":       yaml.Hooks,
 	}
+	if yaml.Dependencies != (provision.TsuruYamlDependencies{}) {
+		customData["dependencies"] = yaml.Dependencies
+	}
+	if yaml.RestartPolicy != (provision.TsuruYamlRestartPolicy{}) {
+		customData["restart_policy"] = yaml.RestartPolicy
+	}
+	return customData
 }
 
 func runBuildHooks(client provision.BuilderDockerClient, app provision.App, imageID string, evt *event.Event, tsuruYamlData *provision.TsuruYamlData) (string, error) {
@@ -221,6 +221,20 @@ func (s *Storage) Events() *storage.Collection {
 	return c
 }
 
+func (s *Storage) Audit() *storage.Collection {
+	c := s.Collection("audit")
+	c.EnsureIndex(mgo.Index{Key: []string{"-time"}})
+	c.EnsureIndex(mgo.Index{Key: []string{"user", "-time"}})
+	c.EnsureIndex(mgo.Index{Key: []string{"app", "-time"}})
+	return c
+}
+
+func (s *Storage) Webhooks() *storage.Collection {
+	c := s.Collection("webhooks")
+	c.EnsureIndex(mgo.Index{Key: []string{"teamowner"}})
+	return c
+}
+
 func (s *Storage) EventBlocks() *storage.Collection {
 	index := mgo.Index{Key: []string{"ownername", "kindname", "target"}}
 	startTimeIndex := mgo.Index{Key: []string{"-starttime"}}
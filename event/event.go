@@ -100,6 +100,8 @@ var (
 	TargetTypeEventBlock      = TargetType("event-block")
 	TargetTypeCluster         = TargetType("cluster")
 	TargetTypeVolume          = TargetType("volume")
+	TargetTypeQueueJob        = TargetType("queue-job")
+	TargetTypeWebhook         = TargetType("webhook")
 )
 
 const (
@@ -340,6 +342,7 @@ type Event struct {
 	eventData
 	logBuffer *safe.Buffer
 	logWriter io.Writer
+	redactor  io.Writer
 }
 
 type ExtraTarget struct {
@@ -995,6 +998,23 @@ func (e *Event) GetLogWriter() io.Writer {
 	return e.logWriter
 }
 
+// SetLogRedactor makes everything written to the event go through the writer
+// returned by redact before reaching both the log writer and the log stored
+// with the event, so sensitive data can be hidden from all of them.
+func (e *Event) SetLogRedactor(redact func(io.Writer) io.Writer) {
+	e.FlushLog()
+	e.redactor = redact(eventLog{evt: e})
+}
+
+// FlushLog writes data held by the log redactor, if any.
+func (e *Event) FlushLog() {
+	if f, ok := e.redactor.(interface {
+		Flush() error
+	}); ok {
+		f.Flush()
+	}
+}
+
 func (e *Event) SetOtherCustomData(data interface{}) error {
 	conn, err := db.Conn()
 	if err != nil {
@@ -1010,20 +1030,28 @@ func (e *Event) SetOtherCustomData(data interface{}) error {
 func (e *Event) Logf(format string, params ...interface{}) {
 	log.Debugf(fmt.Sprintf("%s(%s)[%s] %s", e.Target.Type, e.Target.Value, e.Kind, format), params...)
 	format += "\n"
-	if e.logWriter != nil {
-		fmt.Fprintf(e.logWriter, format, params...)
-	}
-	if e.logBuffer != nil {
-		fmt.Fprintf(e.logBuffer, format, params...)
-	}
+	e.Write([]byte(fmt.Sprintf(format, params...)))
 }
 
 func (e *Event) Write(data []byte) (int, error) {
-	if e.logWriter != nil {
-		e.logWriter.Write(data)
+	if e.redactor != nil {
+		e.redactor.Write(data)
+		return len(data), nil
 	}
-	if e.logBuffer != nil {
-		e.logBuffer.Write(data)
+	return eventLog{evt: e}.Write(data)
+}
+
+// eventLog writes to the log writer and to the log stored with the event.
+type eventLog struct {
+	evt *Event
+}
+
+func (l eventLog) Write(data []byte) (int, error) {
+	if l.evt.logWriter != nil {
+		l.evt.logWriter.Write(data)
+	}
+	if l.evt.logBuffer != nil {
+		l.evt.logBuffer.Write(data)
 	}
 	return len(data), nil
 }
@@ -1161,6 +1189,7 @@ func (e *Event) done(evtErr error, customData interface{}, abort bool) (err erro
 		return err
 	}
 	e.Running = false
+	e.FlushLog()
 	if e.logBuffer != nil {
 		e.Log = e.logBuffer.String()
 	}
@@ -1169,6 +1198,11 @@ func (e *Event) done(evtErr error, customData interface{}, abort bool) (err erro
 	if err == nil {
 		e.OtherCustomData = dbEvt.OtherCustomData
 	}
+	defer func() {
+		if err == nil {
+			webhooks.notify(e)
+		}
+	}()
 	if len(e.ID.ObjId) != 0 {
 		return coll.UpdateId(e.ID, e.eventData)
 	}
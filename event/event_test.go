@@ -473,6 +473,47 @@ func (s *S) TestEventLogfWithWriter(c *check.C) {
 	c.Assert(evts[0].Log, check.Equals, "hey 42\n")
 }
 
+type holdWriter struct {
+	w    io.Writer
+	held []byte
+}
+
+func (w *holdWriter) Write(data []byte) (int, error) {
+	w.held = append(w.held, bytes.ToUpper(data)...)
+	return len(data), nil
+}
+
+func (w *holdWriter) Flush() error {
+	_, err := w.w.Write(w.held)
+	w.held = nil
+	return err
+}
+
+func (s *S) TestEventLogRedactor(c *check.C) {
+	evt, err := New(&Opts{
+		Target:  Target{Type: "app", Value: "myapp"},
+		Kind:    permission.PermAppUpdateEnvSet,
+		Owner:   s.token,
+		Allowed: Allowed(permission.PermAppReadEvents),
+	})
+	c.Assert(err, check.IsNil)
+	buf := bytes.Buffer{}
+	evt.SetLogWriter(&buf)
+	evt.SetLogRedactor(func(w io.Writer) io.Writer {
+		return &holdWriter{w: w}
+	})
+	evt.Logf("%s %d", "hey", 42)
+	evt.Write([]byte("ho"))
+	c.Assert(buf.String(), check.Equals, "")
+	err = evt.Done(nil)
+	c.Assert(err, check.IsNil)
+	c.Assert(buf.String(), check.Equals, "HEY 42\nHO")
+	evts, err := All()
+	c.Assert(err, check.IsNil)
+	c.Assert(evts, check.HasLen, 1)
+	c.Assert(evts[0].Log, check.Equals, "HEY 42\nHO")
+}
+
 func (s *S) TestEventCancel(c *check.C) {
 	evt, err := New(&Opts{
 		Target:        Target{Type: "app", Value: "myapp"},
@@ -0,0 +1,427 @@
+// Copyright 2018 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package event
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+	"github.com/pkg/errors"
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/db"
+	tsuruErrors "github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/log"
+	"github.com/tsuru/tsuru/permission"
+)
+
+const webhookSignatureHeader = "X-Tsuru-Signature"
+
+var (
+	ErrWebhookAlreadyExists = errors.New("webhook already exists")
+	ErrWebhookNotFound      = errors.New("webhook not found")
+
+	webhooks = &webhookNotifier{}
+
+	// defaultWebhookDeniedNetworks prevents webhooks from reaching services
+	// only reachable from the tsuru api host, like the loopback interface and
+	// cloud metadata endpoints.
+	defaultWebhookDeniedNetworks = []string{
+		"127.0.0.0/8",
+		"169.254.0.0/16",
+		"0.0.0.0/8",
+		"::1/128",
+		"fe80::/10",
+		"::/128",
+	}
+
+	webhookTeamPools func(team string) ([]string, error)
+
+	webhookClient = &http.Client{
+		Transport: &http.Transport{
+			DialContext:         webhookDialContext,
+			TLSHandshakeTimeout: 5 * time.Second,
+			MaxIdleConnsPerHost: -1,
+		},
+		Timeout: time.Minute,
+	}
+)
+
+// SetWebhookTeamPools sets the function used to find the pools of a team,
+// webhooks are notified about events allowed in the pools of their team owner.
+func SetWebhookTeamPools(fn func(team string) ([]string, error)) {
+	webhookTeamPools = fn
+}
+
+// Webhook is an URL notified about every finished event visible to its team
+// and matching its filter. Events are visible to a team when they are allowed
+// in the team context, in the context of one of the pools used by the team or,
+// for teams listed in events:webhooks:global-teams, in the global context.
+type Webhook struct {
+	Name      string `bson:"_id"`
+	TeamOwner string
+	URL       string
+	Secret    string `json:"-"`
+	Filter    WebhookFilter
+}
+
+// WebhookFilter restricts the events sent to a webhook. KindNames are matched
+// as prefixes of the event kind and Apps against app targets, empty values
+// match any event.
+type WebhookFilter struct {
+	KindNames   []string `bson:",omitempty"`
+	Apps        []string `bson:",omitempty"`
+	ErrorOnly   bool
+	SuccessOnly bool
+}
+
+type webhookPayload struct {
+	ID          string    `json:"id"`
+	Kind        string    `json:"kind"`
+	TargetType  string    `json:"targetType"`
+	TargetValue string    `json:"targetValue"`
+	Owner       string    `json:"owner"`
+	StartTime   time.Time `json:"startTime"`
+	EndTime     time.Time `json:"endTime"`
+	Success     bool      `json:"success"`
+	Error       string    `json:"error,omitempty"`
+}
+
+func (w *Webhook) validate() error {
+	if w.Name == "" {
+		return &tsuruErrors.ValidationError{Message: "webhook name is required"}
+	}
+	if w.TeamOwner == "" {
+		return &tsuruErrors.ValidationError{Message: "webhook team owner is required"}
+	}
+	u, err := url.Parse(w.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return &tsuruErrors.ValidationError{Message: fmt.Sprintf("invalid webhook url %q", w.URL)}
+	}
+	if err = checkWebhookHost(u.Hostname()); err != nil {
+		return &tsuruErrors.ValidationError{Message: fmt.Sprintf("invalid webhook url %q: %v", w.URL, err)}
+	}
+	if w.Filter.ErrorOnly && w.Filter.SuccessOnly {
+		return &tsuruErrors.ValidationError{Message: "webhook filter cannot be both error only and success only"}
+	}
+	return nil
+}
+
+// Matches returns whether the event should be sent to the webhook.
+func (w *Webhook) Matches(e *Event) bool {
+	if !isAllowedForTeam(e, w.TeamOwner) {
+		return false
+	}
+	if w.Filter.ErrorOnly && e.Error == "" || w.Filter.SuccessOnly && e.Error != "" {
+		return false
+	}
+	if len(w.Filter.KindNames) > 0 {
+		var found bool
+		for _, kind := range w.Filter.KindNames {
+			if strings.HasPrefix(e.Kind.Name, kind) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(w.Filter.Apps) > 0 {
+		if e.Target.Type != TargetTypeApp {
+			return false
+		}
+		for _, app := range w.Filter.Apps {
+			if app == e.Target.Value {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func isAllowedForTeam(e *Event, team string) bool {
+	if len(e.Allowed.Contexts) == 0 {
+		return isGlobalWebhookTeam(team)
+	}
+	var hasPools bool
+	for _, ctx := range e.Allowed.Contexts {
+		switch ctx.CtxType {
+		case permission.CtxGlobal:
+			if isGlobalWebhookTeam(team) {
+				return true
+			}
+		case permission.CtxTeam:
+			if ctx.Value == team {
+				return true
+			}
+		case permission.CtxPool:
+			hasPools = true
+		}
+	}
+	if !hasPools || webhookTeamPools == nil {
+		return false
+	}
+	pools, err := webhookTeamPools(team)
+	if err != nil {
+		log.Errorf("[events] [webhooks] unable to list pools for team %q: %v", team, err)
+		return false
+	}
+	for _, ctx := range e.Allowed.Contexts {
+		if ctx.CtxType != permission.CtxPool {
+			continue
+		}
+		for _, pool := range pools {
+			if ctx.Value == pool {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isGlobalWebhookTeam(team string) bool {
+	teams, _ := config.GetList("events:webhooks:global-teams")
+	for _, t := range teams {
+		if t == team {
+			return true
+		}
+	}
+	return false
+}
+
+func webhookDeniedNetworks() ([]*net.IPNet, error) {
+	cidrs, err := config.GetList("events:webhooks:denied-networks")
+	if err != nil {
+		cidrs = defaultWebhookDeniedNetworks
+	}
+	networks := make([]*net.IPNet, len(cidrs))
+	for i, cidr := range cidrs {
+		_, networks[i], err = net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid network in events:webhooks:denied-networks")
+		}
+	}
+	return networks, nil
+}
+
+func checkWebhookIP(ip net.IP) error {
+	networks, err := webhookDeniedNetworks()
+	if err != nil {
+		return err
+	}
+	for _, network := range networks {
+		if network.Contains(ip) {
+			return errors.Errorf("address %s is not allowed", ip)
+		}
+	}
+	return nil
+}
+
+// checkWebhookHost validates the host when it is an address or resolves to
+// addresses. Names that cannot be resolved are accepted, as every connection
+// is checked again by webhookDialContext.
+func checkWebhookHost(host string) error {
+	if ip := net.ParseIP(host); ip != nil {
+		return checkWebhookIP(ip)
+	}
+	addrs, err := net.LookupIP(host)
+	if err != nil {
+		return nil
+	}
+	for _, ip := range addrs {
+		if err = checkWebhookIP(ip); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// webhookDialContext resolves the address before connecting, refusing denied
+// addresses even if the webhook host changes where it resolves to.
+func webhookDialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	if len(addrs) == 0 {
+		return nil, errors.Errorf("no addresses found for %q", host)
+	}
+	for _, a := range addrs {
+		if err = checkWebhookIP(a.IP); err != nil {
+			return nil, err
+		}
+	}
+	dialer := net.Dialer{Timeout: 5 * time.Second}
+	return dialer.DialContext(ctx, network, net.JoinHostPort(addrs[0].IP.String(), port))
+}
+
+func AddWebhook(w *Webhook) error {
+	if err := w.validate(); err != nil {
+		return err
+	}
+	conn, err := db.Conn()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	err = conn.Webhooks().Insert(w)
+	if mgo.IsDup(err) {
+		return ErrWebhookAlreadyExists
+	}
+	return err
+}
+
+func GetWebhook(name string) (*Webhook, error) {
+	conn, err := db.Conn()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	var w Webhook
+	err = conn.Webhooks().FindId(name).One(&w)
+	if err == mgo.ErrNotFound {
+		return nil, ErrWebhookNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+func RemoveWebhook(name string) error {
+	conn, err := db.Conn()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	err = conn.Webhooks().RemoveId(name)
+	if err == mgo.ErrNotFound {
+		return ErrWebhookNotFound
+	}
+	return err
+}
+
+// ListWebhooks returns the webhooks owned by the given teams, a nil list of
+// teams returns all webhooks.
+func ListWebhooks(teams []string) ([]Webhook, error) {
+	query := bson.M{}
+	if teams != nil {
+		query["teamowner"] = bson.M{"$in": teams}
+	}
+	conn, err := db.Conn()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	var result []Webhook
+	err = conn.Webhooks().Find(query).Sort("_id").All(&result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+type webhookNotifier struct {
+	running sync.WaitGroup
+}
+
+// notify sends the finished event to the matching webhooks in background.
+func (n *webhookNotifier) notify(e *Event) {
+	if e.Target.Type == TargetTypeWebhook {
+		return
+	}
+	evt := *e
+	n.running.Add(1)
+	go func() {
+		defer n.running.Done()
+		err := n.send(&evt)
+		if err != nil {
+			log.Errorf("[events] [webhooks] error notifying event %s: %v", evt.UniqueID.Hex(), err)
+		}
+	}()
+}
+
+func (n *webhookNotifier) wait() {
+	n.running.Wait()
+}
+
+func (n *webhookNotifier) send(e *Event) error {
+	hooks, err := ListWebhooks(nil)
+	if err != nil {
+		return err
+	}
+	var body []byte
+	for i := range hooks {
+		hook := &hooks[i]
+		if !hook.Matches(e) {
+			continue
+		}
+		if body == nil {
+			body, err = json.Marshal(webhookPayload{
+				ID:          e.UniqueID.Hex(),
+				Kind:        e.Kind.Name,
+				TargetType:  string(e.Target.Type),
+				TargetValue: e.Target.Value,
+				Owner:       e.Owner.Name,
+				StartTime:   e.StartTime,
+				EndTime:     e.EndTime,
+				Success:     e.Error == "",
+				Error:       e.Error,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		err = hook.post(body)
+		if err != nil {
+			log.Errorf("[events] [webhooks] error sending event %s to webhook %q: %v", e.UniqueID.Hex(), hook.Name, err)
+		}
+	}
+	return nil
+}
+
+func (w *Webhook) post(body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if w.Secret != "" {
+		req.Header.Set(webhookSignatureHeader, "sha256="+signWebhookPayload(w.Secret, body))
+	}
+	rsp, err := webhookClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer rsp.Body.Close()
+	if rsp.StatusCode < 200 || rsp.StatusCode >= 300 {
+		return errors.Errorf("invalid status code %d", rsp.StatusCode)
+	}
+	return nil
+}
+
+func signWebhookPayload(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,199 @@
+// Copyright 2018 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package event
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/permission"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) TestAddWebhook(c *check.C) {
+	w := Webhook{Name: "hook1", TeamOwner: "team1", URL: "http://example.com/hook"}
+	err := AddWebhook(&w)
+	c.Assert(err, check.IsNil)
+	dbHook, err := GetWebhook("hook1")
+	c.Assert(err, check.IsNil)
+	c.Assert(*dbHook, check.DeepEquals, w)
+	err = AddWebhook(&w)
+	c.Assert(err, check.Equals, ErrWebhookAlreadyExists)
+}
+
+func (s *S) TestAddWebhookInvalid(c *check.C) {
+	tests := []Webhook{
+		{TeamOwner: "team1", URL: "http://example.com"},
+		{Name: "hook1", URL: "http://example.com"},
+		{Name: "hook1", TeamOwner: "team1", URL: "example.com"},
+		{Name: "hook1", TeamOwner: "team1", URL: "http://example.com", Filter: WebhookFilter{ErrorOnly: true, SuccessOnly: true}},
+		{Name: "hook1", TeamOwner: "team1", URL: "http://127.0.0.1:8080/hook"},
+		{Name: "hook1", TeamOwner: "team1", URL: "http://169.254.169.254/latest/meta-data"},
+		{Name: "hook1", TeamOwner: "team1", URL: "http://[::1]/hook"},
+	}
+	for _, w := range tests {
+		err := AddWebhook(&w)
+		c.Check(err, check.FitsTypeOf, &errors.ValidationError{})
+	}
+}
+
+func (s *S) TestAddWebhookDeniedNetworks(c *check.C) {
+	config.Set("events:webhooks:denied-networks", []string{"10.0.0.0/8"})
+	defer config.Unset("events:webhooks:denied-networks")
+	err := AddWebhook(&Webhook{Name: "hook1", TeamOwner: "team1", URL: "http://127.0.0.1:8080/hook"})
+	c.Assert(err, check.IsNil)
+	err = AddWebhook(&Webhook{Name: "hook2", TeamOwner: "team1", URL: "http://10.1.1.1/hook"})
+	c.Assert(err, check.FitsTypeOf, &errors.ValidationError{})
+}
+
+func (s *S) TestRemoveWebhook(c *check.C) {
+	err := AddWebhook(&Webhook{Name: "hook1", TeamOwner: "team1", URL: "http://example.com/hook"})
+	c.Assert(err, check.IsNil)
+	err = RemoveWebhook("hook1")
+	c.Assert(err, check.IsNil)
+	_, err = GetWebhook("hook1")
+	c.Assert(err, check.Equals, ErrWebhookNotFound)
+	err = RemoveWebhook("hook1")
+	c.Assert(err, check.Equals, ErrWebhookNotFound)
+}
+
+func (s *S) TestListWebhooks(c *check.C) {
+	err := AddWebhook(&Webhook{Name: "hook2", TeamOwner: "team2", URL: "http://example.com/hook"})
+	c.Assert(err, check.IsNil)
+	err = AddWebhook(&Webhook{Name: "hook1", TeamOwner: "team1", URL: "http://example.com/hook"})
+	c.Assert(err, check.IsNil)
+	hooks, err := ListWebhooks(nil)
+	c.Assert(err, check.IsNil)
+	c.Assert(hooks, check.HasLen, 2)
+	c.Assert(hooks[0].Name, check.Equals, "hook1")
+	c.Assert(hooks[1].Name, check.Equals, "hook2")
+	hooks, err = ListWebhooks([]string{"team2"})
+	c.Assert(err, check.IsNil)
+	c.Assert(hooks, check.HasLen, 1)
+	c.Assert(hooks[0].Name, check.Equals, "hook2")
+}
+
+func (s *S) TestWebhookMatches(c *check.C) {
+	evt := &Event{eventData: eventData{
+		Target:  Target{Type: TargetTypeApp, Value: "myapp"},
+		Kind:    Kind{Type: KindTypePermission, Name: "app.deploy"},
+		Allowed: Allowed(permission.PermAppReadEvents, permission.Context(permission.CtxTeam, "team1")),
+	}}
+	tests := []struct {
+		hook     Webhook
+		expected bool
+	}{
+		{Webhook{TeamOwner: "team1"}, true},
+		{Webhook{TeamOwner: "team2"}, false},
+		{Webhook{TeamOwner: "team1", Filter: WebhookFilter{KindNames: []string{"app.update", "app"}}}, true},
+		{Webhook{TeamOwner: "team1", Filter: WebhookFilter{KindNames: []string{"app.update"}}}, false},
+		{Webhook{TeamOwner: "team1", Filter: WebhookFilter{Apps: []string{"myapp"}}}, true},
+		{Webhook{TeamOwner: "team1", Filter: WebhookFilter{Apps: []string{"otherapp"}}}, false},
+		{Webhook{TeamOwner: "team1", Filter: WebhookFilter{SuccessOnly: true}}, true},
+		{Webhook{TeamOwner: "team1", Filter: WebhookFilter{ErrorOnly: true}}, false},
+	}
+	for i, tt := range tests {
+		c.Check(tt.hook.Matches(evt), check.Equals, tt.expected, check.Commentf("test %d", i))
+	}
+}
+
+func (s *S) TestWebhookMatchesPoolAndGlobalContexts(c *check.C) {
+	SetWebhookTeamPools(func(team string) ([]string, error) {
+		if team == "team1" {
+			return []string{"pool1"}, nil
+		}
+		return nil, nil
+	})
+	defer SetWebhookTeamPools(nil)
+	config.Set("events:webhooks:global-teams", []string{"admin"})
+	defer config.Unset("events:webhooks:global-teams")
+	poolEvt := &Event{eventData: eventData{
+		Target:  Target{Type: TargetTypeNode, Value: "http://10.0.0.1:2375"},
+		Kind:    Kind{Type: KindTypeInternal, Name: "healer"},
+		Allowed: Allowed(permission.PermPoolReadEvents, permission.Context(permission.CtxPool, "pool1")),
+	}}
+	globalEvt := &Event{eventData: eventData{
+		Target:  Target{Type: TargetTypeNode, Value: "http://10.0.0.1:2375"},
+		Kind:    Kind{Type: KindTypePermission, Name: "node.create"},
+		Allowed: Allowed(permission.PermPoolReadEvents),
+	}}
+	tests := []struct {
+		hook     Webhook
+		evt      *Event
+		expected bool
+	}{
+		{Webhook{TeamOwner: "team1"}, poolEvt, true},
+		{Webhook{TeamOwner: "team2"}, poolEvt, false},
+		{Webhook{TeamOwner: "admin"}, poolEvt, false},
+		{Webhook{TeamOwner: "team1"}, globalEvt, false},
+		{Webhook{TeamOwner: "admin"}, globalEvt, true},
+	}
+	for i, tt := range tests {
+		c.Check(tt.hook.Matches(tt.evt), check.Equals, tt.expected, check.Commentf("test %d", i))
+	}
+}
+
+func (s *S) TestWebhookPostDeniedNetwork(c *check.C) {
+	var called bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer srv.Close()
+	hook := Webhook{Name: "hook1", TeamOwner: "team1", URL: srv.URL}
+	err := hook.post([]byte("{}"))
+	c.Assert(err, check.ErrorMatches, `.*address 127\.0\.0\.1 is not allowed`)
+	c.Assert(called, check.Equals, false)
+}
+
+func (s *S) TestWebhookNotifiedOnDone(c *check.C) {
+	config.Set("events:webhooks:denied-networks", []string{})
+	defer config.Unset("events:webhooks:denied-networks")
+	var received []webhookPayload
+	var signatures []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		c.Assert(err, check.IsNil)
+		var payload webhookPayload
+		err = json.Unmarshal(body, &payload)
+		c.Assert(err, check.IsNil)
+		received = append(received, payload)
+		signatures = append(signatures, r.Header.Get("X-Tsuru-Signature"))
+		c.Assert(r.Header.Get("X-Tsuru-Signature"), check.Equals, "sha256="+signWebhookPayload("s3cr3t", body))
+	}))
+	defer srv.Close()
+	err := AddWebhook(&Webhook{Name: "hook1", TeamOwner: "team1", URL: srv.URL, Secret: "s3cr3t", Filter: WebhookFilter{KindNames: []string{"app.update"}}})
+	c.Assert(err, check.IsNil)
+	evt, err := New(&Opts{
+		Target:  Target{Type: TargetTypeApp, Value: "myapp"},
+		Kind:    permission.PermAppUpdateEnvSet,
+		Owner:   s.token,
+		Allowed: Allowed(permission.PermAppReadEvents, permission.Context(permission.CtxTeam, "team1")),
+	})
+	c.Assert(err, check.IsNil)
+	err = evt.Done(nil)
+	c.Assert(err, check.IsNil)
+	otherEvt, err := New(&Opts{
+		Target:  Target{Type: TargetTypeApp, Value: "myapp"},
+		Kind:    permission.PermAppDeploy,
+		Owner:   s.token,
+		Allowed: Allowed(permission.PermAppReadEvents, permission.Context(permission.CtxTeam, "team1")),
+	})
+	c.Assert(err, check.IsNil)
+	err = otherEvt.Done(nil)
+	c.Assert(err, check.IsNil)
+	webhooks.wait()
+	c.Assert(received, check.HasLen, 1)
+	c.Assert(signatures, check.HasLen, 1)
+	c.Assert(received[0].ID, check.Equals, evt.UniqueID.Hex())
+	c.Assert(received[0].Kind, check.Equals, "app.update.env.set")
+	c.Assert(received[0].TargetType, check.Equals, "app")
+	c.Assert(received[0].TargetValue, check.Equals, "myapp")
+	c.Assert(received[0].Owner, check.Equals, s.token.GetUserName())
+	c.Assert(received[0].Success, check.Equals, true)
+}
@@ -6,6 +6,7 @@ package io
 
 import (
 	"io"
+	"net/http"
 	"sync"
 	"time"
 
@@ -26,7 +27,15 @@ type keepAliveWriter struct {
 	testCh chan struct{}
 }
 
+// NewKeepAliveWriter returns a writer that writes msg to w whenever nothing
+// else is written for the given interval. When w is an http.ResponseWriter,
+// headers asking proxies not to buffer or cache the response are also set, so
+// that the streamed output reaches the client as it's written.
 func NewKeepAliveWriter(w io.Writer, interval time.Duration, msg string) *keepAliveWriter {
+	if rw, ok := w.(http.ResponseWriter); ok {
+		rw.Header().Set("Cache-Control", "no-cache")
+		rw.Header().Set("X-Accel-Buffering", "no")
+	}
 	writer := &keepAliveWriter{w: w, interval: interval, msg: append([]byte(msg), '\n')}
 	writer.ping = make(chan struct{})
 	writer.done = make(chan struct{})
@@ -7,6 +7,7 @@ package io
 import (
 	"bytes"
 	"errors"
+	"net/http/httptest"
 	"os"
 	"runtime/pprof"
 	"strings"
@@ -140,3 +141,14 @@ func (s *S) TestKeepAliveWriterRace(c *check.C) {
 	c.Assert(strings.Contains(buf.String(), "..."), check.Equals, true)
 	c.Assert(strings.Contains(buf.String(), "......"), check.Equals, false)
 }
+
+func (s *S) TestKeepAliveWriterDisablesProxyBuffering(c *check.C) {
+	recorder := httptest.NewRecorder()
+	w := NewKeepAliveWriter(recorder, time.Minute, "")
+	defer w.Stop()
+	_, err := w.Write([]byte("building...\n"))
+	c.Assert(err, check.IsNil)
+	c.Assert(recorder.Header().Get("Cache-Control"), check.Equals, "no-cache")
+	c.Assert(recorder.Header().Get("X-Accel-Buffering"), check.Equals, "no")
+	c.Assert(recorder.Body.String(), check.Equals, "building...\n")
+}
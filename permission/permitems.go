@@ -25,6 +25,7 @@ var (
 	PermAppDeployRollback                = PermissionRegistry.get("app.deploy.rollback")                 // [global app team pool]
 	PermAppDeployUpload                  = PermissionRegistry.get("app.deploy.upload")                   // [global app team pool]
 	PermAppRead                          = PermissionRegistry.get("app.read")                            // [global app team pool]
+	PermAppReadBuildEnv                  = PermissionRegistry.get("app.read.build-env")                  // [global app team pool]
 	PermAppReadCertificate               = PermissionRegistry.get("app.read.certificate")                // [global app team pool]
 	PermAppReadDeploy                    = PermissionRegistry.get("app.read.deploy")                     // [global app team pool]
 	PermAppReadEnv                       = PermissionRegistry.get("app.read.env")                        // [global app team pool]
@@ -35,8 +36,12 @@ var (
 	PermAppRun                           = PermissionRegistry.get("app.run")                             // [global app team pool]
 	PermAppRunShell                      = PermissionRegistry.get("app.run.shell")                       // [global app team pool]
 	PermAppUpdate                        = PermissionRegistry.get("app.update")                          // [global app team pool]
+	PermAppUpdateAnnotations             = PermissionRegistry.get("app.update.annotations")              // [global app team pool]
 	PermAppUpdateBind                    = PermissionRegistry.get("app.update.bind")                     // [global app team pool]
 	PermAppUpdateBindVolume              = PermissionRegistry.get("app.update.bind-volume")              // [global app team pool]
+	PermAppUpdateBuildEnv                = PermissionRegistry.get("app.update.build-env")                // [global app team pool]
+	PermAppUpdateBuildEnvSet             = PermissionRegistry.get("app.update.build-env.set")            // [global app team pool]
+	PermAppUpdateBuildEnvUnset           = PermissionRegistry.get("app.update.build-env.unset")          // [global app team pool]
 	PermAppUpdateCertificate             = PermissionRegistry.get("app.update.certificate")              // [global app team pool]
 	PermAppUpdateCertificateSet          = PermissionRegistry.get("app.update.certificate.set")          // [global app team pool]
 	PermAppUpdateCertificateUnset        = PermissionRegistry.get("app.update.certificate.unset")        // [global app team pool]
@@ -53,15 +58,18 @@ var (
 	PermAppUpdateGrant                   = PermissionRegistry.get("app.update.grant")                    // [global app team pool]
 	PermAppUpdateImageReset              = PermissionRegistry.get("app.update.image-reset")              // [global app team pool]
 	PermAppUpdateLog                     = PermissionRegistry.get("app.update.log")                      // [global app team pool]
+	PermAppUpdateMaintenance             = PermissionRegistry.get("app.update.maintenance")              // [global app team pool]
 	PermAppUpdatePlan                    = PermissionRegistry.get("app.update.plan")                     // [global app team pool]
 	PermAppUpdatePlatform                = PermissionRegistry.get("app.update.platform")                 // [global app team pool]
 	PermAppUpdatePool                    = PermissionRegistry.get("app.update.pool")                     // [global app team pool]
 	PermAppUpdateRestart                 = PermissionRegistry.get("app.update.restart")                  // [global app team pool]
+	PermAppUpdateRestartSchedule         = PermissionRegistry.get("app.update.restart-schedule")         // [global app team pool]
 	PermAppUpdateRevoke                  = PermissionRegistry.get("app.update.revoke")                   // [global app team pool]
 	PermAppUpdateRouter                  = PermissionRegistry.get("app.update.router")                   // [global app team pool]
 	PermAppUpdateRouterAdd               = PermissionRegistry.get("app.update.router.add")               // [global app team pool]
 	PermAppUpdateRouterRemove            = PermissionRegistry.get("app.update.router.remove")            // [global app team pool]
 	PermAppUpdateRouterUpdate            = PermissionRegistry.get("app.update.router.update")            // [global app team pool]
+	PermAppUpdateScaleSchedule           = PermissionRegistry.get("app.update.scale-schedule")           // [global app team pool]
 	PermAppUpdateSleep                   = PermissionRegistry.get("app.update.sleep")                    // [global app team pool]
 	PermAppUpdateStart                   = PermissionRegistry.get("app.update.start")                    // [global app team pool]
 	PermAppUpdateStop                    = PermissionRegistry.get("app.update.stop")                     // [global app team pool]
@@ -75,6 +83,8 @@ var (
 	PermAppUpdateUnitRegister            = PermissionRegistry.get("app.update.unit.register")            // [global app team pool]
 	PermAppUpdateUnitRemove              = PermissionRegistry.get("app.update.unit.remove")              // [global app team pool]
 	PermAppUpdateUnitStatus              = PermissionRegistry.get("app.update.unit.status")              // [global app team pool]
+	PermAudit                            = PermissionRegistry.get("audit")                               // [global]
+	PermAuditRead                        = PermissionRegistry.get("audit.read")                          // [global]
 	PermCluster                          = PermissionRegistry.get("cluster")                             // [global]
 	PermClusterCreate                    = PermissionRegistry.get("cluster.create")                      // [global]
 	PermClusterDelete                    = PermissionRegistry.get("cluster.delete")                      // [global]
@@ -91,6 +101,10 @@ var (
 	PermHealingDelete                    = PermissionRegistry.get("healing.delete")                      // [global pool]
 	PermHealingRead                      = PermissionRegistry.get("healing.read")                        // [global pool]
 	PermHealingUpdate                    = PermissionRegistry.get("healing.update")                      // [global pool]
+	PermImage                            = PermissionRegistry.get("image")                               // [global]
+	PermImageGc                          = PermissionRegistry.get("image.gc")                            // [global]
+	PermImageRead                        = PermissionRegistry.get("image.read")                          // [global]
+	PermImageReadEvents                  = PermissionRegistry.get("image.read.events")                   // [global]
 	PermInstall                          = PermissionRegistry.get("install")                             // [global]
 	PermInstallManage                    = PermissionRegistry.get("install.manage")                      // [global]
 	PermMachine                          = PermissionRegistry.get("machine")                             // [global iaas]
@@ -146,6 +160,12 @@ var (
 	PermPoolUpdateTeam                   = PermissionRegistry.get("pool.update.team")                    // [global pool]
 	PermPoolUpdateTeamAdd                = PermissionRegistry.get("pool.update.team.add")                // [global pool]
 	PermPoolUpdateTeamRemove             = PermissionRegistry.get("pool.update.team.remove")             // [global pool]
+	PermQueue                            = PermissionRegistry.get("queue")                               // [global]
+	PermQueueDelete                      = PermissionRegistry.get("queue.delete")                        // [global]
+	PermQueueRead                        = PermissionRegistry.get("queue.read")                          // [global]
+	PermQueueReadEvents                  = PermissionRegistry.get("queue.read.events")                   // [global]
+	PermQueueUpdate                      = PermissionRegistry.get("queue.update")                        // [global]
+	PermQueueUpdateRetry                 = PermissionRegistry.get("queue.update.retry")                  // [global]
 	PermRole                             = PermissionRegistry.get("role")                                // [global]
 	PermRoleCreate                       = PermissionRegistry.get("role.create")                         // [global]
 	PermRoleDefault                      = PermissionRegistry.get("role.default")                        // [global]
@@ -219,4 +239,9 @@ var (
 	PermVolumeUpdate                     = PermissionRegistry.get("volume.update")                       // [global volume team pool]
 	PermVolumeUpdateBind                 = PermissionRegistry.get("volume.update.bind")                  // [global volume team pool]
 	PermVolumeUpdateUnbind               = PermissionRegistry.get("volume.update.unbind")                // [global volume team pool]
+	PermWebhook                          = PermissionRegistry.get("webhook")                             // [global team]
+	PermWebhookCreate                    = PermissionRegistry.get("webhook.create")                      // [global team]
+	PermWebhookDelete                    = PermissionRegistry.get("webhook.delete")                      // [global team]
+	PermWebhookRead                      = PermissionRegistry.get("webhook.read")                        // [global team]
+	PermWebhookReadEvents                = PermissionRegistry.get("webhook.read.events")                 // [global team]
 )
@@ -13,6 +13,7 @@ var PermissionRegistry = (&registry{}).addWithCtx(
 ).add(
 	"app.update.description",
 	"app.update.tags",
+	"app.update.annotations",
 	"app.update.log",
 	"app.update.pool",
 	"app.update.unit.add",
@@ -21,6 +22,8 @@ var PermissionRegistry = (&registry{}).addWithCtx(
 	"app.update.unit.status",
 	"app.update.env.set",
 	"app.update.env.unset",
+	"app.update.build-env.set",
+	"app.update.build-env.unset",
 	"app.update.restart",
 	"app.update.sleep",
 	"app.update.start",
@@ -45,6 +48,9 @@ var PermissionRegistry = (&registry{}).addWithCtx(
 	"app.update.router.add",
 	"app.update.router.update",
 	"app.update.router.remove",
+	"app.update.restart-schedule",
+	"app.update.scale-schedule",
+	"app.update.maintenance",
 	"app.deploy",
 	"app.deploy.archive-url",
 	"app.deploy.build",
@@ -56,6 +62,7 @@ var PermissionRegistry = (&registry{}).addWithCtx(
 	"app.read.deploy",
 	"app.read.router",
 	"app.read.env",
+	"app.read.build-env",
 	"app.read.events",
 	"app.read.metric",
 	"app.read.log",
@@ -180,6 +187,9 @@ var PermissionRegistry = (&registry{}).addWithCtx(
 	"pool.delete",
 ).add(
 	"debug",
+).add(
+	"image.gc",
+	"image.read.events",
 ).add(
 	"healing.read",
 ).addWithCtx(
@@ -217,4 +227,18 @@ var PermissionRegistry = (&registry{}).addWithCtx(
 	"volume.update.bind",
 	"volume.update.unbind",
 	"volume.delete",
+).add(
+	"queue.read",
+	"queue.read.events",
+	"queue.update.retry",
+	"queue.delete",
+).addWithCtx(
+	"webhook", []contextType{CtxTeam},
+).add(
+	"webhook.read",
+	"webhook.read.events",
+	"webhook.create",
+	"webhook.delete",
+).add(
+	"audit.read",
 )
@@ -365,7 +365,8 @@ var bindAndHealthcheck = action.Action{
 		}
 		doHealthcheck := true
 		for _, c := range args.toRemove {
-			if c.Status == provision.StatusError.String() || c.Status == provision.StatusStopped.String() {
+			if c.Status == provision.StatusError.String() || c.Status == provision.StatusCrashLoop.String() ||
+				c.Status == provision.StatusStopped.String() {
 				doHealthcheck = false
 				break
 			}
@@ -466,6 +467,12 @@ var addNewRoutes = action.Action{
 		if writer == nil {
 			writer = ioutil.Discard
 		}
+		if provision.InMaintenance(args.app) {
+			if len(newContainers) > 0 {
+				fmt.Fprintf(writer, "\n---- Not adding routes to new units, app is in maintenance mode ----\n")
+			}
+			return newContainers, nil
+		}
 		if len(newContainers) > 0 {
 			fmt.Fprintf(writer, "\n---- Adding routes to new units ----\n")
 		}
@@ -617,6 +624,11 @@ var removeOldRoutes = action.Action{
 				err = nil
 			}()
 		}
+		// Units of apps in maintenance are not routed, removing their
+		// routes would also mark them to be added back on rollback.
+		if provision.InMaintenance(args.app) {
+			return
+		}
 		writer := args.writer
 		if writer == nil {
 			writer = ioutil.Discard
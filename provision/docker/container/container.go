@@ -18,6 +18,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/tsuru/config"
 	"github.com/tsuru/tsuru/action"
+	"github.com/tsuru/tsuru/app/image"
 	tsuruErrors "github.com/tsuru/tsuru/errors"
 	"github.com/tsuru/tsuru/event"
 	"github.com/tsuru/tsuru/log"
@@ -146,7 +147,7 @@ func (c *Container) Create(args *CreateArgs) error {
 	if args.Building {
 		user, _ = dockercommon.UserForContainer()
 	}
-	hostConf, err := c.hostConfig(args.App, args.Deploy)
+	hostConf, err := c.hostConfig(args.App, args.ImageID, args.Deploy)
 	if err != nil {
 		return err
 	}
@@ -243,7 +244,7 @@ func (c *Container) ExpectedStatus() provision.Status {
 func (c *Container) SetStatus(client provision.BuilderDockerClient, status provision.Status, triggerCallback bool) error {
 	c.Status = status.String()
 	c.LastStatusUpdate = time.Now().In(time.UTC)
-	if c.Status != provision.StatusError.String() {
+	if c.Status != provision.StatusError.String() && c.Status != provision.StatusCrashLoop.String() {
 		c.StatusBeforeError = c.Status
 	}
 	if c.Status == provision.StatusStarted.String() ||
@@ -467,7 +468,21 @@ type StartArgs struct {
 	Deploy  bool
 }
 
-func (c *Container) hostConfig(app provision.App, isDeploy bool) (*docker.HostConfig, error) {
+// restartPolicy returns the docker restart policy matching the restart_policy
+// set in the tsuru.yaml of the image. Docker itself doubles the delay between
+// restarts of a failing container.
+func restartPolicy(imageID string) (docker.RestartPolicy, error) {
+	yamlData, err := image.GetImageTsuruYamlData(imageID)
+	if err != nil {
+		return docker.RestartPolicy{}, err
+	}
+	if yamlData.RestartPolicy.OnFailure() {
+		return docker.RestartOnFailure(yamlData.RestartPolicy.MaxRestarts), nil
+	}
+	return docker.AlwaysRestart(), nil
+}
+
+func (c *Container) hostConfig(app provision.App, imageID string, isDeploy bool) (*docker.HostConfig, error) {
 	sharedBasedir, _ := config.GetString("docker:sharedfs:hostdir")
 	sharedMount, _ := config.GetString("docker:sharedfs:mountpoint")
 	sharedIsolation, _ := config.GetBool("docker:sharedfs:app-isolation")
@@ -479,7 +494,11 @@ func (c *Container) hostConfig(app provision.App, isDeploy bool) (*docker.HostCo
 	if !isDeploy {
 		hostConfig.Memory = app.GetMemory()
 		hostConfig.MemorySwap = app.GetMemory() + app.GetSwap()
-		hostConfig.RestartPolicy = docker.AlwaysRestart()
+		policy, err := restartPolicy(imageID)
+		if err != nil {
+			return nil, err
+		}
+		hostConfig.RestartPolicy = policy
 		hostConfig.PortBindings = map[docker.Port][]docker.PortBinding{
 			docker.Port(c.ExposedPort): {{HostIP: "", HostPort: ""}},
 		}
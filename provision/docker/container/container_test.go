@@ -24,6 +24,7 @@ import (
 	"github.com/tsuru/tsuru/action"
 	"github.com/tsuru/tsuru/app"
 	"github.com/tsuru/tsuru/app/bind"
+	"github.com/tsuru/tsuru/app/image"
 	"github.com/tsuru/tsuru/db"
 	"github.com/tsuru/tsuru/provision"
 	"github.com/tsuru/tsuru/provision/docker/types"
@@ -283,6 +284,36 @@ func (s *S) TestContainerCreateSecurityOptions(c *check.C) {
 	c.Assert(container.Config.SecurityOpts, check.DeepEquals, []string{"label:type:svirt_apache", "ptrace peer=@unsecure"})
 }
 
+func (s *S) TestContainerCreateRestartPolicyOnFailure(c *check.C) {
+	app := provisiontest.NewFakeApp("app-name", "brainfuck", 1)
+	routertest.FakeRouter.AddBackend(app)
+	defer routertest.FakeRouter.RemoveBackend(app.GetName())
+	img := "tsuru/brainfuck:latest"
+	s.cli.PullImage(docker.PullImageOptions{Repository: img}, docker.AuthConfiguration{})
+	err := image.SaveImageCustomData(img, map[string]interface{}{
+		"restart_policy": map[string]interface{}{"policy": "on-failure", "max_restarts": 3},
+	})
+	c.Assert(err, check.IsNil)
+	cont := Container{Container: types.Container{
+		Name:    "myName",
+		AppName: app.GetName(),
+		Type:    app.GetPlatform(),
+		Status:  "created",
+	}}
+	err = cont.Create(&CreateArgs{
+		App:      app,
+		ImageID:  img,
+		Commands: []string{"docker", "run"},
+		Client:   s.cli,
+	})
+	c.Assert(err, check.IsNil)
+	defer s.removeTestContainer(&cont)
+	dcli, _ := docker.NewClient(s.server.URL())
+	container, err := dcli.InspectContainer(cont.ID)
+	c.Assert(err, check.IsNil)
+	c.Assert(container.HostConfig.RestartPolicy, check.Equals, docker.RestartOnFailure(3))
+}
+
 func (s *S) TestContainerCreateForDeploy(c *check.C) {
 	s.server.CustomHandler("/images/.*/json", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		response := docker.Image{
@@ -81,11 +81,7 @@ func (p *dockerProvisioner) HandleMoveErrors(moveErrors chan error, writer io.Wr
 	return nil
 }
 
-func (p *dockerProvisioner) runReplaceUnitsPipeline(w io.Writer, a provision.App, toAdd map[string]*containersToAdd, toRemoveContainers []container.Container, imageID string, toHosts ...string) ([]container.Container, error) {
-	var toHost string
-	if len(toHosts) > 0 {
-		toHost = toHosts[0]
-	}
+func (p *dockerProvisioner) runReplaceUnitsPipeline(w io.Writer, a provision.App, toAdd map[string]*containersToAdd, toRemoveContainers []container.Container, imageID, toHost string) ([]container.Container, error) {
 	if w == nil {
 		w = ioutil.Discard
 	}
@@ -130,7 +126,7 @@ func (p *dockerProvisioner) runReplaceUnitsPipeline(w io.Writer, a provision.App
 	return pipeline.Result().([]container.Container), nil
 }
 
-func (p *dockerProvisioner) runCreateUnitsPipeline(w io.Writer, a provision.App, toAdd map[string]*containersToAdd, imageID, exposedPort string) ([]container.Container, error) {
+func (p *dockerProvisioner) runCreateUnitsPipeline(w io.Writer, a provision.App, toAdd map[string]*containersToAdd, imageID, exposedPort, toHost string) ([]container.Container, error) {
 	if w == nil {
 		w = ioutil.Discard
 	}
@@ -138,6 +134,7 @@ func (p *dockerProvisioner) runCreateUnitsPipeline(w io.Writer, a provision.App,
 	args := changeUnitsPipelineArgs{
 		app:         a,
 		toAdd:       toAdd,
+		toHost:      toHost,
 		writer:      w,
 		imageID:     imageID,
 		provisioner: p,
@@ -184,10 +181,8 @@ func (p *dockerProvisioner) MoveOneContainer(c container.Container, toHost strin
 		}
 		return container.Container{}
 	}
-	var destHosts []string
 	var suffix string
 	if toHost != "" {
-		destHosts = []string{toHost}
 		suffix = " -> " + toHost
 	}
 	if !p.isDryMode {
@@ -201,7 +196,7 @@ func (p *dockerProvisioner) MoveOneContainer(c container.Container, toHost strin
 		evtClone.SetLogWriter(ioutil.Discard)
 		pipeWriter = &evtClone
 	}
-	addedContainers, err := p.runReplaceUnitsPipeline(pipeWriter, a, toAdd, []container.Container{c}, imageID, destHosts...)
+	addedContainers, err := p.runReplaceUnitsPipeline(pipeWriter, a, toAdd, []container.Container{c}, imageID, toHost)
 	if err != nil {
 		errCh <- &tsuruErrors.CompositeError{
 			Base:    err,
@@ -53,6 +53,9 @@ func runHealthcheck(cont *container.Container, w io.Writer) error {
 	if maxWaitTime == 0 {
 		maxWaitTime = 120
 	}
+	if yamlData.Healthcheck.TimeoutSeconds > 0 {
+		maxWaitTime = yamlData.Healthcheck.TimeoutSeconds
+	}
 	maxWaitTime = maxWaitTime * int(time.Second)
 	sleepTime := 3 * time.Second
 	startedTime := time.Now()
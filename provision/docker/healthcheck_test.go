@@ -243,6 +243,38 @@ func (s *S) TestHealthcheckErrorsAfterMaxTime(c *check.C) {
 	c.Assert(err, check.ErrorMatches, "healthcheck fail.*lookup some-invalid-server-name.some-invalid-server-name.com.*no such host")
 }
 
+func (s *S) TestHealthcheckErrorsAfterAppTimeout(c *check.C) {
+	a := app.App{Name: "myapp1"}
+	imageName := "tsuru/app"
+	customData := map[string]interface{}{
+		"healthcheck": map[string]interface{}{
+			"path":            "/x/y",
+			"timeout_seconds": 1,
+		},
+	}
+	err := image.SaveImageCustomData(imageName, customData)
+	c.Assert(err, check.IsNil)
+	err = s.conn.Apps().Insert(a)
+	c.Assert(err, check.IsNil)
+	url, _ := url.Parse("http://some-invalid-server-name.some-invalid-server-name.com:9123")
+	host, port, _ := net.SplitHostPort(url.Host)
+	cont := container.Container{Container: types.Container{AppName: a.Name, HostAddr: host, HostPort: port, Image: imageName}}
+	buf := bytes.Buffer{}
+	config.Set("docker:healthcheck:max-time", 600)
+	defer config.Unset("docker:healthcheck:max-time")
+	done := make(chan struct{})
+	go func() {
+		err = runHealthcheck(&cont, &buf)
+		close(done)
+	}()
+	select {
+	case <-time.After(10 * time.Second):
+		c.Fatal("Timed out waiting for healthcheck to fail")
+	case <-done:
+	}
+	c.Assert(err, check.ErrorMatches, "healthcheck fail.*lookup some-invalid-server-name.some-invalid-server-name.com.*no such host")
+}
+
 func (s *S) TestHealthcheckSuccessfulWithAllowedFailures(c *check.C) {
 	var requests []*http.Request
 	lock := sync.Mutex{}
@@ -0,0 +1,105 @@
+// Copyright 2018 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package docker
+
+import (
+	"sync"
+	"time"
+
+	"github.com/fsouza/go-dockerclient"
+	"github.com/pkg/errors"
+	"github.com/tsuru/docker-cluster/cluster"
+	tsuruErrors "github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/net"
+	"github.com/tsuru/tsuru/provision"
+	"github.com/tsuru/tsuru/provision/docker/container"
+)
+
+const statsTimeout = 10 * time.Second
+
+func (p *dockerProvisioner) UnitsMetrics(a provision.App) ([]provision.UnitMetrics, error) {
+	containers, err := p.listContainersByApp(a.GetName())
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := p.Cluster().UnfilteredNodes()
+	if err != nil {
+		return nil, err
+	}
+	nodeSet := map[string]*cluster.Node{}
+	for i := range nodes {
+		nodeSet[net.URLToHost(nodes[i].Address)] = &nodes[i]
+	}
+	result := make([]provision.UnitMetrics, len(containers))
+	errs := tsuruErrors.NewMultiError()
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	for i := range containers {
+		node := nodeSet[containers[i].HostAddr]
+		if node == nil {
+			errs.Add(errors.Errorf("node not found for container %q", containers[i].ShortID()))
+			continue
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			metrics, err := containerMetrics(node, &containers[i])
+			if err != nil {
+				mu.Lock()
+				errs.Add(err)
+				mu.Unlock()
+				return
+			}
+			result[i] = metrics
+		}(i)
+	}
+	wg.Wait()
+	if err = errs.ToError(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func containerMetrics(node *cluster.Node, cont *container.Container) (provision.UnitMetrics, error) {
+	metrics := provision.UnitMetrics{
+		ID:          cont.ID,
+		Name:        cont.Name,
+		ProcessName: cont.ProcessName,
+	}
+	client, err := node.Client()
+	if err != nil {
+		return metrics, err
+	}
+	statsCh := make(chan *docker.Stats, 1)
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- client.Stats(docker.StatsOptions{
+			ID:      cont.ID,
+			Stats:   statsCh,
+			Stream:  false,
+			Timeout: statsTimeout,
+		})
+	}()
+	stats, ok := <-statsCh
+	err = <-errCh
+	if err != nil {
+		return metrics, errors.Wrapf(err, "unable to get stats for container %q", cont.ShortID())
+	}
+	if !ok || stats == nil {
+		return metrics, errors.Errorf("no stats received for container %q", cont.ShortID())
+	}
+	cpuDelta := float64(stats.CPUStats.CPUUsage.TotalUsage) - float64(stats.PreCPUStats.CPUUsage.TotalUsage)
+	systemDelta := float64(stats.CPUStats.SystemCPUUsage) - float64(stats.PreCPUStats.SystemCPUUsage)
+	if cpuDelta > 0 && systemDelta > 0 {
+		metrics.CPUPercent = cpuDelta / systemDelta * float64(len(stats.CPUStats.CPUUsage.PercpuUsage)) * 100
+	}
+	metrics.MemoryUsage = stats.MemoryStats.Usage
+	metrics.MemoryLimit = stats.MemoryStats.Limit
+	for _, network := range stats.Networks {
+		metrics.NetworkRx += network.RxBytes
+		metrics.NetworkTx += network.TxBytes
+	}
+	return metrics, nil
+}
@@ -85,6 +85,7 @@ var (
 	_ provision.InitializableProvisioner = &dockerProvisioner{}
 	_ provision.OptionalLogsProvisioner  = &dockerProvisioner{}
 	_ provision.UnitStatusProvisioner    = &dockerProvisioner{}
+	_ provision.BuildUnitProvisioner     = &dockerProvisioner{}
 	_ provision.NodeProvisioner          = &dockerProvisioner{}
 	_ provision.NodeRebalanceProvisioner = &dockerProvisioner{}
 	_ provision.NodeContainerProvisioner = &dockerProvisioner{}
@@ -302,7 +303,38 @@ func (p *dockerProvisioner) Restart(a provision.App, process string, w io.Writer
 		toAdd[c.ProcessName].Quantity++
 		toAdd[c.ProcessName].Status = provision.StatusStarted
 	}
-	_, err = p.runReplaceUnitsPipeline(w, a, toAdd, containers, imageID)
+	_, err = p.runReplaceUnitsPipeline(w, a, toAdd, containers, imageID, "")
+	return err
+}
+
+func (p *dockerProvisioner) RestartUnits(a provision.App, unitIDs []string, w io.Writer) error {
+	containers := make([]container.Container, 0, len(unitIDs))
+	for _, id := range unitIDs {
+		c, err := p.GetContainer(id)
+		if err != nil {
+			return err
+		}
+		if c.AppName != a.GetName() {
+			return &provision.UnitNotFoundError{ID: id}
+		}
+		containers = append(containers, *c)
+	}
+	imageID, err := image.AppCurrentImageName(a.GetName())
+	if err != nil {
+		return err
+	}
+	if w == nil {
+		w = ioutil.Discard
+	}
+	toAdd := make(map[string]*containersToAdd)
+	for _, c := range containers {
+		if _, ok := toAdd[c.ProcessName]; !ok {
+			toAdd[c.ProcessName] = &containersToAdd{Quantity: 0}
+		}
+		toAdd[c.ProcessName].Quantity++
+		toAdd[c.ProcessName].Status = provision.StatusStarted
+	}
+	_, err = p.runReplaceUnitsPipeline(w, a, toAdd, containers, imageID, "")
 	return err
 }
 
@@ -427,13 +459,13 @@ func (p *dockerProvisioner) deploy(a provision.App, imageID string, evt *event.E
 		if err = setQuota(a, toAdd); err != nil {
 			return err
 		}
-		_, err = p.runCreateUnitsPipeline(evt, a, toAdd, imageID, imageData.ExposedPort)
+		_, err = p.runCreateUnitsPipeline(evt, a, toAdd, imageID, imageData.ExposedPort, "")
 	} else {
 		toAdd := getContainersToAdd(imageData, containers)
 		if err = setQuota(a, toAdd); err != nil {
 			return err
 		}
-		_, err = p.runReplaceUnitsPipeline(evt, a, toAdd, containers, imageID)
+		_, err = p.runReplaceUnitsPipeline(evt, a, toAdd, containers, imageID, "")
 	}
 	return err
 }
@@ -582,6 +614,36 @@ func addContainersWithHost(args *changeUnitsPipelineArgs) ([]container.Container
 }
 
 func (p *dockerProvisioner) AddUnits(a provision.App, units uint, process string, w io.Writer) error {
+	return p.addUnits(a, units, process, "", w)
+}
+
+func (p *dockerProvisioner) AddUnitsOnNode(a provision.App, units uint, process, node string, w io.Writer) error {
+	host, err := p.appNodeHost(a, node)
+	if err != nil {
+		return err
+	}
+	return p.addUnits(a, units, process, host, w)
+}
+
+// appNodeHost returns the host of the given node, ensuring it's one of the
+// nodes where the app may run.
+func (p *dockerProvisioner) appNodeHost(a provision.App, node string) (string, error) {
+	host := net.URLToHost(node)
+	nodes, err := p.Nodes(a)
+	if err != nil {
+		return "", err
+	}
+	for _, n := range nodes {
+		if net.URLToHost(n.Address) == host {
+			return host, nil
+		}
+	}
+	return "", &tsuruErrors.ValidationError{
+		Message: fmt.Sprintf("node %q is not available for the app %q", node, a.GetName()),
+	}
+}
+
+func (p *dockerProvisioner) addUnits(a provision.App, units uint, process, toHost string, w io.Writer) error {
 	if a.GetDeploys() == 0 {
 		return errors.New("New units can only be added after the first deployment")
 	}
@@ -599,33 +661,43 @@ func (p *dockerProvisioner) AddUnits(a provision.App, units uint, process string
 	if err != nil {
 		return err
 	}
-	_, err = p.runCreateUnitsPipeline(w, a, map[string]*containersToAdd{process: {Quantity: int(units)}}, imageID, imageData.ExposedPort)
+	_, err = p.runCreateUnitsPipeline(w, a, map[string]*containersToAdd{process: {Quantity: int(units)}}, imageID, imageData.ExposedPort, toHost)
 	return err
 }
 
-func (p *dockerProvisioner) RemoveUnits(a provision.App, units uint, processName string, w io.Writer) error {
+// processContainers checks the arguments to remove units from the process of
+// the app, returning its containers and the process name, which defaults to
+// the web process of the current image.
+func (p *dockerProvisioner) processContainers(a provision.App, units uint, processName string) ([]container.Container, string, error) {
 	if a == nil {
-		return errors.New("remove units: app should not be nil")
+		return nil, "", errors.New("remove units: app should not be nil")
 	}
 	if units == 0 {
-		return errors.New("cannot remove zero units")
-	}
-	var err error
-	if w == nil {
-		w = ioutil.Discard
+		return nil, "", errors.New("cannot remove zero units")
 	}
 	imgID, err := image.AppCurrentImageName(a.GetName())
 	if err != nil {
-		return err
+		return nil, "", err
 	}
 	_, processName, err = dockercommon.ProcessCmdForImage(processName, imgID)
 	if err != nil {
-		return err
+		return nil, "", err
 	}
 	containers, err := p.listContainersByProcess(a.GetName(), processName)
+	if err != nil {
+		return nil, "", err
+	}
+	return containers, processName, nil
+}
+
+func (p *dockerProvisioner) RemoveUnits(a provision.App, units uint, processName string, w io.Writer) error {
+	containers, processName, err := p.processContainers(a, units, processName)
 	if err != nil {
 		return err
 	}
+	if w == nil {
+		w = ioutil.Discard
+	}
 	if len(containers) < int(units) {
 		return errors.Errorf("cannot remove %d units from process %q, only %d available", units, processName, len(containers))
 	}
@@ -651,6 +723,35 @@ func (p *dockerProvisioner) RemoveUnits(a provision.App, units uint, processName
 		p.scheduler.ignoredContainers = append(p.scheduler.ignoredContainers, cont.ID)
 		toRemove = append(toRemove, *cont)
 	}
+	return p.removeUnits(a, toRemove, w)
+}
+
+func (p *dockerProvisioner) RemoveUnitsOnNode(a provision.App, units uint, processName, node string, w io.Writer) error {
+	containers, processName, err := p.processContainers(a, units, processName)
+	if err != nil {
+		return err
+	}
+	host, err := p.appNodeHost(a, node)
+	if err != nil {
+		return err
+	}
+	if w == nil {
+		w = ioutil.Discard
+	}
+	toRemove := make([]container.Container, 0, units)
+	for _, c := range containers {
+		if c.HostAddr == host && len(toRemove) < int(units) {
+			toRemove = append(toRemove, c)
+		}
+	}
+	if len(toRemove) < int(units) {
+		return errors.Errorf("cannot remove %d units from process %q in node %q, only %d available", units, processName, node, len(toRemove))
+	}
+	fmt.Fprintf(w, "\n---- Removing %d %s from node %s ----\n", units, pluralize("unit", int(units)), host)
+	return p.removeUnits(a, toRemove, w)
+}
+
+func (p *dockerProvisioner) removeUnits(a provision.App, toRemove []container.Container, w io.Writer) error {
 	args := changeUnitsPipelineArgs{
 		app:         a,
 		toRemove:    toRemove,
@@ -662,7 +763,7 @@ func (p *dockerProvisioner) RemoveUnits(a provision.App, units uint, processName
 		&provisionRemoveOldUnits,
 		&provisionUnbindOldUnits,
 	)
-	err = pipeline.Execute(args)
+	err := pipeline.Execute(args)
 	if err != nil {
 		return errors.Wrap(err, "error removing routes, units weren't removed")
 	}
@@ -695,6 +796,9 @@ func (p *dockerProvisioner) SetUnitStatus(unit provision.Unit, status provision.
 	if unit.AppName != "" && cont.AppName != unit.AppName {
 		return errors.New("wrong app name")
 	}
+	if status == provision.StatusError && p.crashLooping(cont) {
+		status = provision.StatusCrashLoop
+	}
 	err = cont.SetStatus(p.ClusterClient(), status, true)
 	if err != nil {
 		return err
@@ -702,6 +806,25 @@ func (p *dockerProvisioner) SetUnitStatus(unit provision.Unit, status provision.
 	return p.checkContainer(cont)
 }
 
+// crashLooping returns whether docker gave up restarting the container after
+// reaching the maximum retry count of its on-failure restart policy.
+func (p *dockerProvisioner) crashLooping(cont *container.Container) bool {
+	dockerContainer, err := p.Cluster().InspectContainer(cont.ID)
+	if err != nil {
+		log.Errorf("unable to inspect container %s: %s", cont.ID, err)
+		return false
+	}
+	if dockerContainer.HostConfig == nil {
+		return false
+	}
+	policy := dockerContainer.HostConfig.RestartPolicy
+	if dockerContainer.State.Running || dockerContainer.State.Restarting ||
+		policy.Name != "on-failure" || policy.MaximumRetryCount == 0 {
+		return false
+	}
+	return dockerContainer.RestartCount >= policy.MaximumRetryCount
+}
+
 func (p *dockerProvisioner) ExecuteCommandOnce(stdout, stderr io.Writer, app provision.App, cmd string, args ...string) error {
 	containers, err := p.listRunnableContainersByApp(app.GetName())
 	if err != nil {
@@ -817,6 +940,14 @@ func (p *dockerProvisioner) RegisterUnit(a provision.App, unitId string, customD
 	return p.checkContainer(cont)
 }
 
+func (p *dockerProvisioner) IsBuildUnit(a provision.App, unitId string) (bool, error) {
+	cont, err := p.GetContainer(unitId)
+	if err != nil {
+		return false, err
+	}
+	return cont.Status == provision.StatusBuilding.String(), nil
+}
+
 func (p *dockerProvisioner) Shell(opts provision.ShellOptions) error {
 	var (
 		c   *container.Container
@@ -119,6 +119,58 @@ func (s *S) TestProvisionerRestart(c *check.C) {
 	c.Assert(dbConts[0].HostPort, check.Equals, expectedPort)
 }
 
+func (s *S) TestProvisionerRestartUnits(c *check.C) {
+	app := provisiontest.NewFakeApp("almah", "static", 1)
+	customData := map[string]interface{}{
+		"processes": map[string]interface{}{
+			"web":    "python web.py",
+			"worker": "python worker.py",
+		},
+	}
+	cont1, err := s.newContainer(&newContainerOpts{
+		AppName:         app.GetName(),
+		ProcessName:     "web",
+		ImageCustomData: customData,
+		Image:           "tsuru/app-" + app.GetName(),
+	}, nil)
+	c.Assert(err, check.IsNil)
+	defer s.removeTestContainer(cont1)
+	cont2, err := s.newContainer(&newContainerOpts{
+		AppName:         app.GetName(),
+		ProcessName:     "worker",
+		ImageCustomData: customData,
+		Image:           "tsuru/app-" + app.GetName(),
+	}, nil)
+	c.Assert(err, check.IsNil)
+	defer s.removeTestContainer(cont2)
+	err = s.p.Start(app, "")
+	c.Assert(err, check.IsNil)
+	err = s.p.RestartUnits(app, []string{cont1.ID}, nil)
+	c.Assert(err, check.IsNil)
+	dbConts, err := s.p.listAllContainers()
+	c.Assert(err, check.IsNil)
+	c.Assert(dbConts, check.HasLen, 2)
+	_, err = s.p.GetContainer(cont1.ID)
+	c.Assert(err, check.FitsTypeOf, &provision.UnitNotFoundError{})
+	web, err := s.p.listContainersByProcess(app.GetName(), "web")
+	c.Assert(err, check.IsNil)
+	c.Assert(web, check.HasLen, 1)
+	c.Assert(web[0].Status, check.Equals, provision.StatusStarting.String())
+	_, err = s.p.GetContainer(cont2.ID)
+	c.Assert(err, check.IsNil)
+}
+
+func (s *S) TestProvisionerRestartUnitsFromOtherApp(c *check.C) {
+	app := provisiontest.NewFakeApp("almah", "static", 1)
+	cont, err := s.newContainer(&newContainerOpts{AppName: "otherapp"}, nil)
+	c.Assert(err, check.IsNil)
+	defer s.removeTestContainer(cont)
+	err = s.p.RestartUnits(app, []string{cont.ID}, nil)
+	c.Assert(err, check.DeepEquals, &provision.UnitNotFoundError{ID: cont.ID})
+	_, err = s.p.GetContainer(cont.ID)
+	c.Assert(err, check.IsNil)
+}
+
 func (s *S) TestProvisionerRestartStoppedContainer(c *check.C) {
 	app := provisiontest.NewFakeApp("almah", "static", 1)
 	customData := map[string]interface{}{
@@ -314,6 +366,50 @@ func (s *S) TestDeploy(c *check.C) {
 	})
 }
 
+func (s *S) TestDeployInMaintenance(c *check.C) {
+	config.Set("maintenance:address", "http://maintenance.tsuru.io")
+	defer config.Unset("maintenance")
+	stopCh := s.stopContainers(s.server.URL(), 1)
+	defer func() { <-stopCh }()
+	err := newFakeImage(s.p, "tsuru/python:latest", nil)
+	c.Assert(err, check.IsNil)
+	a := s.newApp("myapp")
+	err = app.CreateApp(&a, s.user)
+	c.Assert(err, check.IsNil)
+	err = a.EnableMaintenance(nil)
+	c.Assert(err, check.IsNil)
+	customData := map[string]interface{}{
+		"processes": map[string]interface{}{
+			"web": "python myapp.py",
+		},
+	}
+	err = image.SaveImageCustomData("tsuru/app-"+a.Name+":v1", customData)
+	c.Assert(err, check.IsNil)
+	evt, err := event.New(&event.Opts{
+		Target:  event.Target{Type: "app", Value: a.Name},
+		Kind:    permission.PermAppDeploy,
+		Owner:   s.token,
+		Allowed: event.Allowed(permission.PermApp),
+	})
+	c.Assert(err, check.IsNil)
+	builderImgID := "tsuru/app-" + a.Name + ":v1-builder"
+	pullOpts := docker.PullImageOptions{
+		Repository: "tsuru/app-" + a.Name,
+		Tag:        "v1-builder",
+	}
+	err = s.p.Cluster().PullImage(pullOpts, dockercommon.RegistryAuthConfig())
+	c.Assert(err, check.IsNil)
+	_, err = s.p.Deploy(&a, builderImgID, evt)
+	c.Assert(err, check.IsNil)
+	units, err := a.Units()
+	c.Assert(err, check.IsNil)
+	c.Assert(units, check.HasLen, 1)
+	routes, err := routertest.FakeRouter.Routes(a.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(routes, check.HasLen, 1)
+	c.Assert(routes[0].String(), check.Equals, "http://maintenance.tsuru.io")
+}
+
 func (s *S) TestDeployWithLimiterActive(c *check.C) {
 	config.Set("docker:limit:actions-per-host", 1)
 	defer config.Unset("docker:limit:actions-per-host")
@@ -882,6 +978,83 @@ func (s *S) TestProvisionerAddUnitsWithHost(c *check.C) {
 	c.Assert(count, check.Equals, 2)
 }
 
+func (s *S) TestProvisionerAddUnitsOnNode(c *check.C) {
+	p, err := s.startMultipleServersCluster()
+	c.Assert(err, check.IsNil)
+	err = newFakeImage(p, "tsuru/app-myapp", nil)
+	c.Assert(err, check.IsNil)
+	a := provisiontest.NewFakeApp("myapp", "python", 0)
+	a.Deploys = 1
+	p.Provision(a)
+	err = p.AddUnitsOnNode(a, 2, "web", "http://localhost:2375", nil)
+	c.Assert(err, check.IsNil)
+	containers, err := p.listContainersByApp(a.GetName())
+	c.Assert(err, check.IsNil)
+	c.Assert(containers, check.HasLen, 2)
+	for _, cont := range containers {
+		c.Assert(cont.HostAddr, check.Equals, "localhost")
+	}
+}
+
+func (s *S) TestProvisionerAddUnitsOnNodeNotAvailable(c *check.C) {
+	err := newFakeImage(s.p, "tsuru/app-myapp", nil)
+	c.Assert(err, check.IsNil)
+	a := provisiontest.NewFakeApp("myapp", "python", 0)
+	a.Deploys = 1
+	s.p.Provision(a)
+	err = s.p.AddUnitsOnNode(a, 1, "web", "http://10.0.0.9:2375", nil)
+	c.Assert(err, check.FitsTypeOf, &errors.ValidationError{})
+	c.Assert(err, check.ErrorMatches, `node "http://10.0.0.9:2375" is not available for the app "myapp"`)
+}
+
+func (s *S) TestProvisionerRemoveUnitsOnNode(c *check.C) {
+	p, err := s.startMultipleServersCluster()
+	c.Assert(err, check.IsNil)
+	err = newFakeImage(p, "tsuru/app-myapp", nil)
+	c.Assert(err, check.IsNil)
+	a := provisiontest.NewFakeApp("myapp", "python", 0)
+	p.Provision(a)
+	coll := p.Collection()
+	defer coll.Close()
+	conts := []container.Container{
+		{Container: types.Container{ID: "1", Name: "c1", AppName: a.GetName(), ProcessName: "web", HostAddr: "127.0.0.1", HostPort: "1"}},
+		{Container: types.Container{ID: "2", Name: "c2", AppName: a.GetName(), ProcessName: "web", HostAddr: "localhost", HostPort: "2"}},
+		{Container: types.Container{ID: "3", Name: "c3", AppName: a.GetName(), ProcessName: "web", HostAddr: "localhost", HostPort: "3"}},
+	}
+	for _, cont := range conts {
+		err = coll.Insert(cont)
+		c.Assert(err, check.IsNil)
+	}
+	var buf bytes.Buffer
+	err = p.RemoveUnitsOnNode(a, 1, "web", "http://localhost:2375", &buf)
+	c.Assert(err, check.IsNil)
+	c.Assert(buf.String(), check.Matches, `(?s).*---- Removing 1 unit from node localhost ----.*`)
+	_, err = p.GetContainer("1")
+	c.Assert(err, check.IsNil)
+	_, err = p.GetContainer("2")
+	c.Assert(err, check.NotNil)
+	_, err = p.GetContainer("3")
+	c.Assert(err, check.IsNil)
+	err = p.RemoveUnitsOnNode(a, 2, "web", "localhost", nil)
+	c.Assert(err, check.ErrorMatches, `cannot remove 2 units from process "web" in node "localhost", only 1 available`)
+}
+
+func (s *S) TestProvisionerRemoveUnitsOnNodeNotAvailable(c *check.C) {
+	err := newFakeImage(s.p, "tsuru/app-myapp", nil)
+	c.Assert(err, check.IsNil)
+	a := provisiontest.NewFakeApp("myapp", "python", 0)
+	s.p.Provision(a)
+	coll := s.p.Collection()
+	defer coll.Close()
+	err = coll.Insert(container.Container{Container: types.Container{ID: "1", Name: "c1", AppName: a.GetName(), ProcessName: "web", HostAddr: "10.0.0.9", HostPort: "1"}})
+	c.Assert(err, check.IsNil)
+	err = s.p.RemoveUnitsOnNode(a, 1, "web", "http://10.0.0.9:2375", nil)
+	c.Assert(err, check.FitsTypeOf, &errors.ValidationError{})
+	c.Assert(err, check.ErrorMatches, `node "http://10.0.0.9:2375" is not available for the app "myapp"`)
+	_, err = s.p.GetContainer("1")
+	c.Assert(err, check.IsNil)
+}
+
 func (s *S) TestProvisionerAddUnitsWithHostPartialRollback(c *check.C) {
 	err := newFakeImage(s.p, "tsuru/app-myapp", nil)
 	c.Assert(err, check.IsNil)
@@ -3230,3 +3403,39 @@ func (s *S) TestRebalanceNodesDry(c *check.C) {
 	c.Assert(err, check.IsNil)
 	c.Assert(containers, check.HasLen, 4)
 }
+
+func (s *S) TestProvisionerUnitsMetrics(c *check.C) {
+	app := provisiontest.NewFakeApp("myapp", "python", 1)
+	cont, err := s.newContainer(&newContainerOpts{AppName: app.GetName(), ProcessName: "web"}, nil)
+	c.Assert(err, check.IsNil)
+	defer s.removeTestContainer(cont)
+	s.server.PrepareStats(cont.ID, func(id string) docker.Stats {
+		var stats docker.Stats
+		stats.CPUStats.CPUUsage.TotalUsage = 300
+		stats.CPUStats.CPUUsage.PercpuUsage = []uint64{150, 150}
+		stats.CPUStats.SystemCPUUsage = 2000
+		stats.PreCPUStats.CPUUsage.TotalUsage = 100
+		stats.PreCPUStats.SystemCPUUsage = 1000
+		stats.MemoryStats.Usage = 1024
+		stats.MemoryStats.Limit = 4096
+		stats.Networks = map[string]docker.NetworkStats{
+			"eth0": {RxBytes: 10, TxBytes: 20},
+			"eth1": {RxBytes: 1, TxBytes: 2},
+		}
+		return stats
+	})
+	metrics, err := s.p.UnitsMetrics(app)
+	c.Assert(err, check.IsNil)
+	c.Assert(metrics, check.DeepEquals, []provision.UnitMetrics{
+		{
+			ID:          cont.ID,
+			Name:        cont.Name,
+			ProcessName: "web",
+			CPUPercent:  40,
+			MemoryUsage: 1024,
+			MemoryLimit: 4096,
+			NetworkRx:   11,
+			NetworkTx:   22,
+		},
+	})
+}
@@ -424,7 +424,25 @@ func createDeployTimeoutError(client *clusterClient, a provision.App, processNam
 	return errors.Errorf("timeout waiting %s after %v waiting for units%s", label, timeout, msgErrorPart)
 }
 
-func monitorDeployment(client *clusterClient, dep *v1beta2.Deployment, a provision.App, processName string, w io.Writer) error {
+// healthcheckMaxWait returns how long to wait for the new units to become
+// ready, healthcheck:timeout_seconds in the tsuru.yaml of the image takes
+// precedence over the docker:healthcheck:max-time config.
+func healthcheckMaxWait(img string) (time.Duration, error) {
+	yamlData, err := image.GetImageTsuruYamlData(img)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	maxWaitTime := yamlData.Healthcheck.TimeoutSeconds
+	if maxWaitTime <= 0 {
+		maxWaitTime, _ = config.GetInt("docker:healthcheck:max-time")
+	}
+	if maxWaitTime <= 0 {
+		maxWaitTime = 120
+	}
+	return time.Duration(maxWaitTime) * time.Second, nil
+}
+
+func monitorDeployment(client *clusterClient, dep *v1beta2.Deployment, a provision.App, processName, img string, w io.Writer) error {
 	fmt.Fprintf(w, "\n---- Updating units [%s] ----\n", processName)
 	kubeConf := getKubeConfig()
 	timeout := time.After(kubeConf.DeploymentProgressTimeout)
@@ -447,11 +465,10 @@ func monitorDeployment(client *clusterClient, dep *v1beta2.Deployment, a provisi
 	oldUpdatedReplicas := int32(-1)
 	oldReadyUnits := int32(-1)
 	oldPendingTermination := int32(-1)
-	maxWaitTime, _ := config.GetInt("docker:healthcheck:max-time")
-	if maxWaitTime == 0 {
-		maxWaitTime = 120
+	maxWaitTimeDuration, err := healthcheckMaxWait(img)
+	if err != nil {
+		return err
 	}
-	maxWaitTimeDuration := time.Duration(maxWaitTime) * time.Second
 	var healthcheckTimeout <-chan time.Time
 	t0 := time.Now()
 	for {
@@ -523,7 +540,7 @@ func (m *serviceManager) DeployService(a provision.App, process string, labels *
 	if m.writer == nil {
 		m.writer = ioutil.Discard
 	}
-	err = monitorDeployment(m.client, dep, a, process, m.writer)
+	err = monitorDeployment(m.client, dep, a, process, img, m.writer)
 	if err != nil {
 		fmt.Fprintf(m.writer, "\n**** ROLLING BACK AFTER FAILURE ****\n ---> %s <---\n", err)
 		rollbackErr := m.client.ExtensionsV1beta1().Deployments(m.client.Namespace()).Rollback(&extensions.DeploymentRollback{
@@ -8,6 +8,7 @@ import (
 	"bytes"
 	"sort"
 	"strconv"
+	"time"
 
 	"github.com/fsouza/go-dockerclient"
 	"github.com/pkg/errors"
@@ -841,6 +842,24 @@ func (s *S) TestServiceManagerDeployServiceRollbackHealthcheckTimeout(c *check.C
 	c.Assert(err, check.ErrorMatches, "^timeout waiting healthcheck after .+ waiting for units: Pod myapp-p1-pod-2-1: invalid pod phase \"Running\" - last event: my evt message$")
 }
 
+func (s *S) TestHealthcheckMaxWait(c *check.C) {
+	wait, err := healthcheckMaxWait("myimg")
+	c.Assert(err, check.IsNil)
+	c.Assert(wait, check.Equals, 120*time.Second)
+	config.Set("docker:healthcheck:max-time", 1)
+	defer config.Unset("docker:healthcheck:max-time")
+	wait, err = healthcheckMaxWait("myimg")
+	c.Assert(err, check.IsNil)
+	c.Assert(wait, check.Equals, time.Second)
+	err = image.SaveImageCustomData("myimg", map[string]interface{}{
+		"healthcheck": map[string]interface{}{"path": "/hc", "timeout_seconds": 30},
+	})
+	c.Assert(err, check.IsNil)
+	wait, err = healthcheckMaxWait("myimg")
+	c.Assert(err, check.IsNil)
+	c.Assert(wait, check.Equals, 30*time.Second)
+}
+
 func (s *S) TestServiceManagerDeployServiceRollbackPendingPod(c *check.C) {
 	config.Set("docker:healthcheck:max-time", 1)
 	defer config.Unset("docker:healthcheck:max-time")
@@ -53,6 +53,7 @@ var (
 	_ provision.SleepableProvisioner     = &kubernetesProvisioner{}
 	_ provision.ImageDeployer            = &kubernetesProvisioner{}
 	_ provision.VolumeProvisioner        = &kubernetesProvisioner{}
+	_ provision.BuildUnitProvisioner     = &kubernetesProvisioner{}
 	// _ provision.ArchiveDeployer          = &kubernetesProvisioner{}
 	// _ provision.InitializableProvisioner = &kubernetesProvisioner{}
 	// _ provision.RollbackableDeployer     = &kubernetesProvisioner{}
@@ -216,6 +217,18 @@ var stateMap = map[apiv1.PodPhase]provision.Status{
 	apiv1.PodUnknown:   provision.StatusError,
 }
 
+// podStatus returns the unit status for the pod, pods whose containers are
+// being held back by kubernetes after failing repeatedly are reported as
+// StatusCrashLoop.
+func podStatus(pod *apiv1.Pod) provision.Status {
+	for _, contStatus := range pod.Status.ContainerStatuses {
+		if contStatus.State.Waiting != nil && contStatus.State.Waiting.Reason == "CrashLoopBackOff" {
+			return provision.StatusCrashLoop
+		}
+	}
+	return stateMap[pod.Status.Phase]
+}
+
 func (p *kubernetesProvisioner) podsToUnits(client *clusterClient, pods []apiv1.Pod, baseApp provision.App, baseNode *apiv1.Node) ([]provision.Unit, error) {
 	var err error
 	if len(pods) == 0 {
@@ -288,7 +301,7 @@ func (p *kubernetesProvisioner) podsToUnits(client *clusterClient, pods []apiv1.
 			ProcessName: appProcess,
 			Type:        l.AppPlatform(),
 			IP:          wrapper.ip(),
-			Status:      stateMap[pod.Status.Phase],
+			Status:      podStatus(&pod),
 			Address:     url,
 		}
 	}
@@ -411,6 +424,21 @@ func (p *kubernetesProvisioner) RegisterUnit(a provision.App, unitID string, cus
 	return errors.WithStack(image.SaveImageCustomData(buildingImage, customData))
 }
 
+func (p *kubernetesProvisioner) IsBuildUnit(a provision.App, unitID string) (bool, error) {
+	client, err := clusterForPool(a.GetPool())
+	if err != nil {
+		return false, err
+	}
+	pod, err := client.CoreV1().Pods(client.Namespace()).Get(unitID, metav1.GetOptions{})
+	if err != nil {
+		if k8sErrors.IsNotFound(err) {
+			return false, &provision.UnitNotFoundError{ID: unitID}
+		}
+		return false, errors.WithStack(err)
+	}
+	return labelSetFromMeta(&pod.ObjectMeta).IsDeploy(), nil
+}
+
 func (p *kubernetesProvisioner) ListNodes(addressFilter []string) ([]provision.Node, error) {
 	var nodes []provision.Node
 	kubeConf := getKubeConfig()
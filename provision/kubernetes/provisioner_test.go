@@ -486,6 +486,15 @@ func (s *S) TestUnitsEmpty(c *check.C) {
 	c.Assert(units, check.HasLen, 0)
 }
 
+func (s *S) TestPodStatusCrashLoop(c *check.C) {
+	pod := apiv1.Pod{Status: apiv1.PodStatus{Phase: apiv1.PodRunning}}
+	c.Assert(podStatus(&pod), check.Equals, provision.StatusStarted)
+	pod.Status.ContainerStatuses = []apiv1.ContainerStatus{
+		{State: apiv1.ContainerState{Waiting: &apiv1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}},
+	}
+	c.Assert(podStatus(&pod), check.Equals, provision.StatusCrashLoop)
+}
+
 func (s *S) TestUnitsTimeoutShort(c *check.C) {
 	wantedTimeout := 0.1
 	config.Set("kubernetes:api-short-timeout", wantedTimeout)
@@ -16,6 +16,7 @@ import (
 	"github.com/tsuru/tsuru/auth"
 	"github.com/tsuru/tsuru/db"
 	tsuruErrors "github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/event"
 	"github.com/tsuru/tsuru/provision"
 	"github.com/tsuru/tsuru/router"
 	"github.com/tsuru/tsuru/service"
@@ -34,6 +35,10 @@ var (
 	ErrPoolHasNoService               = errors.New("no service found for pool")
 )
 
+func init() {
+	event.SetWebhookTeamPools(teamPoolNames)
+}
+
 type Pool struct {
 	Name        string `bson:"_id"`
 	Default     bool
@@ -413,6 +418,18 @@ func ListPoolsForTeam(team string) ([]Pool, error) {
 	return getPoolsSatisfyConstraints(true, ConstraintTypeTeam, team)
 }
 
+func teamPoolNames(team string) ([]string, error) {
+	pools, err := ListPoolsForTeam(team)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(pools))
+	for i, p := range pools {
+		names[i] = p.Name
+	}
+	return names, nil
+}
+
 func listPools(query bson.M) ([]Pool, error) {
 	conn, err := db.Conn()
 	if err != nil {
@@ -80,6 +80,8 @@ func ParseStatus(status string) (Status, error) {
 		return StatusStopped, nil
 	case "asleep":
 		return StatusAsleep, nil
+	case "crashloop":
+		return StatusCrashLoop, nil
 	}
 	return Status(""), ErrInvalidStatus
 }
@@ -132,6 +134,11 @@ const (
 
 	// StatusAsleep is for cases where the unit has been asleep.
 	StatusAsleep = Status("asleep")
+
+	// StatusCrashLoop is for units that kept failing after being restarted
+	// and reached the maximum number of restarts set in the app restart
+	// policy. They won't be restarted again by the provisioner.
+	StatusCrashLoop = Status("crashloop")
 )
 
 // Unit represents a provision unit. Can be a machine, container or anything
@@ -239,6 +246,20 @@ type AppLock interface {
 	GetAcquireDate() time.Time
 }
 
+// MaintenanceApp is an app that may be in maintenance mode, with its routes
+// pointing to a maintenance server instead of its units. Provisioners must not
+// add routes to the units of apps in maintenance.
+type MaintenanceApp interface {
+	App
+	InMaintenance() bool
+}
+
+// InMaintenance returns whether the app is in maintenance mode.
+func InMaintenance(a App) bool {
+	m, ok := a.(MaintenanceApp)
+	return ok && m.InMaintenance()
+}
+
 // ShellOptions is the set of options that can be used when calling the method
 // Shell in the provisioner.
 type ShellOptions struct {
@@ -394,6 +415,47 @@ type SleepableProvisioner interface {
 	Sleep(App, string) error
 }
 
+// UnitRestarterProvisioner is a provisioner that allows restarting specific
+// units of an application.
+type UnitRestarterProvisioner interface {
+	// RestartUnits restarts the units of the application with the given
+	// ids, leaving other units untouched.
+	RestartUnits(app App, unitIDs []string, w io.Writer) error
+}
+
+// NodeUnitsProvisioner is a provisioner that allows choosing the node where
+// units of an application are added to or removed from.
+type NodeUnitsProvisioner interface {
+	// AddUnitsOnNode adds units of the given process to the node with the
+	// given address, which must be one of the nodes available to the app.
+	AddUnitsOnNode(app App, units uint, process, node string, w io.Writer) error
+
+	// RemoveUnitsOnNode removes units of the given process running in the
+	// node with the given address.
+	RemoveUnitsOnNode(app App, units uint, process, node string, w io.Writer) error
+}
+
+// UnitMetrics holds the resource usage of a unit, as reported by the
+// provisioner.
+type UnitMetrics struct {
+	ID          string
+	Name        string
+	ProcessName string
+	// CPUPercent is the percentage of the host CPUs used by the unit, it
+	// may be higher than 100 when the unit uses more than one CPU.
+	CPUPercent  float64
+	MemoryUsage uint64
+	MemoryLimit uint64
+	NetworkRx   uint64
+	NetworkTx   uint64
+}
+
+// MetricsProvisioner is a provisioner that reports the resource usage of the
+// units of an application.
+type MetricsProvisioner interface {
+	UnitsMetrics(App) ([]UnitMetrics, error)
+}
+
 // MessageProvisioner is a provisioner that provides a welcome message for
 // logging.
 type MessageProvisioner interface {
@@ -420,6 +482,13 @@ type UnitStatusProvisioner interface {
 	SetUnitStatus(Unit, Status) error
 }
 
+// BuildUnitProvisioner is a provisioner able to tell whether a unit is
+// building an image for the app, instead of running it.
+type BuildUnitProvisioner interface {
+	// IsBuildUnit returns whether the unit is building an image for the app.
+	IsBuildUnit(App, string) (bool, error)
+}
+
 type AddNodeOptions struct {
 	IaaSID     string
 	Address    string
@@ -675,8 +744,10 @@ func (e *Error) Error() string {
 }
 
 type TsuruYamlData struct {
-	Hooks       TsuruYamlHooks       `bson:",omitempty"`
-	Healthcheck TsuruYamlHealthcheck `bson:",omitempty"`
+	Hooks         TsuruYamlHooks         `bson:",omitempty"`
+	Healthcheck   TsuruYamlHealthcheck   `bson:",omitempty"`
+	Dependencies  TsuruYamlDependencies  `bson:",omitempty"`
+	RestartPolicy TsuruYamlRestartPolicy `json:"restart_policy" yaml:"restart_policy" bson:"restart_policy,omitempty"`
 }
 
 type TsuruYamlHooks struct {
@@ -697,6 +768,7 @@ type TsuruYamlHealthcheck struct {
 	RouterBody      string `json:"router_body" yaml:"router_body" bson:"router_body,omitempty"`
 	UseInRouter     bool   `json:"use_in_router" yaml:"use_in_router" bson:"use_in_router,omitempty"`
 	AllowedFailures int    `json:"allowed_failures" yaml:"allowed_failures" bson:"allowed_failures,omitempty"`
+	TimeoutSeconds  int    `json:"timeout_seconds" yaml:"timeout_seconds" bson:"timeout_seconds,omitempty"`
 }
 
 func (hc TsuruYamlHealthcheck) ToRouterHC() router.HealthcheckData {
@@ -711,3 +783,45 @@ func (hc TsuruYamlHealthcheck) ToRouterHC() router.HealthcheckData {
 		Path: "/",
 	}
 }
+
+// TsuruYamlDependencies describes what must be available before the units of
+// the app are started by a deploy or restart.
+type TsuruYamlDependencies struct {
+	WaitServices   bool `json:"wait_services" yaml:"wait_services" bson:"wait_services,omitempty"`
+	TimeoutSeconds int  `json:"timeout_seconds" yaml:"timeout_seconds" bson:"timeout_seconds,omitempty"`
+}
+
+const (
+	RestartPolicyAlways    = "always"
+	RestartPolicyOnFailure = "on-failure"
+)
+
+// TsuruYamlRestartPolicy describes how the provisioner restarts the units of
+// the app whose process exits. With the on-failure policy, units are restarted
+// at most MaxRestarts times, waiting DelaySeconds between restarts when the
+// provisioner supports it, and are then reported as StatusCrashLoop.
+type TsuruYamlRestartPolicy struct {
+	Policy       string `json:"policy" yaml:"policy" bson:"policy,omitempty"`
+	MaxRestarts  int    `json:"max_restarts" yaml:"max_restarts" bson:"max_restarts,omitempty"`
+	DelaySeconds int    `json:"delay_seconds" yaml:"delay_seconds" bson:"delay_seconds,omitempty"`
+}
+
+// OnFailure returns whether units must only be restarted when their process
+// exits with an error.
+func (p TsuruYamlRestartPolicy) OnFailure() bool {
+	return p.Policy == RestartPolicyOnFailure
+}
+
+// Validate returns an error if the policy is unknown or has negative values,
+// an unknown policy would otherwise behave as the always policy.
+func (p TsuruYamlRestartPolicy) Validate() error {
+	switch p.Policy {
+	case "", RestartPolicyAlways, RestartPolicyOnFailure:
+	default:
+		return errors.Errorf("invalid restart policy %q, must be either %q or %q", p.Policy, RestartPolicyAlways, RestartPolicyOnFailure)
+	}
+	if p.MaxRestarts < 0 || p.DelaySeconds < 0 {
+		return errors.New("restart policy max_restarts and delay_seconds cannot be negative")
+	}
+	return nil
+}
@@ -658,6 +658,13 @@ func (p *FakeProvisioner) Restarts(a provision.App, process string) int {
 	return p.apps[a.GetName()].restarts[process]
 }
 
+// UnitRestarts returns the number of restarts for a given unit.
+func (p *FakeProvisioner) UnitRestarts(a provision.App, unitID string) int {
+	p.mut.RLock()
+	defer p.mut.RUnlock()
+	return p.apps[a.GetName()].unitRestarts[unitID]
+}
+
 // Starts returns the number of starts for a given app.
 func (p *FakeProvisioner) Starts(app provision.App, process string) int {
 	p.mut.RLock()
@@ -924,11 +931,12 @@ func (p *FakeProvisioner) Provision(app provision.App) error {
 	p.mut.Lock()
 	defer p.mut.Unlock()
 	p.apps[app.GetName()] = provisionedApp{
-		app:      app,
-		restarts: make(map[string]int),
-		starts:   make(map[string]int),
-		stops:    make(map[string]int),
-		sleeps:   make(map[string]int),
+		app:          app,
+		restarts:     make(map[string]int),
+		unitRestarts: make(map[string]int),
+		starts:       make(map[string]int),
+		stops:        make(map[string]int),
+		sleeps:       make(map[string]int),
 	}
 	return nil
 }
@@ -951,6 +959,38 @@ func (p *FakeProvisioner) Restart(app provision.App, process string, w io.Writer
 	return nil
 }
 
+func (p *FakeProvisioner) RestartUnits(app provision.App, unitIDs []string, w io.Writer) error {
+	if err := p.getError("RestartUnits"); err != nil {
+		return err
+	}
+	p.mut.Lock()
+	defer p.mut.Unlock()
+	pApp, ok := p.apps[app.GetName()]
+	if !ok {
+		return errNotProvisioned
+	}
+	for _, id := range unitIDs {
+		found := false
+		for _, u := range pApp.units {
+			if u.ID == id {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return &provision.UnitNotFoundError{ID: id}
+		}
+	}
+	for _, id := range unitIDs {
+		pApp.unitRestarts[id]++
+	}
+	p.apps[app.GetName()] = pApp
+	if w != nil {
+		fmt.Fprintf(w, "restarting units")
+	}
+	return nil
+}
+
 func (p *FakeProvisioner) Start(app provision.App, process string) error {
 	p.mut.Lock()
 	defer p.mut.Unlock()
@@ -981,6 +1021,11 @@ func (p *FakeProvisioner) AddUnits(app provision.App, n uint, process string, w
 	return err
 }
 
+func (p *FakeProvisioner) AddUnitsOnNode(app provision.App, n uint, process, node string, w io.Writer) error {
+	_, err := p.AddUnitsToNode(app, n, process, w, node)
+	return err
+}
+
 func (p *FakeProvisioner) AddUnitsToNode(app provision.App, n uint, process string, w io.Writer, nodeAddr string) ([]provision.Unit, error) {
 	if err := p.getError("AddUnits"); err != nil {
 		return nil, err
@@ -1080,6 +1125,47 @@ func (p *FakeProvisioner) RemoveUnits(app provision.App, n uint, process string,
 	return nil
 }
 
+func (p *FakeProvisioner) RemoveUnitsOnNode(app provision.App, n uint, process, node string, w io.Writer) error {
+	if err := p.getError("RemoveUnits"); err != nil {
+		return err
+	}
+	if n == 0 {
+		return errors.New("cannot remove 0 units")
+	}
+	p.mut.Lock()
+	defer p.mut.Unlock()
+	pApp, ok := p.apps[app.GetName()]
+	if !ok {
+		return errNotProvisioned
+	}
+	host := net.URLToHost(node)
+	var newUnits []provision.Unit
+	removedCount := n
+	var addresses []*url.URL
+	for _, u := range pApp.units {
+		if removedCount > 0 && u.ProcessName == process && u.IP == host {
+			removedCount--
+			addresses = append(addresses, u.Address)
+			continue
+		}
+		newUnits = append(newUnits, u)
+	}
+	if removedCount > 0 {
+		return errors.New("too many units to remove")
+	}
+	err := routertest.FakeRouter.RemoveRoutes(app.GetName(), addresses)
+	if err != nil {
+		return err
+	}
+	if w != nil {
+		fmt.Fprintf(w, "removing %d units from %s", n, host)
+	}
+	pApp.units = newUnits
+	pApp.unitLen = len(newUnits)
+	p.apps[app.GetName()] = pApp
+	return nil
+}
+
 // ExecuteCommand will pretend to execute the given command, recording data
 // about it.
 //
@@ -1220,6 +1306,30 @@ func (p *FakeProvisioner) Units(apps ...provision.App) ([]provision.Unit, error)
 	return allUnits, nil
 }
 
+func (p *FakeProvisioner) UnitsMetrics(app provision.App) ([]provision.UnitMetrics, error) {
+	if err := p.getError("UnitsMetrics"); err != nil {
+		return nil, err
+	}
+	p.mut.Lock()
+	defer p.mut.Unlock()
+	pApp, ok := p.apps[app.GetName()]
+	if !ok {
+		return nil, errNotProvisioned
+	}
+	metrics := make([]provision.UnitMetrics, len(pApp.units))
+	for i, u := range pApp.units {
+		metrics[i] = provision.UnitMetrics{
+			ID:          u.ID,
+			Name:        u.Name,
+			ProcessName: u.ProcessName,
+			CPUPercent:  12.5,
+			MemoryUsage: 64 * 1024 * 1024,
+			MemoryLimit: 128 * 1024 * 1024,
+		}
+	}
+	return metrics, nil
+}
+
 func (p *FakeProvisioner) RoutableAddresses(app provision.App) ([]url.URL, error) {
 	p.mut.Lock()
 	defer p.mut.Unlock()
@@ -1369,6 +1479,21 @@ func (p *FakeProvisioner) RegisterUnit(a provision.App, unitId string, customDat
 	return &provision.UnitNotFoundError{ID: unitId}
 }
 
+func (p *FakeProvisioner) IsBuildUnit(a provision.App, unitId string) (bool, error) {
+	p.mut.RLock()
+	defer p.mut.RUnlock()
+	pa, ok := p.apps[a.GetName()]
+	if !ok {
+		return false, errors.New("app not found")
+	}
+	for _, u := range pa.units {
+		if u.ID == unitId {
+			return u.Status == provision.StatusBuilding, nil
+		}
+	}
+	return false, &provision.UnitNotFoundError{ID: unitId}
+}
+
 func (p *FakeProvisioner) Shell(opts provision.ShellOptions) error {
 	var unit provision.Unit
 	units, err := p.Units(opts.App)
@@ -1480,16 +1605,17 @@ func (p *PipelineErrorFakeProvisioner) DeployPipeline() *action.Pipeline {
 }
 
 type provisionedApp struct {
-	units       []provision.Unit
-	app         provision.App
-	restarts    map[string]int
-	starts      map[string]int
-	stops       map[string]int
-	sleeps      map[string]int
-	lastArchive string
-	lastFile    io.ReadCloser
-	cnames      []string
-	unitLen     int
-	lastData    map[string]interface{}
-	image       string
+	units        []provision.Unit
+	app          provision.App
+	restarts     map[string]int
+	unitRestarts map[string]int
+	starts       map[string]int
+	stops        map[string]int
+	sleeps       map[string]int
+	lastArchive  string
+	lastFile     io.ReadCloser
+	cnames       []string
+	unitLen      int
+	lastData     map[string]interface{}
+	image        string
 }
@@ -225,6 +225,26 @@ func extraRegisterCmds(app provision.App) string {
 	return fmt.Sprintf(`curl -sSL -m15 -XPOST -d"hostname=$(hostname)" -o/dev/null -H"Content-Type:application/x-www-form-urlencoded" -H"Authorization:bearer %s" %sapps/%s/units/register || true`, token, host, app.GetName())
 }
 
+func toRestartPolicy(policy provision.TsuruYamlRestartPolicy) *swarm.RestartPolicy {
+	if !policy.OnFailure() {
+		return &swarm.RestartPolicy{
+			Condition: swarm.RestartPolicyConditionAny,
+		}
+	}
+	restartPolicy := &swarm.RestartPolicy{
+		Condition: swarm.RestartPolicyConditionOnFailure,
+	}
+	if policy.MaxRestarts > 0 {
+		maxAttempts := uint64(policy.MaxRestarts)
+		restartPolicy.MaxAttempts = &maxAttempts
+	}
+	if policy.DelaySeconds > 0 {
+		delay := time.Duration(policy.DelaySeconds) * time.Second
+		restartPolicy.Delay = &delay
+	}
+	return restartPolicy
+}
+
 func serviceSpecForApp(opts tsuruServiceOpts) (*swarm.ServiceSpec, error) {
 	var envs []string
 	appEnvs := provision.EnvsForApp(opts.app, opts.process, opts.isDeploy)
@@ -236,6 +256,9 @@ func serviceSpecForApp(opts tsuruServiceOpts) (*swarm.ServiceSpec, error) {
 	var endpointSpec *swarm.EndpointSpec
 	var networks []swarm.NetworkAttachmentConfig
 	var healthConfig *container.HealthConfig
+	restartPolicy := &swarm.RestartPolicy{
+		Condition: swarm.RestartPolicyConditionAny,
+	}
 	port := provision.WebProcessDefaultPort()
 	portInt, _ := strconv.Atoi(port)
 	mounts, err := mountsForApp(opts.app)
@@ -263,6 +286,7 @@ func serviceSpecForApp(opts tsuruServiceOpts) (*swarm.ServiceSpec, error) {
 			return nil, errors.WithStack(err)
 		}
 		healthConfig = toHealthConfig(yamlData.Healthcheck, portInt)
+		restartPolicy = toRestartPolicy(yamlData.RestartPolicy)
 	}
 	if opts.labels == nil {
 		opts.labels, err = provision.ServiceLabels(provision.ServiceLabelsOpts{
@@ -304,10 +328,8 @@ func serviceSpecForApp(opts tsuruServiceOpts) (*swarm.ServiceSpec, error) {
 				Healthcheck: healthConfig,
 				Mounts:      mounts,
 			},
-			Networks: networks,
-			RestartPolicy: &swarm.RestartPolicy{
-				Condition: swarm.RestartPolicyConditionAny,
-			},
+			Networks:      networks,
+			RestartPolicy: restartPolicy,
 			Placement: &swarm.Placement{
 				Constraints: []string{
 					toNodePoolConstraint(opts.app.GetPool(), true),
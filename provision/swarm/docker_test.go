@@ -12,6 +12,7 @@ import (
 	"os"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/docker/docker/api/types/mount"
 	"github.com/docker/docker/api/types/swarm"
@@ -379,6 +380,33 @@ func (s *S) TestServiceSpecForApp(c *check.C) {
 	}
 }
 
+func (s *S) TestToRestartPolicy(c *check.C) {
+	maxAttempts := uint64(3)
+	delay := 10 * time.Second
+	tests := []struct {
+		input    provision.TsuruYamlRestartPolicy
+		expected *swarm.RestartPolicy
+	}{
+		{input: provision.TsuruYamlRestartPolicy{}, expected: &swarm.RestartPolicy{
+			Condition: swarm.RestartPolicyConditionAny,
+		}},
+		{input: provision.TsuruYamlRestartPolicy{Policy: "always", MaxRestarts: 3}, expected: &swarm.RestartPolicy{
+			Condition: swarm.RestartPolicyConditionAny,
+		}},
+		{input: provision.TsuruYamlRestartPolicy{Policy: "on-failure"}, expected: &swarm.RestartPolicy{
+			Condition: swarm.RestartPolicyConditionOnFailure,
+		}},
+		{input: provision.TsuruYamlRestartPolicy{Policy: "on-failure", MaxRestarts: 3, DelaySeconds: 10}, expected: &swarm.RestartPolicy{
+			Condition:   swarm.RestartPolicyConditionOnFailure,
+			MaxAttempts: &maxAttempts,
+			Delay:       &delay,
+		}},
+	}
+	for _, tt := range tests {
+		c.Check(toRestartPolicy(tt.input), check.DeepEquals, tt.expected)
+	}
+}
+
 func (s *S) TestServiceSpecForNodeContainer(c *check.C) {
 	c1 := nodecontainer.NodeContainerConfig{
 		Name: "swarmbs",
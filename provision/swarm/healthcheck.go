@@ -35,6 +35,9 @@ func toHealthConfig(hc provision.TsuruYamlHealthcheck, port int) *container.Heal
 	if maxWaitTime == 0 {
 		maxWaitTime = 120
 	}
+	if hc.TimeoutSeconds > 0 {
+		maxWaitTime = hc.TimeoutSeconds
+	}
 	curlLine := fmt.Sprintf("curl -X%s -fsSL http://localhost:%d/%s", method, port, strings.TrimPrefix(path, "/"))
 	if match != "" {
 		curlLine = fmt.Sprintf("%s | egrep %q", curlLine, match)
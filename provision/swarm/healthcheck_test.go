@@ -59,6 +59,18 @@ func (s *S) TestToHealthConfig(c *check.C) {
 			Interval: 3 * time.Second,
 			Retries:  11,
 		}},
+		{input: provision.TsuruYamlHealthcheck{
+			Path:           "/hc",
+			TimeoutSeconds: 30,
+		}, expected: &container.HealthConfig{
+			Test: []string{
+				"CMD-SHELL",
+				"curl -XGET -fsSL http://localhost:9000/hc -o/dev/null -w '%{http_code}' | grep 200",
+			},
+			Timeout:  30 * time.Second,
+			Interval: 3 * time.Second,
+			Retries:  1,
+		}},
 	}
 	for i, test := range tests {
 		result := toHealthConfig(test.input, 9000)
@@ -49,6 +49,7 @@ var (
 	_ provision.SleepableProvisioner     = &swarmProvisioner{}
 	_ provision.BuilderDeploy            = &swarmProvisioner{}
 	_ provision.VolumeProvisioner        = &swarmProvisioner{}
+	_ provision.BuildUnitProvisioner     = &swarmProvisioner{}
 	_ cluster.InitClusterProvisioner     = &swarmProvisioner{}
 	// _ provision.RollbackableDeployer     = &swarmProvisioner{}
 	// _ provision.RebuildableDeployer      = &swarmProvisioner{}
@@ -218,6 +219,13 @@ func tasksToUnits(client *clusterClient, tasks []swarm.Task) ([]provision.Unit,
 	serviceMap := map[string]*swarm.Service{}
 	appsMap := map[string]provision.App{}
 	units := []provision.Unit{}
+	latest := map[string]swarm.Task{}
+	for _, t := range tasks {
+		key := taskSlotKey(&t)
+		if l, ok := latest[key]; !ok || t.Meta.CreatedAt.After(l.Meta.CreatedAt) {
+			latest[key] = t
+		}
+	}
 	for _, t := range tasks {
 		labels := provision.LabelSet{Labels: t.Spec.ContainerSpec.Labels, Prefix: tsuruLabelPrefix}
 		if !labels.IsService() {
@@ -252,11 +260,31 @@ func tasksToUnits(client *clusterClient, tasks []swarm.Task) ([]provision.Unit,
 			}
 			appsMap[appName] = a
 		}
-		units = append(units, taskToUnit(&t, serviceMap[t.ServiceID], nodeMap[t.NodeID], appsMap[appName]))
+		unit := taskToUnit(&t, serviceMap[t.ServiceID], nodeMap[t.NodeID], appsMap[appName])
+		if unit.Status == provision.StatusError && crashLooping(service, &t, latest[taskSlotKey(&t)]) {
+			unit.Status = provision.StatusCrashLoop
+		}
+		units = append(units, unit)
 	}
 	return units, nil
 }
 
+func taskSlotKey(t *swarm.Task) string {
+	return fmt.Sprintf("%s/%d", t.ServiceID, t.Slot)
+}
+
+// crashLooping returns whether swarm gave up restarting the tasks in a slot
+// of the service after reaching the maximum attempts of its restart policy,
+// which happens when the failed task is the newest one in its slot. Counting
+// the failed tasks is not enough, as swarm only keeps a few of them.
+func crashLooping(service *swarm.Service, task *swarm.Task, latest swarm.Task) bool {
+	policy := service.Spec.TaskTemplate.RestartPolicy
+	if policy == nil || policy.Condition != swarm.RestartPolicyConditionOnFailure || policy.MaxAttempts == nil {
+		return false
+	}
+	return latest.ID == task.ID
+}
+
 func (p *swarmProvisioner) Units(apps ...provision.App) ([]provision.Unit, error) {
 	var units []provision.Unit
 	for _, a := range apps {
@@ -426,6 +454,28 @@ func (p *swarmProvisioner) RegisterUnit(a provision.App, unitId string, customDa
 	return image.SaveImageCustomData(buildingImage, customData)
 }
 
+func (p *swarmProvisioner) IsBuildUnit(a provision.App, unitId string) (bool, error) {
+	client, err := clusterForPool(a.GetPool())
+	if err != nil {
+		return false, err
+	}
+	l, err := provision.ProcessLabels(provision.ProcessLabelsOpts{App: a, Prefix: tsuruLabelPrefix})
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+	taskOpts := docker.ListTasksOptions{
+		Filters: map[string][]string{
+			"label": toLabelSelectors(l.ToAppSelector()),
+		},
+	}
+	task, tasks, err := findTaskByContainerId(client, taskOpts, unitId, waitContainerIDTimeout)
+	if err != nil {
+		return false, errors.Wrapf(err, "task not found for container %q, existing tasks: %#v", unitId, tasks)
+	}
+	labels := provision.LabelSet{Labels: task.Spec.ContainerSpec.Labels, Prefix: tsuruLabelPrefix}
+	return labels.IsDeploy(), nil
+}
+
 func (p *swarmProvisioner) ListNodes(addressFilter []string) ([]provision.Node, error) {
 	clusters, err := allClusters()
 	if err != nil {
@@ -456,6 +456,22 @@ func (s *S) TestUnitsWithNoNodeIDServiceIDTasks(c *check.C) {
 	c.Assert(units, check.HasLen, 0)
 }
 
+func (s *S) TestCrashLooping(c *check.C) {
+	maxAttempts := uint64(5)
+	onFailure := &swarm.Service{}
+	onFailure.Spec.TaskTemplate.RestartPolicy = &swarm.RestartPolicy{
+		Condition:   swarm.RestartPolicyConditionOnFailure,
+		MaxAttempts: &maxAttempts,
+	}
+	always := &swarm.Service{}
+	always.Spec.TaskTemplate.RestartPolicy = &swarm.RestartPolicy{Condition: swarm.RestartPolicyConditionAny}
+	failed := swarm.Task{ID: "t6", Status: swarm.TaskStatus{State: swarm.TaskStateFailed}}
+	newer := swarm.Task{ID: "t7", Status: swarm.TaskStatus{State: swarm.TaskStatePending}}
+	c.Assert(crashLooping(onFailure, &failed, failed), check.Equals, true)
+	c.Assert(crashLooping(onFailure, &failed, newer), check.Equals, false)
+	c.Assert(crashLooping(always, &failed, failed), check.Equals, false)
+}
+
 func (s *S) TestUnitsWithoutSwarmCluster(c *check.C) {
 	s.addCluster(c)
 	a := &app.App{Name: "myapp", TeamOwner: s.team.Name, Deploys: 1}
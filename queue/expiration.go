@@ -0,0 +1,33 @@
+// Copyright 2018 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package queue
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/tsuru/config"
+	"github.com/tsuru/monsterqueue"
+	"github.com/tsuru/tsuru/log"
+)
+
+// ErrJobExpired is the error set as result of jobs that were not started
+// before the time set in queue:job-expiration.
+var ErrJobExpired = errors.New("job expired before running")
+
+func expireJob(job monsterqueue.Job, next func(monsterqueue.Job)) {
+	expiration, _ := config.GetFloat("queue:job-expiration")
+	if expiration <= 0 {
+		next(job)
+		return
+	}
+	age := time.Since(job.Status().Enqueued)
+	if age <= time.Duration(expiration*float64(time.Second)) {
+		next(job)
+		return
+	}
+	log.Errorf("[queue] discarding job %s for task %q enqueued %v ago: %s", job.ID(), job.TaskName(), age, ErrJobExpired)
+	job.Error(ErrJobExpired)
+}
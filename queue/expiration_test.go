@@ -0,0 +1,48 @@
+// Copyright 2018 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package queue
+
+import (
+	"time"
+
+	"github.com/tsuru/config"
+	"github.com/tsuru/monsterqueue"
+	"gopkg.in/check.v1"
+)
+
+func (s *MemorySuite) TestExpiredJobIsNotExecuted(c *check.C) {
+	config.Set("queue:job-expiration", 0.1)
+	defer config.Unset("queue:job-expiration")
+	q, err := Queue()
+	c.Assert(err, check.IsNil)
+	task := &testTask{}
+	j, err := q.Enqueue(task.Name(), nil)
+	c.Assert(err, check.IsNil)
+	time.Sleep(200 * time.Millisecond)
+	err = q.RegisterTask(task)
+	c.Assert(err, check.IsNil)
+	err = TestingWaitQueueTasks(1, 5*time.Second)
+	c.Assert(err, check.IsNil)
+	c.Assert(task.callCount, check.Equals, 0)
+	c.Assert(j.Status().State, check.Equals, monsterqueue.JobStateDone)
+	_, err = j.Result()
+	c.Assert(err, check.Equals, ErrJobExpired)
+}
+
+func (s *MemorySuite) TestJobNotExpired(c *check.C) {
+	config.Set("queue:job-expiration", 60)
+	defer config.Unset("queue:job-expiration")
+	q, err := Queue()
+	c.Assert(err, check.IsNil)
+	task := &testTask{}
+	err = q.RegisterTask(task)
+	c.Assert(err, check.IsNil)
+	j, err := q.EnqueueWait(task.Name(), nil, time.Minute)
+	c.Assert(err, check.IsNil)
+	result, err := j.Result()
+	c.Assert(err, check.IsNil)
+	c.Assert(result, check.Equals, "result")
+	c.Assert(task.callCount, check.Equals, 1)
+}
@@ -0,0 +1,80 @@
+// Copyright 2018 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package queue
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/tsuru/monsterqueue"
+	"github.com/tsuru/tsuru/hc"
+)
+
+const (
+	healthCheckTaskName = "queue-healthcheck"
+	healthCheckTimeout  = 10 * time.Second
+	healthCheckCacheTTL = 30 * time.Second
+)
+
+// healthCheckResult holds the result of the last probe job, so frequent
+// calls to /healthcheck, like the ones from load balancers, don't pile up
+// waiting for jobs.
+var healthCheckResult struct {
+	sync.Mutex
+	err     error
+	expires time.Time
+}
+
+func init() {
+	hc.AddChecker("Queue", HealthCheck)
+}
+
+type healthCheckTask struct{}
+
+func (t *healthCheckTask) Run(j monsterqueue.Job) {
+	j.Success(nil)
+}
+
+func (t *healthCheckTask) Name() string {
+	return healthCheckTaskName
+}
+
+// HealthCheck checks that the queue is working by enqueuing a probe job and
+// waiting for it to be processed by one of the tsuru API servers. The result
+// is reused for healthCheckCacheTTL.
+func HealthCheck() error {
+	healthCheckResult.Lock()
+	defer healthCheckResult.Unlock()
+	now := time.Now()
+	if now.Before(healthCheckResult.expires) {
+		return healthCheckResult.err
+	}
+	healthCheckResult.err = runHealthCheck()
+	healthCheckResult.expires = now.Add(healthCheckCacheTTL)
+	return healthCheckResult.err
+}
+
+func resetHealthCheckResult() {
+	healthCheckResult.Lock()
+	defer healthCheckResult.Unlock()
+	healthCheckResult.err = nil
+	healthCheckResult.expires = time.Time{}
+}
+
+func runHealthCheck() error {
+	q, err := Queue()
+	if err != nil {
+		return err
+	}
+	job, err := q.EnqueueWait(healthCheckTaskName, nil, healthCheckTimeout)
+	if err != nil {
+		return errors.Wrap(err, "unable to process probe job")
+	}
+	if _, err = job.Result(); err != nil {
+		return err
+	}
+	return q.DeleteJob(job.ID())
+}
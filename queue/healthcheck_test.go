@@ -0,0 +1,46 @@
+// Copyright 2018 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package queue
+
+import (
+	"errors"
+	"time"
+
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/hc"
+	"gopkg.in/check.v1"
+)
+
+func (s *MemorySuite) TestHealthCheck(c *check.C) {
+	err := HealthCheck()
+	c.Assert(err, check.IsNil)
+	jobs, err := FindJobs(JobFilter{})
+	c.Assert(err, check.IsNil)
+	c.Assert(jobs, check.HasLen, 0)
+}
+
+func (s *MemorySuite) TestHealthCheckCachesResult(c *check.C) {
+	err := HealthCheck()
+	c.Assert(err, check.IsNil)
+	c.Assert(healthCheckResult.expires.After(time.Now()), check.Equals, true)
+	healthCheckResult.err = errors.New("cached failure")
+	err = HealthCheck()
+	c.Assert(err, check.ErrorMatches, "cached failure")
+	healthCheckResult.expires = time.Now().Add(-time.Second)
+	err = HealthCheck()
+	c.Assert(err, check.IsNil)
+}
+
+func (s *MemorySuite) TestHealthCheckRegistered(c *check.C) {
+	results := hc.Check("Queue")
+	c.Assert(results, check.HasLen, 1)
+	c.Assert(results[0].Status, check.Equals, hc.HealthCheckOK)
+}
+
+func (s *MemorySuite) TestHealthCheckUnknownDriver(c *check.C) {
+	config.Set("queue:driver", "unknown")
+	err := HealthCheck()
+	c.Assert(err, check.ErrorMatches, `unknown queue driver: "unknown"`)
+}
@@ -0,0 +1,51 @@
+// Copyright 2018 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package queue
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/tsuru/monsterqueue"
+)
+
+var (
+	jobsEnqueued = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "tsuru_queue_jobs_enqueued_total",
+		Help: "The total number of jobs added to the queue.",
+	}, []string{"task"})
+
+	taskDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "tsuru_queue_task_duration_seconds",
+		Help:    "The queued tasks execution time distributions.",
+		Buckets: append(prometheus.DefBuckets, []float64{30, 60, 120, 300, 600}...),
+	}, []string{"task"})
+
+	taskErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "tsuru_queue_task_errors_total",
+		Help: "The total number of queued tasks finished with errors.",
+	}, []string{"task"})
+
+	taskPanics = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "tsuru_queue_task_panics_total",
+		Help: "The total number of queued tasks that panicked.",
+	}, []string{"task"})
+)
+
+func init() {
+	prometheus.MustRegister(jobsEnqueued)
+	prometheus.MustRegister(taskDuration)
+	prometheus.MustRegister(taskErrors)
+	prometheus.MustRegister(taskPanics)
+}
+
+func instrumentTask(job monsterqueue.Job, next func(monsterqueue.Job)) {
+	begin := time.Now()
+	next(job)
+	taskDuration.WithLabelValues(job.TaskName()).Observe(time.Since(begin).Seconds())
+	if _, err := job.Result(); err != nil {
+		taskErrors.WithLabelValues(job.TaskName()).Inc()
+	}
+}
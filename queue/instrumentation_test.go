@@ -0,0 +1,43 @@
+// Copyright 2018 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package queue
+
+import (
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"gopkg.in/check.v1"
+)
+
+func (s *MemorySuite) TestInstrumentation(c *check.C) {
+	jobsEnqueued.Reset()
+	taskDuration.Reset()
+	taskErrors.Reset()
+	q, err := Queue()
+	c.Assert(err, check.IsNil)
+	err = q.RegisterTask(&testTask{})
+	c.Assert(err, check.IsNil)
+	err = q.RegisterTask(&failingTask{})
+	c.Assert(err, check.IsNil)
+	_, err = q.EnqueueWait("test-task", nil, time.Minute)
+	c.Assert(err, check.IsNil)
+	_, err = q.EnqueueWait("failing-task", nil, time.Minute)
+	c.Assert(err, check.IsNil)
+	_, err = q.Enqueue("test-task", nil)
+	c.Assert(err, check.IsNil)
+	err = TestingWaitQueueTasks(3, 5*time.Second)
+	c.Assert(err, check.IsNil)
+	var dtoMetric dto.Metric
+	jobsEnqueued.WithLabelValues("test-task").Write(&dtoMetric)
+	c.Assert(dtoMetric.Counter.GetValue(), check.Equals, 2.0)
+	jobsEnqueued.WithLabelValues("failing-task").Write(&dtoMetric)
+	c.Assert(dtoMetric.Counter.GetValue(), check.Equals, 1.0)
+	taskDuration.WithLabelValues("test-task").Write(&dtoMetric)
+	c.Assert(dtoMetric.Histogram.GetSampleCount(), check.Equals, uint64(2))
+	taskErrors.WithLabelValues("test-task").Write(&dtoMetric)
+	c.Assert(dtoMetric.Counter.GetValue(), check.Equals, 0.0)
+	taskErrors.WithLabelValues("failing-task").Write(&dtoMetric)
+	c.Assert(dtoMetric.Counter.GetValue(), check.Equals, 1.0)
+}
@@ -0,0 +1,94 @@
+// Copyright 2018 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package queue
+
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/tsuru/monsterqueue"
+)
+
+// ErrJobNotFailed is returned by RetryJob when the job is still pending,
+// running or finished successfully.
+var ErrJobNotFailed = errors.New("only failed jobs can be retried")
+
+// JobFilter is used to filter the jobs returned by FindJobs. Empty fields
+// match every job.
+type JobFilter struct {
+	Task  string
+	State string
+	Limit int
+}
+
+// FindJobs returns the jobs in the queue matching the filter, sorted by the
+// time they were enqueued.
+func FindJobs(filter JobFilter) ([]monsterqueue.Job, error) {
+	q, err := Queue()
+	if err != nil {
+		return nil, err
+	}
+	jobs, err := q.ListJobs()
+	if err != nil {
+		return nil, err
+	}
+	var result monsterqueue.JobList
+	for _, j := range jobs {
+		if filter.Task != "" && j.TaskName() != filter.Task {
+			continue
+		}
+		if filter.State != "" && j.Status().State != filter.State {
+			continue
+		}
+		result = append(result, j)
+	}
+	sort.Stable(result)
+	if filter.Limit > 0 && len(result) > filter.Limit {
+		result = result[:filter.Limit]
+	}
+	return result, nil
+}
+
+// RetryJob enqueues a new job with the same task and parameters of a job that
+// finished with an error.
+func RetryJob(jobID string) (monsterqueue.Job, error) {
+	q, err := Queue()
+	if err != nil {
+		return nil, err
+	}
+	job, err := q.RetrieveJob(jobID)
+	if err != nil {
+		return nil, err
+	}
+	if job.Status().State != monsterqueue.JobStateDone {
+		return nil, ErrJobNotFailed
+	}
+	if _, err = job.Result(); err == nil {
+		return nil, ErrJobNotFailed
+	}
+	return q.Enqueue(job.TaskName(), job.Parameters())
+}
+
+// RemoveDoneJobs removes finished jobs matching the filter from the queue,
+// returning the number of removed jobs. The filter state is ignored, as only
+// finished jobs can be safely removed.
+func RemoveDoneJobs(filter JobFilter) (int, error) {
+	q, err := Queue()
+	if err != nil {
+		return 0, err
+	}
+	filter.State = monsterqueue.JobStateDone
+	jobs, err := FindJobs(filter)
+	if err != nil {
+		return 0, err
+	}
+	for i, j := range jobs {
+		err = q.DeleteJob(j.ID())
+		if err != nil {
+			return i, err
+		}
+	}
+	return len(jobs), nil
+}
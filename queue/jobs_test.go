@@ -0,0 +1,103 @@
+// Copyright 2018 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package queue
+
+import (
+	"time"
+
+	"github.com/tsuru/monsterqueue"
+	"gopkg.in/check.v1"
+)
+
+func (s *MemorySuite) TestFindJobs(c *check.C) {
+	q, err := Queue()
+	c.Assert(err, check.IsNil)
+	err = q.RegisterTask(&testTask{})
+	c.Assert(err, check.IsNil)
+	done, err := q.EnqueueWait("test-task", nil, time.Minute)
+	c.Assert(err, check.IsNil)
+	j1, err := q.Enqueue("unregistered-task", monsterqueue.JobParams{"n": 1})
+	c.Assert(err, check.IsNil)
+	j2, err := q.Enqueue("unregistered-task", monsterqueue.JobParams{"n": 2})
+	c.Assert(err, check.IsNil)
+	jobs, err := FindJobs(JobFilter{})
+	c.Assert(err, check.IsNil)
+	c.Assert(jobIDs(jobs), check.DeepEquals, []string{done.ID(), j1.ID(), j2.ID()})
+	jobs, err = FindJobs(JobFilter{Task: "unregistered-task"})
+	c.Assert(err, check.IsNil)
+	c.Assert(jobIDs(jobs), check.DeepEquals, []string{j1.ID(), j2.ID()})
+	jobs, err = FindJobs(JobFilter{State: monsterqueue.JobStateDone})
+	c.Assert(err, check.IsNil)
+	c.Assert(jobIDs(jobs), check.DeepEquals, []string{done.ID()})
+	jobs, err = FindJobs(JobFilter{Task: "unregistered-task", Limit: 1})
+	c.Assert(err, check.IsNil)
+	c.Assert(jobIDs(jobs), check.DeepEquals, []string{j1.ID()})
+}
+
+func jobIDs(jobs []monsterqueue.Job) []string {
+	ids := make([]string, len(jobs))
+	for i, j := range jobs {
+		ids[i] = j.ID()
+	}
+	return ids
+}
+
+func (s *MemorySuite) TestRetryJob(c *check.C) {
+	q, err := Queue()
+	c.Assert(err, check.IsNil)
+	err = q.RegisterTask(&failingTask{})
+	c.Assert(err, check.IsNil)
+	failed, err := q.EnqueueWait("failing-task", monsterqueue.JobParams{"app": "myapp"}, time.Minute)
+	c.Assert(err, check.IsNil)
+	j, err := RetryJob(failed.ID())
+	c.Assert(err, check.IsNil)
+	c.Assert(j.ID(), check.Not(check.Equals), failed.ID())
+	c.Assert(j.TaskName(), check.Equals, "failing-task")
+	c.Assert(j.Parameters(), check.DeepEquals, monsterqueue.JobParams{"app": "myapp"})
+}
+
+func (s *MemorySuite) TestRetryJobNotFailed(c *check.C) {
+	q, err := Queue()
+	c.Assert(err, check.IsNil)
+	err = q.RegisterTask(&testTask{})
+	c.Assert(err, check.IsNil)
+	succeeded, err := q.EnqueueWait("test-task", nil, time.Minute)
+	c.Assert(err, check.IsNil)
+	enqueued, err := q.Enqueue("unregistered-task", nil)
+	c.Assert(err, check.IsNil)
+	_, err = RetryJob(succeeded.ID())
+	c.Assert(err, check.Equals, ErrJobNotFailed)
+	_, err = RetryJob(enqueued.ID())
+	c.Assert(err, check.Equals, ErrJobNotFailed)
+	_, err = RetryJob("unknown")
+	c.Assert(err, check.Equals, monsterqueue.ErrNoSuchJob)
+}
+
+func (s *MemorySuite) TestRemoveDoneJobs(c *check.C) {
+	q, err := Queue()
+	c.Assert(err, check.IsNil)
+	err = q.RegisterTask(&testTask{})
+	c.Assert(err, check.IsNil)
+	err = q.RegisterTask(&failingTask{})
+	c.Assert(err, check.IsNil)
+	_, err = q.EnqueueWait("test-task", nil, time.Minute)
+	c.Assert(err, check.IsNil)
+	failed, err := q.EnqueueWait("failing-task", nil, time.Minute)
+	c.Assert(err, check.IsNil)
+	enqueued, err := q.Enqueue("unregistered-task", nil)
+	c.Assert(err, check.IsNil)
+	n, err := RemoveDoneJobs(JobFilter{Task: "test-task", State: monsterqueue.JobStateEnqueued})
+	c.Assert(err, check.IsNil)
+	c.Assert(n, check.Equals, 1)
+	jobs, err := FindJobs(JobFilter{})
+	c.Assert(err, check.IsNil)
+	c.Assert(jobIDs(jobs), check.DeepEquals, []string{failed.ID(), enqueued.ID()})
+	n, err = RemoveDoneJobs(JobFilter{})
+	c.Assert(err, check.IsNil)
+	c.Assert(n, check.Equals, 1)
+	jobs, err = FindJobs(JobFilter{})
+	c.Assert(err, check.IsNil)
+	c.Assert(jobIDs(jobs), check.DeepEquals, []string{enqueued.ID()})
+}
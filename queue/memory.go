@@ -0,0 +1,296 @@
+// Copyright 2018 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package queue
+
+import (
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/globalsign/mgo/bson"
+	"github.com/pkg/errors"
+	"github.com/tsuru/monsterqueue"
+)
+
+func init() {
+	Register("memory", newMemoryQueue)
+}
+
+// queueMemory is a monsterqueue.Queue implementation keeping every job in
+// memory. Jobs are only visible to the process that enqueued them and are lost
+// on restart, so it should only be used in tests and single node installs.
+type queueMemory struct {
+	sync.Mutex
+	tasks  map[string]monsterqueue.Task
+	jobs   map[string]*memoryJob
+	ids    []string
+	notify chan struct{}
+	done   chan bool
+	wg     sync.WaitGroup
+}
+
+func newMemoryQueue() (monsterqueue.Queue, error) {
+	return &queueMemory{
+		tasks:  make(map[string]monsterqueue.Task),
+		jobs:   make(map[string]*memoryJob),
+		notify: make(chan struct{}, 1),
+		done:   make(chan bool),
+	}, nil
+}
+
+func (q *queueMemory) RegisterTask(task monsterqueue.Task) error {
+	q.Lock()
+	defer q.Unlock()
+	if _, isRegistered := q.tasks[task.Name()]; isRegistered {
+		return errors.New("task already registered")
+	}
+	q.tasks[task.Name()] = task
+	q.wakeUp()
+	return nil
+}
+
+func (q *queueMemory) Enqueue(taskName string, params monsterqueue.JobParams) (monsterqueue.Job, error) {
+	return q.enqueue(taskName, params, false), nil
+}
+
+func (q *queueMemory) EnqueueWait(taskName string, params monsterqueue.JobParams, timeout time.Duration) (monsterqueue.Job, error) {
+	j := q.enqueue(taskName, params, true)
+	select {
+	case <-j.finished:
+		return j, nil
+	case <-time.After(timeout):
+	}
+	q.Lock()
+	defer q.Unlock()
+	if j.status.State == monsterqueue.JobStateDone {
+		return j, nil
+	}
+	j.waited = false
+	return j, monsterqueue.ErrQueueWaitTimeout
+}
+
+func (q *queueMemory) ProcessLoop() {
+	for {
+		for q.processNext() {
+			select {
+			case <-q.done:
+				return
+			default:
+			}
+		}
+		select {
+		case <-q.notify:
+		case <-q.done:
+			return
+		}
+	}
+}
+
+func (q *queueMemory) Stop() {
+	q.done <- true
+	q.Wait()
+}
+
+func (q *queueMemory) Wait() {
+	q.wg.Wait()
+}
+
+// ReleaseRunning puts the jobs still running back in the queue.
+func (q *queueMemory) ReleaseRunning() error {
+	q.Lock()
+	defer q.Unlock()
+	for _, id := range q.ids {
+		j := q.jobs[id]
+		if j.status.State == monsterqueue.JobStateRunning {
+			j.status.State = monsterqueue.JobStateEnqueued
+			j.status.Started = time.Time{}
+		}
+	}
+	return nil
+}
+
+func (q *queueMemory) ResetStorage() error {
+	q.Lock()
+	defer q.Unlock()
+	q.jobs = make(map[string]*memoryJob)
+	q.ids = nil
+	return nil
+}
+
+func (q *queueMemory) RetrieveJob(jobId string) (monsterqueue.Job, error) {
+	q.Lock()
+	defer q.Unlock()
+	j, ok := q.jobs[jobId]
+	if !ok {
+		return nil, monsterqueue.ErrNoSuchJob
+	}
+	return j, nil
+}
+
+func (q *queueMemory) ListJobs() ([]monsterqueue.Job, error) {
+	q.Lock()
+	defer q.Unlock()
+	jobs := make([]monsterqueue.Job, len(q.ids))
+	for i, id := range q.ids {
+		jobs[i] = q.jobs[id]
+	}
+	return jobs, nil
+}
+
+func (q *queueMemory) DeleteJob(jobId string) error {
+	q.Lock()
+	defer q.Unlock()
+	if _, ok := q.jobs[jobId]; !ok {
+		return monsterqueue.ErrNoSuchJob
+	}
+	delete(q.jobs, jobId)
+	for i, id := range q.ids {
+		if id == jobId {
+			q.ids = append(q.ids[:i], q.ids[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (q *queueMemory) enqueue(taskName string, params monsterqueue.JobParams, waited bool) *memoryJob {
+	buf := make([]byte, monsterqueue.StackTraceLimit)
+	buf = buf[:runtime.Stack(buf, false)]
+	j := &memoryJob{
+		id:       bson.NewObjectId().Hex(),
+		task:     taskName,
+		params:   params,
+		stack:    string(buf),
+		waited:   waited,
+		finished: make(chan struct{}),
+		queue:    q,
+	}
+	j.status.State = monsterqueue.JobStateEnqueued
+	j.status.Enqueued = time.Now().UTC()
+	q.Lock()
+	q.jobs[j.id] = j
+	q.ids = append(q.ids, j.id)
+	q.wakeUp()
+	q.Unlock()
+	return j
+}
+
+// wakeUp notifies the processing loop that there may be new jobs to run. It
+// must be called with the queue lock held.
+func (q *queueMemory) wakeUp() {
+	select {
+	case q.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (q *queueMemory) processNext() bool {
+	q.Lock()
+	var (
+		job  *memoryJob
+		task monsterqueue.Task
+	)
+	for _, id := range q.ids {
+		j := q.jobs[id]
+		if j.status.State != monsterqueue.JobStateEnqueued {
+			continue
+		}
+		if t, ok := q.tasks[j.task]; ok {
+			job, task = j, t
+			break
+		}
+	}
+	if job == nil {
+		q.Unlock()
+		return false
+	}
+	job.status.State = monsterqueue.JobStateRunning
+	job.status.Started = time.Now().UTC()
+	q.wg.Add(1)
+	q.Unlock()
+	go func() {
+		defer q.wg.Done()
+		task.Run(job)
+		q.Lock()
+		isDone := job.status.State == monsterqueue.JobStateDone
+		q.Unlock()
+		if !isDone {
+			job.finish(nil, monsterqueue.ErrNoJobResultSet)
+		}
+	}()
+	return true
+}
+
+type memoryJob struct {
+	id       string
+	task     string
+	params   monsterqueue.JobParams
+	stack    string
+	status   monsterqueue.JobStatus
+	result   monsterqueue.JobResult
+	err      error
+	waited   bool
+	finished chan struct{}
+	queue    *queueMemory
+}
+
+func (j *memoryJob) ID() string {
+	return j.id
+}
+
+func (j *memoryJob) Parameters() monsterqueue.JobParams {
+	return j.params
+}
+
+func (j *memoryJob) TaskName() string {
+	return j.task
+}
+
+func (j *memoryJob) Queue() monsterqueue.Queue {
+	return j.queue
+}
+
+func (j *memoryJob) EnqueueStack() string {
+	return j.stack
+}
+
+func (j *memoryJob) Status() monsterqueue.JobStatus {
+	j.queue.Lock()
+	defer j.queue.Unlock()
+	return j.status
+}
+
+func (j *memoryJob) Success(result monsterqueue.JobResult) (bool, error) {
+	return j.finish(result, nil)
+}
+
+func (j *memoryJob) Error(jobErr error) (bool, error) {
+	return j.finish(nil, jobErr)
+}
+
+func (j *memoryJob) Result() (monsterqueue.JobResult, error) {
+	j.queue.Lock()
+	defer j.queue.Unlock()
+	if j.status.State != monsterqueue.JobStateDone {
+		return nil, monsterqueue.ErrNoJobResult
+	}
+	return j.result, j.err
+}
+
+func (j *memoryJob) finish(result monsterqueue.JobResult, jobErr error) (bool, error) {
+	j.queue.Lock()
+	defer j.queue.Unlock()
+	if j.status.State == monsterqueue.JobStateDone {
+		return false, nil
+	}
+	j.status.State = monsterqueue.JobStateDone
+	j.status.Done = time.Now().UTC()
+	j.result = result
+	j.err = jobErr
+	received := j.waited
+	j.waited = false
+	close(j.finished)
+	return received, nil
+}
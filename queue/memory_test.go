@@ -0,0 +1,184 @@
+// Copyright 2018 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package queue
+
+import (
+	"context"
+	"time"
+
+	"github.com/tsuru/config"
+	"github.com/tsuru/monsterqueue"
+	"gopkg.in/check.v1"
+)
+
+type MemorySuite struct{}
+
+var _ = check.Suite(&MemorySuite{})
+
+func (s *MemorySuite) SetUpTest(c *check.C) {
+	config.Set("queue:driver", "memory")
+	ResetQueue()
+}
+
+func (s *MemorySuite) TearDownTest(c *check.C) {
+	ResetQueue()
+	config.Unset("queue:driver")
+}
+
+type blockingTask struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (t *blockingTask) Run(j monsterqueue.Job) {
+	close(t.started)
+	<-t.release
+	j.Success("done")
+}
+
+func (t *blockingTask) Name() string {
+	return "blocking-task"
+}
+
+func (s *MemorySuite) TestMemoryQueue(c *check.C) {
+	q, err := Queue()
+	c.Assert(err, check.IsNil)
+	c.Assert(q.(*middlewareQueue).Queue, check.FitsTypeOf, &queueMemory{})
+	task := &testTask{}
+	err = q.RegisterTask(task)
+	c.Assert(err, check.IsNil)
+	j, err := q.EnqueueWait(task.Name(), monsterqueue.JobParams{"app": "myapp"}, time.Minute)
+	c.Assert(err, check.IsNil)
+	result, err := j.Result()
+	c.Assert(err, check.IsNil)
+	c.Assert(result, check.Equals, "result")
+	c.Assert(j.Parameters(), check.DeepEquals, monsterqueue.JobParams{"app": "myapp"})
+	c.Assert(j.Status().State, check.Equals, monsterqueue.JobStateDone)
+	c.Assert(task.callCount, check.Equals, 1)
+}
+
+func (s *MemorySuite) TestMemoryQueueJobError(c *check.C) {
+	q, err := Queue()
+	c.Assert(err, check.IsNil)
+	task := &failingTask{}
+	err = q.RegisterTask(task)
+	c.Assert(err, check.IsNil)
+	j, err := q.EnqueueWait(task.Name(), nil, time.Minute)
+	c.Assert(err, check.IsNil)
+	_, err = j.Result()
+	c.Assert(err, check.ErrorMatches, "something went wrong")
+}
+
+func (s *MemorySuite) TestMemoryQueueEnqueueWaitTimeout(c *check.C) {
+	q, err := Queue()
+	c.Assert(err, check.IsNil)
+	task := &blockingTask{started: make(chan struct{}), release: make(chan struct{})}
+	err = q.RegisterTask(task)
+	c.Assert(err, check.IsNil)
+	j, err := q.EnqueueWait(task.Name(), nil, 100*time.Millisecond)
+	c.Assert(err, check.Equals, monsterqueue.ErrQueueWaitTimeout)
+	<-task.started
+	c.Assert(j.Status().State, check.Equals, monsterqueue.JobStateRunning)
+	close(task.release)
+	q.Wait()
+	result, err := j.Result()
+	c.Assert(err, check.IsNil)
+	c.Assert(result, check.Equals, "done")
+}
+
+func (s *MemorySuite) TestMemoryQueueRunsJobsEnqueuedBeforeRegister(c *check.C) {
+	q, err := Queue()
+	c.Assert(err, check.IsNil)
+	task := &testTask{}
+	j, err := q.Enqueue(task.Name(), nil)
+	c.Assert(err, check.IsNil)
+	c.Assert(j.Status().State, check.Equals, monsterqueue.JobStateEnqueued)
+	err = q.RegisterTask(task)
+	c.Assert(err, check.IsNil)
+	timeout := time.After(5 * time.Second)
+	for j.Status().State != monsterqueue.JobStateDone {
+		select {
+		case <-timeout:
+			c.Fatal("timeout waiting for job to run")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	c.Assert(task.callCount, check.Equals, 1)
+}
+
+func (s *MemorySuite) TestMemoryQueueListRetrieveAndDeleteJobs(c *check.C) {
+	q, err := Queue()
+	c.Assert(err, check.IsNil)
+	j1, err := q.Enqueue("unregistered-task", nil)
+	c.Assert(err, check.IsNil)
+	j2, err := q.Enqueue("unregistered-task", nil)
+	c.Assert(err, check.IsNil)
+	jobs, err := q.ListJobs()
+	c.Assert(err, check.IsNil)
+	c.Assert(jobs, check.HasLen, 2)
+	c.Assert(jobs[0].ID(), check.Equals, j1.ID())
+	c.Assert(jobs[1].ID(), check.Equals, j2.ID())
+	j, err := q.RetrieveJob(j2.ID())
+	c.Assert(err, check.IsNil)
+	c.Assert(j.ID(), check.Equals, j2.ID())
+	err = q.DeleteJob(j1.ID())
+	c.Assert(err, check.IsNil)
+	_, err = q.RetrieveJob(j1.ID())
+	c.Assert(err, check.Equals, monsterqueue.ErrNoSuchJob)
+	jobs, err = q.ListJobs()
+	c.Assert(err, check.IsNil)
+	c.Assert(jobs, check.HasLen, 1)
+	c.Assert(jobs[0].ID(), check.Equals, j2.ID())
+}
+
+func (s *MemorySuite) TestMemoryQueueTestingWaitQueueTasks(c *check.C) {
+	q, err := Queue()
+	c.Assert(err, check.IsNil)
+	task := &testTask{}
+	err = q.RegisterTask(task)
+	c.Assert(err, check.IsNil)
+	_, err = q.Enqueue(task.Name(), nil)
+	c.Assert(err, check.IsNil)
+	err = TestingWaitQueueTasks(1, 5*time.Second)
+	c.Assert(err, check.IsNil)
+	c.Assert(task.callCount, check.Equals, 1)
+}
+
+func (s *MemorySuite) TestShutdownWaitsRunningTasks(c *check.C) {
+	q, err := Queue()
+	c.Assert(err, check.IsNil)
+	task := &blockingTask{started: make(chan struct{}), release: make(chan struct{})}
+	err = q.RegisterTask(task)
+	c.Assert(err, check.IsNil)
+	j, err := q.Enqueue(task.Name(), nil)
+	c.Assert(err, check.IsNil)
+	<-task.started
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		close(task.release)
+	}()
+	err = queueData.Shutdown(context.Background())
+	c.Assert(err, check.IsNil)
+	c.Assert(j.Status().State, check.Equals, monsterqueue.JobStateDone)
+	c.Assert(queueData.instance, check.IsNil)
+}
+
+func (s *MemorySuite) TestShutdownTimeout(c *check.C) {
+	q, err := Queue()
+	c.Assert(err, check.IsNil)
+	task := &blockingTask{started: make(chan struct{}), release: make(chan struct{})}
+	defer close(task.release)
+	err = q.RegisterTask(task)
+	c.Assert(err, check.IsNil)
+	j, err := q.Enqueue(task.Name(), nil)
+	c.Assert(err, check.IsNil)
+	<-task.started
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	err = queueData.Shutdown(ctx)
+	c.Assert(err, check.ErrorMatches, "timeout waiting for running queued tasks: context deadline exceeded")
+	c.Assert(j.Status().State, check.Equals, monsterqueue.JobStateEnqueued)
+	c.Assert(queueData.instance, check.IsNil)
+}
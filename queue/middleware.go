@@ -0,0 +1,81 @@
+// Copyright 2018 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package queue
+
+import (
+	"sync"
+	"time"
+
+	"github.com/tsuru/monsterqueue"
+)
+
+// TaskMiddleware is called around the execution of queued tasks. It must call
+// next to continue running the task.
+type TaskMiddleware func(job monsterqueue.Job, next func(monsterqueue.Job))
+
+var (
+	middlewaresMut sync.RWMutex
+	middlewares    = []TaskMiddleware{recoverTask, rateLimitJob, instrumentTask, expireJob}
+)
+
+// Use adds a middleware to every task registered after this call.
+// Middlewares are called in the order they were added.
+func Use(m TaskMiddleware) {
+	middlewaresMut.Lock()
+	defer middlewaresMut.Unlock()
+	middlewares = append(middlewares, m)
+}
+
+// middlewareQueue wraps the queue returned by drivers, applying registered
+// middlewares to tasks and counting enqueued jobs.
+type middlewareQueue struct {
+	monsterqueue.Queue
+}
+
+func (q *middlewareQueue) RegisterTask(task monsterqueue.Task) error {
+	middlewaresMut.RLock()
+	taskMiddlewares := make([]TaskMiddleware, len(middlewares))
+	copy(taskMiddlewares, middlewares)
+	middlewaresMut.RUnlock()
+	if len(taskMiddlewares) == 0 {
+		return q.Queue.RegisterTask(task)
+	}
+	return q.Queue.RegisterTask(&middlewareTask{Task: task, middlewares: taskMiddlewares})
+}
+
+func (q *middlewareQueue) Enqueue(taskName string, params monsterqueue.JobParams) (monsterqueue.Job, error) {
+	job, err := q.Queue.Enqueue(taskName, params)
+	if err == nil {
+		jobsEnqueued.WithLabelValues(taskName).Inc()
+	}
+	return job, err
+}
+
+func (q *middlewareQueue) EnqueueWait(taskName string, params monsterqueue.JobParams, timeout time.Duration) (monsterqueue.Job, error) {
+	job, err := q.Queue.EnqueueWait(taskName, params, timeout)
+	if job != nil {
+		jobsEnqueued.WithLabelValues(taskName).Inc()
+	}
+	return job, err
+}
+
+type middlewareTask struct {
+	monsterqueue.Task
+	middlewares []TaskMiddleware
+}
+
+func (t *middlewareTask) Run(job monsterqueue.Job) {
+	t.run(0, job)
+}
+
+func (t *middlewareTask) run(i int, job monsterqueue.Job) {
+	if i == len(t.middlewares) {
+		t.Task.Run(job)
+		return
+	}
+	t.middlewares[i](job, func(j monsterqueue.Job) {
+		t.run(i+1, j)
+	})
+}
@@ -0,0 +1,59 @@
+// Copyright 2018 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package queue
+
+import (
+	"time"
+
+	"github.com/tsuru/monsterqueue"
+	"gopkg.in/check.v1"
+)
+
+func (s *MemorySuite) TestUseMiddleware(c *check.C) {
+	oldMiddlewares := middlewares
+	defer func() { middlewares = oldMiddlewares }()
+	var calls []string
+	Use(func(job monsterqueue.Job, next func(monsterqueue.Job)) {
+		calls = append(calls, "first-before:"+job.TaskName())
+		next(job)
+		calls = append(calls, "first-after")
+	})
+	Use(func(job monsterqueue.Job, next func(monsterqueue.Job)) {
+		calls = append(calls, "second-before")
+		next(job)
+		calls = append(calls, "second-after")
+	})
+	q, err := Queue()
+	c.Assert(err, check.IsNil)
+	task := &testTask{}
+	err = q.RegisterTask(task)
+	c.Assert(err, check.IsNil)
+	_, err = q.EnqueueWait(task.Name(), nil, time.Minute)
+	c.Assert(err, check.IsNil)
+	q.Wait()
+	c.Assert(task.callCount, check.Equals, 1)
+	c.Assert(calls, check.DeepEquals, []string{
+		"first-before:test-task", "second-before", "second-after", "first-after",
+	})
+}
+
+func (s *MemorySuite) TestUseMiddlewareCanSkipTask(c *check.C) {
+	oldMiddlewares := middlewares
+	defer func() { middlewares = oldMiddlewares }()
+	Use(func(job monsterqueue.Job, next func(monsterqueue.Job)) {
+		job.Success("skipped")
+	})
+	q, err := Queue()
+	c.Assert(err, check.IsNil)
+	task := &testTask{}
+	err = q.RegisterTask(task)
+	c.Assert(err, check.IsNil)
+	j, err := q.EnqueueWait(task.Name(), nil, time.Minute)
+	c.Assert(err, check.IsNil)
+	result, err := j.Result()
+	c.Assert(err, check.IsNil)
+	c.Assert(result, check.Equals, "skipped")
+	c.Assert(task.callCount, check.Equals, 0)
+}
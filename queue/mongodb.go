@@ -0,0 +1,74 @@
+// Copyright 2018 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package queue
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+	"github.com/pkg/errors"
+	"github.com/tsuru/config"
+	"github.com/tsuru/monsterqueue"
+	"github.com/tsuru/monsterqueue/mongodb"
+)
+
+const mongoCollectionPrefix = "tsuru"
+
+func init() {
+	Register("mongodb", newMongoQueue)
+}
+
+func newMongoQueue() (monsterqueue.Queue, error) {
+	queueMongoURL, _ := config.GetString("queue:mongo-url")
+	if queueMongoURL == "" {
+		queueMongoURL = "localhost:27017"
+	}
+	queueMongoDB, _ := config.GetString("queue:mongo-database")
+	pollingInterval, _ := config.GetFloat("queue:mongo-polling-interval")
+	if pollingInterval == 0.0 {
+		pollingInterval = 1.0
+	}
+	conf := mongodb.QueueConfig{
+		CollectionPrefix: mongoCollectionPrefix,
+		Url:              queueMongoURL,
+		Database:         queueMongoDB,
+		PollingInterval:  time.Duration(pollingInterval * float64(time.Second)),
+	}
+	q, err := mongodb.NewQueue(conf)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create queue instance, please check queue:mongo-url and queue:mongo-database config entries. error")
+	}
+	return &mongoQueue{Queue: q, url: queueMongoURL, database: queueMongoDB}, nil
+}
+
+// mongoQueue adds to the monsterqueue mongodb driver the release of the jobs
+// running in this process, which the driver doesn't support.
+type mongoQueue struct {
+	monsterqueue.Queue
+	url      string
+	database string
+}
+
+// ReleaseRunning removes the ownership of the jobs reserved by this process
+// and not yet finished, so other processes can run them. The driver marks
+// jobs it reserves with the hostname and the pid of the process.
+func (q *mongoQueue) ReleaseRunning() error {
+	session, err := mgo.DialWithTimeout(q.url, 10*time.Second)
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+	hostname, _ := os.Hostname()
+	coll := session.DB(q.database).C(mongoCollectionPrefix + "_queue_tasks")
+	_, err = coll.UpdateAll(bson.M{
+		"owner.name":         fmt.Sprintf("%s_%d", hostname, os.Getpid()),
+		"owner.owned":        true,
+		"resultmessage.done": false,
+	}, bson.M{"$set": bson.M{"owner.owned": false}})
+	return err
+}
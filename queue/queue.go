@@ -15,8 +15,8 @@ import (
 	"github.com/pkg/errors"
 	"github.com/tsuru/config"
 	"github.com/tsuru/monsterqueue"
-	"github.com/tsuru/monsterqueue/mongodb"
 	"github.com/tsuru/tsuru/api/shutdown"
+	"github.com/tsuru/tsuru/log"
 )
 
 type queueInstanceData struct {
@@ -24,12 +24,47 @@ type queueInstanceData struct {
 	instance monsterqueue.Queue
 }
 
+// runningReleaser is implemented by queue drivers able to put the jobs still
+// running in this process back in the queue, so other processes can run them
+// instead of waiting for their reservation to expire.
+type runningReleaser interface {
+	ReleaseRunning() error
+}
+
+// Shutdown stops processing new tasks and waits for running tasks to finish
+// until the context is done. Jobs still running then are released back to the
+// queue, they may end up running twice if they finish before the process
+// exits.
 func (q *queueInstanceData) Shutdown(ctx context.Context) error {
 	q.Lock()
-	defer q.Unlock()
-	if q.instance != nil {
-		q.instance.Stop()
-		q.instance = nil
+	instance := q.instance
+	q.instance = nil
+	q.Unlock()
+	if instance == nil {
+		return nil
+	}
+	done := make(chan struct{})
+	go func() {
+		instance.Stop()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		if err := releaseRunning(instance); err != nil {
+			log.Errorf("unable to release running queued tasks: %s", err)
+		}
+		return errors.Wrap(ctx.Err(), "timeout waiting for running queued tasks")
+	}
+}
+
+func releaseRunning(q monsterqueue.Queue) error {
+	if mq, ok := q.(*middlewareQueue); ok {
+		q = mq.Queue
+	}
+	if r, ok := q.(runningReleaser); ok {
+		return r.ReleaseRunning()
 	}
 	return nil
 }
@@ -38,9 +73,28 @@ func (q *queueInstanceData) String() string {
 	return "queued tasks"
 }
 
-var queueData queueInstanceData
+const defaultDriver = "mongodb"
+
+type queueFactory func() (monsterqueue.Queue, error)
+
+var (
+	queueData      queueInstanceData
+	queueFactories = make(map[string]queueFactory)
+)
+
+// Register registers a new queue driver. The driver used by tsuru is
+// selected using the queue:driver config entry, defaulting to mongodb.
+func Register(name string, factory queueFactory) {
+	queueFactories[name] = factory
+}
+
+// Unregister removes a previously registered queue driver.
+func Unregister(name string) {
+	delete(queueFactories, name)
+}
 
 func ResetQueue() {
+	resetHealthCheckResult()
 	queueData.Lock()
 	defer queueData.Unlock()
 	if queueData.instance != nil {
@@ -79,6 +133,8 @@ func TestingWaitQueueTasks(n int, timeout time.Duration) error {
 	return nil
 }
 
+// Queue returns the queue instance, creating it using the configured driver
+// if it's not yet available.
 func Queue() (monsterqueue.Queue, error) {
 	queueData.RLock()
 	if queueData.instance != nil {
@@ -91,26 +147,23 @@ func Queue() (monsterqueue.Queue, error) {
 	if queueData.instance != nil {
 		return queueData.instance, nil
 	}
-	queueMongoURL, _ := config.GetString("queue:mongo-url")
-	if queueMongoURL == "" {
-		queueMongoURL = "localhost:27017"
+	driver, _ := config.GetString("queue:driver")
+	if driver == "" {
+		driver = defaultDriver
 	}
-	queueMongoDB, _ := config.GetString("queue:mongo-database")
-	pollingInterval, _ := config.GetFloat("queue:mongo-polling-interval")
-	if pollingInterval == 0.0 {
-		pollingInterval = 1.0
+	factory, ok := queueFactories[driver]
+	if !ok {
+		return nil, errors.Errorf("unknown queue driver: %q", driver)
 	}
-	conf := mongodb.QueueConfig{
-		CollectionPrefix: "tsuru",
-		Url:              queueMongoURL,
-		Database:         queueMongoDB,
-		PollingInterval:  time.Duration(pollingInterval * float64(time.Second)),
+	instance, err := factory()
+	if err != nil {
+		return nil, err
 	}
-	var err error
-	queueData.instance, err = mongodb.NewQueue(conf)
+	err = instance.RegisterTask(&healthCheckTask{})
 	if err != nil {
-		return nil, errors.Wrap(err, "could not create queue instance, please check queue:mongo-url and queue:mongo-database config entries. error")
+		return nil, err
 	}
+	queueData.instance = &middlewareQueue{Queue: instance}
 	shutdown.Register(&queueData)
 	go queueData.instance.ProcessLoop()
 	return queueData.instance, nil
@@ -6,6 +6,7 @@ package queue
 
 import (
 	"context"
+	"errors"
 	"io/ioutil"
 	"testing"
 	"time"
@@ -57,3 +58,44 @@ func (s *S) TestQueue(c *check.C) {
 	shutdown.Do(context.Background(), ioutil.Discard)
 	c.Assert(queueData.instance, check.IsNil)
 }
+
+func (s *S) TestShutdownTimeoutReleasesMongoJobs(c *check.C) {
+	q, err := Queue()
+	c.Assert(err, check.IsNil)
+	task := &blockingTask{started: make(chan struct{}), release: make(chan struct{})}
+	defer close(task.release)
+	err = q.RegisterTask(task)
+	c.Assert(err, check.IsNil)
+	j, err := q.Enqueue(task.Name(), nil)
+	c.Assert(err, check.IsNil)
+	<-task.started
+	mongoQ := q.(*middlewareQueue).Queue.(*mongoQueue)
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	err = queueData.Shutdown(ctx)
+	c.Assert(err, check.ErrorMatches, "timeout waiting for running queued tasks: context deadline exceeded")
+	job, err := mongoQ.RetrieveJob(j.ID())
+	c.Assert(err, check.IsNil)
+	c.Assert(job.Status().State, check.Equals, monsterqueue.JobStateEnqueued)
+}
+
+func (s *S) TestQueueUnknownDriver(c *check.C) {
+	config.Set("queue:driver", "unknown")
+	defer config.Unset("queue:driver")
+	q, err := Queue()
+	c.Assert(err, check.ErrorMatches, `unknown queue driver: "unknown"`)
+	c.Assert(q, check.IsNil)
+}
+
+func (s *S) TestQueueDriverError(c *check.C) {
+	Register("fake", func() (monsterqueue.Queue, error) {
+		return nil, errors.New("fake error")
+	})
+	defer Unregister("fake")
+	config.Set("queue:driver", "fake")
+	defer config.Unset("queue:driver")
+	q, err := Queue()
+	c.Assert(err, check.ErrorMatches, "fake error")
+	c.Assert(q, check.IsNil)
+	c.Assert(queueData.instance, check.IsNil)
+}
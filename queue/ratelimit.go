@@ -0,0 +1,46 @@
+// Copyright 2018 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package queue
+
+import (
+	"math"
+	"sync"
+
+	"github.com/juju/ratelimit"
+	"github.com/tsuru/config"
+	"github.com/tsuru/monsterqueue"
+)
+
+var taskLimiter rateLimiter
+
+// rateLimiter holds the token bucket shared by all tasks, it's recreated
+// whenever queue:tasks-per-second changes.
+type rateLimiter struct {
+	sync.Mutex
+	rate   float64
+	bucket *ratelimit.Bucket
+}
+
+func (l *rateLimiter) get() *ratelimit.Bucket {
+	rate, _ := config.GetFloat("queue:tasks-per-second")
+	l.Lock()
+	defer l.Unlock()
+	if rate <= 0 {
+		l.rate, l.bucket = 0, nil
+		return nil
+	}
+	if rate != l.rate {
+		capacity := int64(math.Max(1, math.Ceil(rate)))
+		l.rate, l.bucket = rate, ratelimit.NewBucketWithRate(rate, capacity)
+	}
+	return l.bucket
+}
+
+func rateLimitJob(job monsterqueue.Job, next func(monsterqueue.Job)) {
+	if bucket := taskLimiter.get(); bucket != nil {
+		bucket.Wait(1)
+	}
+	next(job)
+}
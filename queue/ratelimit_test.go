@@ -0,0 +1,60 @@
+// Copyright 2018 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package queue
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/tsuru/config"
+	"github.com/tsuru/monsterqueue"
+	"gopkg.in/check.v1"
+)
+
+type countingTask struct {
+	count int32
+}
+
+func (t *countingTask) Run(j monsterqueue.Job) {
+	atomic.AddInt32(&t.count, 1)
+	j.Success(nil)
+}
+
+func (t *countingTask) Name() string {
+	return "counting-task"
+}
+
+func (s *MemorySuite) TestRateLimitTasks(c *check.C) {
+	config.Set("queue:tasks-per-second", 10)
+	defer config.Unset("queue:tasks-per-second")
+	q, err := Queue()
+	c.Assert(err, check.IsNil)
+	task := &countingTask{}
+	err = q.RegisterTask(task)
+	c.Assert(err, check.IsNil)
+	start := time.Now()
+	for i := 0; i < 20; i++ {
+		_, err = q.Enqueue(task.Name(), nil)
+		c.Assert(err, check.IsNil)
+	}
+	err = TestingWaitQueueTasks(20, 10*time.Second)
+	c.Assert(err, check.IsNil)
+	c.Assert(atomic.LoadInt32(&task.count), check.Equals, int32(20))
+	c.Assert(time.Since(start) >= 900*time.Millisecond, check.Equals, true)
+}
+
+func (s *MemorySuite) TestRateLimiterDisabled(c *check.C) {
+	var l rateLimiter
+	c.Assert(l.get(), check.IsNil)
+	config.Set("queue:tasks-per-second", 2.5)
+	defer config.Unset("queue:tasks-per-second")
+	bucket := l.get()
+	c.Assert(bucket, check.NotNil)
+	c.Assert(bucket.Rate(), check.Equals, 2.5)
+	c.Assert(bucket.Capacity(), check.Equals, int64(3))
+	c.Assert(l.get(), check.Equals, bucket)
+	config.Set("queue:tasks-per-second", 0)
+	c.Assert(l.get(), check.IsNil)
+}
@@ -0,0 +1,26 @@
+// Copyright 2018 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package queue
+
+import (
+	"runtime/debug"
+
+	"github.com/pkg/errors"
+	"github.com/tsuru/monsterqueue"
+	"github.com/tsuru/tsuru/log"
+)
+
+// recoverTask prevents a panic in a queued task from crashing the tsuru
+// daemon, finishing the job with an error describing the panic instead.
+func recoverTask(job monsterqueue.Job, next func(monsterqueue.Job)) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Errorf("[queue] PANIC running job %s for task %q - %v\n%s", job.ID(), job.TaskName(), r, debug.Stack())
+			taskPanics.WithLabelValues(job.TaskName()).Inc()
+			job.Error(errors.Errorf("panic running task %q: %v", job.TaskName(), r))
+		}
+	}()
+	next(job)
+}
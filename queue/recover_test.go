@@ -0,0 +1,45 @@
+// Copyright 2018 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package queue
+
+import (
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/tsuru/monsterqueue"
+	"gopkg.in/check.v1"
+)
+
+type panickingTask struct{}
+
+func (t *panickingTask) Run(j monsterqueue.Job) {
+	panic("something went very wrong")
+}
+
+func (t *panickingTask) Name() string {
+	return "panicking-task"
+}
+
+func (s *MemorySuite) TestRecoverTask(c *check.C) {
+	taskPanics.Reset()
+	q, err := Queue()
+	c.Assert(err, check.IsNil)
+	err = q.RegisterTask(&panickingTask{})
+	c.Assert(err, check.IsNil)
+	err = q.RegisterTask(&testTask{})
+	c.Assert(err, check.IsNil)
+	j, err := q.EnqueueWait("panicking-task", nil, time.Minute)
+	c.Assert(err, check.IsNil)
+	_, err = j.Result()
+	c.Assert(err, check.ErrorMatches, `panic running task "panicking-task": something went very wrong`)
+	var dtoMetric dto.Metric
+	taskPanics.WithLabelValues("panicking-task").Write(&dtoMetric)
+	c.Assert(dtoMetric.Counter.GetValue(), check.Equals, 1.0)
+	j, err = q.EnqueueWait("test-task", nil, time.Minute)
+	c.Assert(err, check.IsNil)
+	result, err := j.Result()
+	c.Assert(err, check.IsNil)
+	c.Assert(result, check.Equals, "result")
+}
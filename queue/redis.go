@@ -0,0 +1,556 @@
+// Copyright 2018 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package queue
+
+import (
+	"encoding/json"
+	"runtime"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/globalsign/mgo/bson"
+	"github.com/pkg/errors"
+	"github.com/tsuru/config"
+	"github.com/tsuru/monsterqueue"
+	"github.com/tsuru/tsuru/log"
+	tsuruRedis "github.com/tsuru/tsuru/redis"
+	"gopkg.in/redis.v3"
+)
+
+const redisKeyPrefix = "tsuru:queue"
+
+func init() {
+	Register("redis", newRedisQueue)
+}
+
+// queueRedis is a monsterqueue.Queue implementation storing jobs in redis.
+// Each registered task has its own list of ready jobs, reserved jobs are
+// atomically moved to a processing list and are requeued if the owner stops
+// sending heartbeats for longer than the reservation timeout.
+type queueRedis struct {
+	client             tsuruRedis.Client
+	tasks              map[string]monsterqueue.Task
+	tasksMut           sync.RWMutex
+	pollingInterval    time.Duration
+	reservationTimeout time.Duration
+	pendingReserved    map[string]time.Time
+	pendingMut         sync.Mutex
+	running            map[string]string
+	runningMut         sync.Mutex
+	done               chan bool
+	wg                 sync.WaitGroup
+}
+
+func newRedisQueue() (monsterqueue.Queue, error) {
+	client, err := tsuruRedis.NewRedisDefaultConfig("queue", &tsuruRedis.CommonConfig{
+		PoolSize:    100,
+		PoolTimeout: time.Second,
+		IdleTimeout: 2 * time.Minute,
+		TryLocal:    true,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create queue instance, please check queue:redis-* config entries. error")
+	}
+	pollingInterval, _ := config.GetFloat("queue:redis-polling-interval")
+	if pollingInterval == 0.0 {
+		pollingInterval = 1.0
+	}
+	reservationTimeout, _ := config.GetFloat("queue:redis-reservation-timeout")
+	if reservationTimeout == 0.0 {
+		reservationTimeout = 60.0
+	}
+	return &queueRedis{
+		client:             client,
+		tasks:              make(map[string]monsterqueue.Task),
+		pollingInterval:    time.Duration(pollingInterval * float64(time.Second)),
+		reservationTimeout: time.Duration(reservationTimeout * float64(time.Second)),
+		pendingReserved:    make(map[string]time.Time),
+		running:            make(map[string]string),
+		done:               make(chan bool),
+	}, nil
+}
+
+func (q *queueRedis) key(name string) string {
+	return redisKeyPrefix + ":" + name
+}
+
+func (q *queueRedis) jobKey(id string) string {
+	return q.key("job:" + id)
+}
+
+func (q *queueRedis) readyKey(taskName string) string {
+	return q.key("ready:" + taskName)
+}
+
+func (q *queueRedis) RegisterTask(task monsterqueue.Task) error {
+	q.tasksMut.Lock()
+	defer q.tasksMut.Unlock()
+	if _, isRegistered := q.tasks[task.Name()]; isRegistered {
+		return errors.New("task already registered")
+	}
+	q.tasks[task.Name()] = task
+	return nil
+}
+
+func (q *queueRedis) Enqueue(taskName string, params monsterqueue.JobParams) (monsterqueue.Job, error) {
+	j := q.initialJob(taskName, params)
+	err := q.insert(j, false)
+	if err != nil {
+		return nil, err
+	}
+	return j, nil
+}
+
+func (q *queueRedis) EnqueueWait(taskName string, params monsterqueue.JobParams, timeout time.Duration) (monsterqueue.Job, error) {
+	j := q.initialJob(taskName, params)
+	err := q.insert(j, true)
+	if err != nil {
+		return nil, err
+	}
+	timeoutCh := time.After(timeout)
+	for {
+		job, err := q.getJob(j.id)
+		if err != nil {
+			log.Errorf("error trying to get job %s: %s", j.id, err)
+		} else if job.state == monsterqueue.JobStateDone {
+			return job, nil
+		}
+		select {
+		case <-timeoutCh:
+			err = q.client.HMSetMap(q.jobKey(j.id), map[string]string{"waited": "false"}).Err()
+			if err != nil {
+				return j, err
+			}
+			job, err = q.getJob(j.id)
+			if err == nil && job.state == monsterqueue.JobStateDone {
+				return job, nil
+			}
+			return j, monsterqueue.ErrQueueWaitTimeout
+		case <-time.After(200 * time.Millisecond):
+		}
+	}
+}
+
+func (q *queueRedis) ProcessLoop() {
+	for {
+		q.requeueAbandoned()
+		for q.processNext() {
+			select {
+			case <-q.done:
+				return
+			default:
+			}
+		}
+		select {
+		case <-time.After(q.pollingInterval):
+		case <-q.done:
+			return
+		}
+	}
+}
+
+func (q *queueRedis) Stop() {
+	q.done <- true
+	q.Wait()
+}
+
+func (q *queueRedis) Wait() {
+	q.wg.Wait()
+}
+
+// ReleaseRunning moves the jobs still running in this process from the
+// processing list back to their ready lists.
+func (q *queueRedis) ReleaseRunning() error {
+	q.runningMut.Lock()
+	defer q.runningMut.Unlock()
+	var lastErr error
+	for id, taskName := range q.running {
+		removed, err := q.client.LRem(q.key("processing"), 1, id).Result()
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if removed == 0 {
+			continue
+		}
+		pipe := q.client.Pipeline()
+		pipe.HMSetMap(q.jobKey(id), map[string]string{
+			"state":     monsterqueue.JobStateEnqueued,
+			"heartbeat": "",
+		})
+		pipe.RPush(q.readyKey(taskName), id)
+		_, err = pipe.Exec()
+		if err != nil {
+			lastErr = err
+		}
+	}
+	return lastErr
+}
+
+func (q *queueRedis) ResetStorage() error {
+	defer q.client.Close()
+	keys, err := q.client.Keys(redisKeyPrefix + ":*").Result()
+	if err != nil {
+		return err
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	return q.client.Del(keys...).Err()
+}
+
+func (q *queueRedis) RetrieveJob(jobId string) (monsterqueue.Job, error) {
+	job, err := q.getJob(jobId)
+	if err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+func (q *queueRedis) ListJobs() ([]monsterqueue.Job, error) {
+	ids, err := q.client.LRange(q.key("jobs"), 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+	jobs := make([]monsterqueue.Job, 0, len(ids))
+	for _, id := range ids {
+		job, err := q.getJob(id)
+		if err != nil {
+			if err == monsterqueue.ErrNoSuchJob {
+				continue
+			}
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
+
+func (q *queueRedis) DeleteJob(jobId string) error {
+	pipe := q.client.Pipeline()
+	pipe.Del(q.jobKey(jobId))
+	pipe.LRem(q.key("jobs"), 0, jobId)
+	_, err := pipe.Exec()
+	return err
+}
+
+func (q *queueRedis) initialJob(taskName string, params monsterqueue.JobParams) *redisJob {
+	buf := make([]byte, monsterqueue.StackTraceLimit)
+	buf = buf[:runtime.Stack(buf, false)]
+	return &redisJob{
+		id:       bson.NewObjectId().Hex(),
+		task:     taskName,
+		params:   params,
+		stack:    string(buf),
+		state:    monsterqueue.JobStateEnqueued,
+		enqueued: time.Now().UTC(),
+		queue:    q,
+	}
+}
+
+func (q *queueRedis) insert(j *redisJob, waited bool) error {
+	data, err := json.Marshal(j.params)
+	if err != nil {
+		return err
+	}
+	pipe := q.client.Pipeline()
+	pipe.HMSetMap(q.jobKey(j.id), map[string]string{
+		"task":     j.task,
+		"params":   string(data),
+		"stack":    j.stack,
+		"state":    j.state,
+		"enqueued": formatRedisTime(j.enqueued),
+		"waited":   strconv.FormatBool(waited),
+	})
+	pipe.RPush(q.key("jobs"), j.id)
+	pipe.LPush(q.readyKey(j.task), j.id)
+	_, err = pipe.Exec()
+	return err
+}
+
+func (q *queueRedis) getJob(id string) (*redisJob, error) {
+	data, err := q.client.HGetAllMap(q.jobKey(id)).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, monsterqueue.ErrNoSuchJob
+	}
+	job := redisJob{
+		id:       id,
+		task:     data["task"],
+		stack:    data["stack"],
+		state:    data["state"],
+		errMsg:   data["error"],
+		enqueued: parseRedisTime(data["enqueued"]),
+		started:  parseRedisTime(data["started"]),
+		finished: parseRedisTime(data["done"]),
+		queue:    q,
+	}
+	if data["params"] != "" {
+		err = json.Unmarshal([]byte(data["params"]), &job.params)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if data["result"] != "" {
+		err = json.Unmarshal([]byte(data["result"]), &job.result)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &job, nil
+}
+
+func (q *queueRedis) processNext() bool {
+	q.tasksMut.RLock()
+	tasks := make([]monsterqueue.Task, 0, len(q.tasks))
+	for _, task := range q.tasks {
+		tasks = append(tasks, task)
+	}
+	q.tasksMut.RUnlock()
+	for _, task := range tasks {
+		id, err := q.client.RPopLPush(q.readyKey(task.Name()), q.key("processing")).Result()
+		if err == redis.Nil {
+			continue
+		}
+		if err != nil {
+			log.Debugf("error getting message from queue: %s", err)
+			return false
+		}
+		q.run(task, id)
+		return true
+	}
+	return false
+}
+
+func (q *queueRedis) run(task monsterqueue.Task, id string) {
+	now := formatRedisTime(time.Now().UTC())
+	err := q.client.HMSetMap(q.jobKey(id), map[string]string{
+		"state":     monsterqueue.JobStateRunning,
+		"started":   now,
+		"heartbeat": now,
+	}).Err()
+	var job *redisJob
+	if err == nil {
+		job, err = q.getJob(id)
+	}
+	if err != nil {
+		log.Errorf("error reserving job %s: %s", id, err)
+		q.client.LRem(q.key("processing"), 0, id)
+		return
+	}
+	q.runningMut.Lock()
+	q.running[id] = task.Name()
+	q.runningMut.Unlock()
+	q.wg.Add(1)
+	go func() {
+		defer q.wg.Done()
+		defer func() {
+			q.runningMut.Lock()
+			delete(q.running, id)
+			q.runningMut.Unlock()
+		}()
+		stop := make(chan struct{})
+		go q.heartbeat(id, stop)
+		task.Run(job)
+		close(stop)
+		if job.state != monsterqueue.JobStateDone {
+			q.moveToResult(job, nil, monsterqueue.ErrNoJobResultSet)
+		}
+	}()
+}
+
+func (q *queueRedis) heartbeat(id string, stop chan struct{}) {
+	ticker := time.NewTicker(q.reservationTimeout / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			err := q.client.HMSetMap(q.jobKey(id), map[string]string{
+				"heartbeat": formatRedisTime(time.Now().UTC()),
+			}).Err()
+			if err != nil {
+				log.Errorf("error sending heartbeat for job %s: %s", id, err)
+			}
+		case <-stop:
+			return
+		}
+	}
+}
+
+// requeueAbandoned moves reserved jobs whose owners stopped sending
+// heartbeats back to their ready lists. Jobs reserved without a heartbeat yet
+// are only requeued after being seen in this state for the whole reservation
+// timeout.
+func (q *queueRedis) requeueAbandoned() {
+	q.pendingMut.Lock()
+	defer q.pendingMut.Unlock()
+	processingKey := q.key("processing")
+	ids, err := q.client.LRange(processingKey, 0, -1).Result()
+	if err != nil {
+		log.Debugf("error listing reserved jobs: %s", err)
+		return
+	}
+	now := time.Now()
+	pending := make(map[string]time.Time)
+	for _, id := range ids {
+		values, err := q.client.HMGet(q.jobKey(id), "task", "heartbeat").Result()
+		if err != nil {
+			log.Debugf("error getting reserved job %s: %s", id, err)
+			continue
+		}
+		taskName, _ := values[0].(string)
+		heartbeat, _ := values[1].(string)
+		lastSeen := parseRedisTime(heartbeat)
+		if heartbeat == "" {
+			firstSeen, ok := q.pendingReserved[id]
+			if !ok {
+				firstSeen = now
+			}
+			pending[id] = firstSeen
+			lastSeen = firstSeen
+		}
+		if taskName != "" && now.Sub(lastSeen) < q.reservationTimeout {
+			continue
+		}
+		delete(pending, id)
+		removed, err := q.client.LRem(processingKey, 1, id).Result()
+		if err != nil || removed == 0 || taskName == "" {
+			continue
+		}
+		log.Errorf("requeuing abandoned job %s for task %q", id, taskName)
+		pipe := q.client.Pipeline()
+		pipe.HMSetMap(q.jobKey(id), map[string]string{
+			"state":     monsterqueue.JobStateEnqueued,
+			"heartbeat": "",
+		})
+		pipe.RPush(q.readyKey(taskName), id)
+		_, err = pipe.Exec()
+		if err != nil {
+			log.Errorf("error requeuing job %s: %s", id, err)
+		}
+	}
+	q.pendingReserved = pending
+}
+
+func (q *queueRedis) moveToResult(job *redisJob, result monsterqueue.JobResult, jobErr error) error {
+	job.state = monsterqueue.JobStateDone
+	job.finished = time.Now().UTC()
+	job.result = result
+	job.errMsg = ""
+	if jobErr != nil {
+		job.errMsg = jobErr.Error()
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	pipe := q.client.Pipeline()
+	pipe.HMSetMap(q.jobKey(job.id), map[string]string{
+		"state":  job.state,
+		"done":   formatRedisTime(job.finished),
+		"result": string(data),
+		"error":  job.errMsg,
+	})
+	pipe.LRem(q.key("processing"), 0, job.id)
+	_, err = pipe.Exec()
+	return err
+}
+
+func (q *queueRedis) publishResult(job *redisJob) (bool, error) {
+	values, err := q.client.HMGet(q.jobKey(job.id), "waited").Result()
+	if err != nil {
+		return false, err
+	}
+	if waited, _ := values[0].(string); waited != "true" {
+		return false, nil
+	}
+	err = q.client.HMSetMap(q.jobKey(job.id), map[string]string{"waited": "false"}).Err()
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+type redisJob struct {
+	id       string
+	task     string
+	params   monsterqueue.JobParams
+	stack    string
+	state    string
+	result   monsterqueue.JobResult
+	errMsg   string
+	enqueued time.Time
+	started  time.Time
+	finished time.Time
+	queue    *queueRedis
+}
+
+func (j *redisJob) ID() string {
+	return j.id
+}
+
+func (j *redisJob) Parameters() monsterqueue.JobParams {
+	return j.params
+}
+
+func (j *redisJob) TaskName() string {
+	return j.task
+}
+
+func (j *redisJob) Queue() monsterqueue.Queue {
+	return j.queue
+}
+
+func (j *redisJob) EnqueueStack() string {
+	return j.stack
+}
+
+func (j *redisJob) Status() monsterqueue.JobStatus {
+	return monsterqueue.JobStatus{
+		State:    j.state,
+		Enqueued: j.enqueued,
+		Started:  j.started,
+		Done:     j.finished,
+	}
+}
+
+func (j *redisJob) Success(result monsterqueue.JobResult) (bool, error) {
+	err := j.queue.moveToResult(j, result, nil)
+	if err != nil {
+		return false, err
+	}
+	return j.queue.publishResult(j)
+}
+
+func (j *redisJob) Error(jobErr error) (bool, error) {
+	err := j.queue.moveToResult(j, nil, jobErr)
+	if err != nil {
+		return false, err
+	}
+	return j.queue.publishResult(j)
+}
+
+func (j *redisJob) Result() (monsterqueue.JobResult, error) {
+	if j.state != monsterqueue.JobStateDone {
+		return nil, monsterqueue.ErrNoJobResult
+	}
+	var err error
+	if j.errMsg != "" {
+		err = errors.New(j.errMsg)
+	}
+	return j.result, err
+}
+
+func formatRedisTime(t time.Time) string {
+	return t.Format(time.RFC3339Nano)
+}
+
+func parseRedisTime(value string) time.Time {
+	t, _ := time.Parse(time.RFC3339Nano, value)
+	return t
+}
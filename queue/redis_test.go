@@ -0,0 +1,176 @@
+// Copyright 2018 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package queue
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/tsuru/config"
+	"github.com/tsuru/monsterqueue"
+	"gopkg.in/check.v1"
+)
+
+type RedisSuite struct{}
+
+var _ = check.Suite(&RedisSuite{})
+
+func (s *RedisSuite) SetUpTest(c *check.C) {
+	config.Set("queue:driver", "redis")
+	config.Set("queue:redis-polling-interval", 0.01)
+	ResetQueue()
+}
+
+func (s *RedisSuite) TearDownTest(c *check.C) {
+	ResetQueue()
+	config.Unset("queue:driver")
+	config.Unset("queue:redis-polling-interval")
+}
+
+type failingTask struct{}
+
+func (t *failingTask) Run(j monsterqueue.Job) {
+	j.Error(errors.New("something went wrong"))
+}
+
+func (t *failingTask) Name() string {
+	return "failing-task"
+}
+
+func (s *RedisSuite) TestRedisQueue(c *check.C) {
+	q, err := Queue()
+	c.Assert(err, check.IsNil)
+	c.Assert(q.(*middlewareQueue).Queue, check.FitsTypeOf, &queueRedis{})
+	task := &testTask{}
+	err = q.RegisterTask(task)
+	c.Assert(err, check.IsNil)
+	j, err := q.EnqueueWait(task.Name(), monsterqueue.JobParams{"app": "myapp"}, time.Minute)
+	c.Assert(err, check.IsNil)
+	result, err := j.Result()
+	c.Assert(err, check.IsNil)
+	c.Assert(result, check.Equals, "result")
+	c.Assert(j.Parameters(), check.DeepEquals, monsterqueue.JobParams{"app": "myapp"})
+	c.Assert(j.Status().State, check.Equals, monsterqueue.JobStateDone)
+	c.Assert(task.callCount, check.Equals, 1)
+}
+
+func (s *RedisSuite) TestRedisQueueJobError(c *check.C) {
+	q, err := Queue()
+	c.Assert(err, check.IsNil)
+	task := &failingTask{}
+	err = q.RegisterTask(task)
+	c.Assert(err, check.IsNil)
+	j, err := q.EnqueueWait(task.Name(), nil, time.Minute)
+	c.Assert(err, check.IsNil)
+	_, err = j.Result()
+	c.Assert(err, check.ErrorMatches, "something went wrong")
+}
+
+func (s *RedisSuite) TestRedisQueueRegisterTwice(c *check.C) {
+	q, err := Queue()
+	c.Assert(err, check.IsNil)
+	err = q.RegisterTask(&testTask{})
+	c.Assert(err, check.IsNil)
+	err = q.RegisterTask(&testTask{})
+	c.Assert(err, check.ErrorMatches, "task already registered")
+}
+
+func (s *RedisSuite) TestRedisQueueListRetrieveAndDeleteJobs(c *check.C) {
+	q, err := Queue()
+	c.Assert(err, check.IsNil)
+	j1, err := q.Enqueue("unregistered-task", monsterqueue.JobParams{"a": "b"})
+	c.Assert(err, check.IsNil)
+	j2, err := q.Enqueue("unregistered-task", nil)
+	c.Assert(err, check.IsNil)
+	jobs, err := q.ListJobs()
+	c.Assert(err, check.IsNil)
+	c.Assert(jobs, check.HasLen, 2)
+	c.Assert(jobs[0].ID(), check.Equals, j1.ID())
+	c.Assert(jobs[0].Status().State, check.Equals, monsterqueue.JobStateEnqueued)
+	c.Assert(jobs[1].ID(), check.Equals, j2.ID())
+	j, err := q.RetrieveJob(j1.ID())
+	c.Assert(err, check.IsNil)
+	c.Assert(j.TaskName(), check.Equals, "unregistered-task")
+	c.Assert(j.Parameters(), check.DeepEquals, monsterqueue.JobParams{"a": "b"})
+	err = q.DeleteJob(j1.ID())
+	c.Assert(err, check.IsNil)
+	_, err = q.RetrieveJob(j1.ID())
+	c.Assert(err, check.Equals, monsterqueue.ErrNoSuchJob)
+	jobs, err = q.ListJobs()
+	c.Assert(err, check.IsNil)
+	c.Assert(jobs, check.HasLen, 1)
+	c.Assert(jobs[0].ID(), check.Equals, j2.ID())
+}
+
+func (s *RedisSuite) TestRedisQueueRequeueAbandoned(c *check.C) {
+	q, err := Queue()
+	c.Assert(err, check.IsNil)
+	redisQ := q.(*middlewareQueue).Queue.(*queueRedis)
+	j, err := q.Enqueue("other-task", nil)
+	c.Assert(err, check.IsNil)
+	id, err := redisQ.client.RPopLPush(redisQ.readyKey("other-task"), redisQ.key("processing")).Result()
+	c.Assert(err, check.IsNil)
+	c.Assert(id, check.Equals, j.ID())
+	err = redisQ.client.HMSetMap(redisQ.jobKey(id), map[string]string{
+		"state":     monsterqueue.JobStateRunning,
+		"heartbeat": formatRedisTime(time.Now().Add(-2 * redisQ.reservationTimeout)),
+	}).Err()
+	c.Assert(err, check.IsNil)
+	redisQ.requeueAbandoned()
+	processing, err := redisQ.client.LRange(redisQ.key("processing"), 0, -1).Result()
+	c.Assert(err, check.IsNil)
+	c.Assert(processing, check.HasLen, 0)
+	ready, err := redisQ.client.LRange(redisQ.readyKey("other-task"), 0, -1).Result()
+	c.Assert(err, check.IsNil)
+	c.Assert(ready, check.DeepEquals, []string{j.ID()})
+	job, err := q.RetrieveJob(j.ID())
+	c.Assert(err, check.IsNil)
+	c.Assert(job.Status().State, check.Equals, monsterqueue.JobStateEnqueued)
+}
+
+func (s *RedisSuite) TestRedisQueueKeepsReservedJobsWithHeartbeat(c *check.C) {
+	q, err := Queue()
+	c.Assert(err, check.IsNil)
+	redisQ := q.(*middlewareQueue).Queue.(*queueRedis)
+	j, err := q.Enqueue("other-task", nil)
+	c.Assert(err, check.IsNil)
+	_, err = redisQ.client.RPopLPush(redisQ.readyKey("other-task"), redisQ.key("processing")).Result()
+	c.Assert(err, check.IsNil)
+	err = redisQ.client.HMSetMap(redisQ.jobKey(j.ID()), map[string]string{
+		"heartbeat": formatRedisTime(time.Now().UTC()),
+	}).Err()
+	c.Assert(err, check.IsNil)
+	redisQ.requeueAbandoned()
+	processing, err := redisQ.client.LRange(redisQ.key("processing"), 0, -1).Result()
+	c.Assert(err, check.IsNil)
+	c.Assert(processing, check.DeepEquals, []string{j.ID()})
+}
+
+func (s *RedisSuite) TestRedisShutdownTimeoutReleasesRunningJobs(c *check.C) {
+	q, err := Queue()
+	c.Assert(err, check.IsNil)
+	redisQ := q.(*middlewareQueue).Queue.(*queueRedis)
+	task := &blockingTask{started: make(chan struct{}), release: make(chan struct{})}
+	defer close(task.release)
+	err = q.RegisterTask(task)
+	c.Assert(err, check.IsNil)
+	j, err := q.Enqueue(task.Name(), nil)
+	c.Assert(err, check.IsNil)
+	<-task.started
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	err = queueData.Shutdown(ctx)
+	c.Assert(err, check.ErrorMatches, "timeout waiting for running queued tasks: context deadline exceeded")
+	processing, err := redisQ.client.LRange(redisQ.key("processing"), 0, -1).Result()
+	c.Assert(err, check.IsNil)
+	c.Assert(processing, check.HasLen, 0)
+	ready, err := redisQ.client.LRange(redisQ.readyKey(task.Name()), 0, -1).Result()
+	c.Assert(err, check.IsNil)
+	c.Assert(ready, check.DeepEquals, []string{j.ID()})
+	job, err := redisQ.RetrieveJob(j.ID())
+	c.Assert(err, check.IsNil)
+	c.Assert(job.Status().State, check.Equals, monsterqueue.JobStateEnqueued)
+}
@@ -0,0 +1,60 @@
+// Copyright 2018 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package queue
+
+import (
+	"sort"
+
+	"github.com/tsuru/monsterqueue"
+)
+
+// TaskStats holds the number of jobs in each state for a task.
+type TaskStats struct {
+	Task     string
+	Enqueued int
+	Running  int
+	Done     int
+	Failed   int
+}
+
+// Stats returns the number of jobs in each state for every task with jobs in
+// the queue, sorted by task name. Failed jobs are also counted as done.
+func Stats() ([]TaskStats, error) {
+	q, err := Queue()
+	if err != nil {
+		return nil, err
+	}
+	jobs, err := q.ListJobs()
+	if err != nil {
+		return nil, err
+	}
+	statsMap := make(map[string]*TaskStats)
+	for _, j := range jobs {
+		stats, ok := statsMap[j.TaskName()]
+		if !ok {
+			stats = &TaskStats{Task: j.TaskName()}
+			statsMap[j.TaskName()] = stats
+		}
+		switch j.Status().State {
+		case monsterqueue.JobStateEnqueued:
+			stats.Enqueued++
+		case monsterqueue.JobStateRunning:
+			stats.Running++
+		case monsterqueue.JobStateDone:
+			stats.Done++
+			if _, err := j.Result(); err != nil {
+				stats.Failed++
+			}
+		}
+	}
+	result := make([]TaskStats, 0, len(statsMap))
+	for _, stats := range statsMap {
+		result = append(result, *stats)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Task < result[j].Task
+	})
+	return result, nil
+}
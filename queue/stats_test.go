@@ -0,0 +1,42 @@
+// Copyright 2018 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package queue
+
+import (
+	"time"
+
+	"gopkg.in/check.v1"
+)
+
+func (s *MemorySuite) TestStats(c *check.C) {
+	q, err := Queue()
+	c.Assert(err, check.IsNil)
+	task := &testTask{}
+	err = q.RegisterTask(task)
+	c.Assert(err, check.IsNil)
+	err = q.RegisterTask(&failingTask{})
+	c.Assert(err, check.IsNil)
+	_, err = q.EnqueueWait(task.Name(), nil, time.Minute)
+	c.Assert(err, check.IsNil)
+	_, err = q.EnqueueWait("failing-task", nil, time.Minute)
+	c.Assert(err, check.IsNil)
+	_, err = q.Enqueue("unregistered-task", nil)
+	c.Assert(err, check.IsNil)
+	_, err = q.Enqueue("unregistered-task", nil)
+	c.Assert(err, check.IsNil)
+	stats, err := Stats()
+	c.Assert(err, check.IsNil)
+	c.Assert(stats, check.DeepEquals, []TaskStats{
+		{Task: "failing-task", Done: 1, Failed: 1},
+		{Task: "test-task", Done: 1},
+		{Task: "unregistered-task", Enqueued: 2},
+	})
+}
+
+func (s *MemorySuite) TestStatsEmptyQueue(c *check.C) {
+	stats, err := Stats()
+	c.Assert(err, check.IsNil)
+	c.Assert(stats, check.HasLen, 0)
+}
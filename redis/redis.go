@@ -22,6 +22,8 @@ var (
 type baseClient interface {
 	Exists(key string) *redis.BoolCmd
 	RPush(key string, values ...string) *redis.IntCmd
+	LPush(key string, values ...string) *redis.IntCmd
+	RPopLPush(source, destination string) *redis.StringCmd
 	Set(key string, value interface{}, expiration time.Duration) *redis.StatusCmd
 	Get(key string) *redis.StringCmd
 	Del(keys ...string) *redis.IntCmd
@@ -34,6 +36,7 @@ type baseClient interface {
 	LLen(key string) *redis.IntCmd
 	HMGet(key string, fields ...string) *redis.SliceCmd
 	HMSetMap(key string, fields map[string]string) *redis.StatusCmd
+	HGetAllMap(key string) *redis.StringStringMapCmd
 	HLen(key string) *redis.IntCmd
 	Close() error
 }
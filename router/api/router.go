@@ -14,6 +14,7 @@ import (
 	"net/url"
 
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/tsuru/config"
@@ -36,6 +37,7 @@ var (
 	_ router.CustomHealthcheckRouter = &apiRouterWithHealthcheckSupport{}
 	_ router.InfoRouter              = &apiRouterWithInfo{}
 	_ router.StatusRouter            = &apiRouterWithStatus{}
+	_ router.TrafficRouter           = &apiRouterWithTraffic{}
 )
 
 type apiRouter struct {
@@ -56,6 +58,8 @@ type apiRouterWithInfo struct{ *apiRouter }
 
 type apiRouterWithStatus struct{ *apiRouter }
 
+type apiRouterWithTraffic struct{ *apiRouter }
+
 type routesReq struct {
 	Addresses []string `json:"addresses"`
 }
@@ -83,6 +87,10 @@ type statusResp struct {
 	Detail string               `json:"detail"`
 }
 
+type trafficResp struct {
+	LastRequest time.Time `json:"lastRequest"`
+}
+
 type capability string
 
 var (
@@ -91,8 +99,9 @@ var (
 	capHealthcheck = capability("healthcheck")
 	capInfo        = capability("info")
 	capStatus      = capability("status")
+	capTraffic     = capability("traffic")
 
-	allCaps = []capability{capCName, capTLS, capHealthcheck, capInfo, capStatus}
+	allCaps = []capability{capCName, capTLS, capHealthcheck, capInfo, capStatus, capTraffic}
 )
 
 func init() {
@@ -487,6 +496,26 @@ func (r *apiRouterWithStatus) GetBackendStatus(name string) (router.BackendStatu
 	return status.Status, status.Detail, nil
 }
 
+func (r *apiRouterWithTraffic) LastRequest(name string) (time.Time, error) {
+	backendName, err := router.Retrieve(name)
+	if err != nil {
+		return time.Time{}, err
+	}
+	data, code, err := r.do(http.MethodGet, fmt.Sprintf("backend/%s/traffic", backendName), nil)
+	if code == http.StatusNotFound {
+		return time.Time{}, router.ErrBackendNotFound
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	var traffic trafficResp
+	err = json.Unmarshal(data, &traffic)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return traffic.LastRequest, nil
+}
+
 func addDefaultOpts(app router.App, opts map[string]string) map[string]interface{} {
 	mergedOpts := make(map[string]interface{})
 	for k, v := range opts {
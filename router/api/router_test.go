@@ -11,6 +11,7 @@ import (
 	"net/http"
 	"strings"
 	"testing"
+	"time"
 
 	"net/url"
 
@@ -350,6 +351,19 @@ func (s *S) TestHealcheckBackendNotFound(c *check.C) {
 	c.Assert(err, check.DeepEquals, router.ErrBackendNotFound)
 }
 
+func (s *S) TestLastRequest(c *check.C) {
+	trafficRouter := &apiRouterWithTraffic{s.testRouter}
+	last, err := trafficRouter.LastRequest("mybackend")
+	c.Assert(err, check.IsNil)
+	c.Assert(last.Equal(time.Date(2018, 5, 4, 10, 30, 0, 0, time.UTC)), check.Equals, true)
+}
+
+func (s *S) TestLastRequestBackendNotFound(c *check.C) {
+	trafficRouter := &apiRouterWithTraffic{s.testRouter}
+	_, err := trafficRouter.LastRequest("invalid")
+	c.Assert(err, check.DeepEquals, router.ErrBackendNotFound)
+}
+
 func (s *S) TestCreateRouterSupport(c *check.C) {
 	tt := []struct {
 		features    map[string]bool
@@ -431,6 +445,7 @@ func newFakeRouter(c *check.C) *fakeRouterAPI {
 	r.HandleFunc("/backend/{name}/certificate/{cname}", api.addCertificate).Methods(http.MethodPut)
 	r.HandleFunc("/backend/{name}/certificate/{cname}", api.removeCertificate).Methods(http.MethodDelete)
 	r.HandleFunc("/backend/{name}/status", api.getStatusBackend).Methods(http.MethodGet)
+	r.HandleFunc("/backend/{name}/traffic", api.getTrafficBackend).Methods(http.MethodGet)
 	r.HandleFunc("/info", api.getInfo).Methods(http.MethodGet)
 
 	listener, err := net.Listen("tcp", "127.0.0.1:0")
@@ -470,6 +485,16 @@ func (f *fakeRouterAPI) getStatusBackend(w http.ResponseWriter, r *http.Request)
 	w.Write([]byte(`{"status": "ready", "detail": "anaander"}`))
 }
 
+func (f *fakeRouterAPI) getTrafficBackend(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	if _, ok := f.backends[vars["name"]]; !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(`{"lastRequest": "2018-05-04T10:30:00Z"}`))
+}
+
 func (f *fakeRouterAPI) getBackend(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	name := vars["name"]
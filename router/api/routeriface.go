@@ -1,7 +1,7 @@
 // AUTOMATICALLY GENERATED FILE - DO NOT EDIT!
 // Please run 'go generate' to update this file.
 //
-// Copyright 2017 tsuru authors. All rights reserved.
+// Copyright 2018 tsuru authors. All rights reserved.
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
@@ -17,8 +17,9 @@ func toSupportedInterface(base *apiRouter, supports map[capability]bool) router.
 	apiRouterWithInfoInst := &apiRouterWithInfo{base}
 	apiRouterWithStatusInst := &apiRouterWithStatus{base}
 	apiRouterWithTLSSupportInst := &apiRouterWithTLSSupport{base}
+	apiRouterWithTrafficInst := &apiRouterWithTraffic{base}
 
-	if !supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["status"] && !supports["tls"] {
+	if !supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["status"] && !supports["tls"] && !supports["traffic"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
@@ -27,7 +28,7 @@ func toSupportedInterface(base *apiRouter, supports map[capability]bool) router.
 			base,
 		}
 	}
-	if supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["status"] && !supports["tls"] {
+	if supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["status"] && !supports["tls"] && !supports["traffic"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
@@ -38,7 +39,7 @@ func toSupportedInterface(base *apiRouter, supports map[capability]bool) router.
 			apiRouterWithCnameSupportInst,
 		}
 	}
-	if !supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["status"] && !supports["tls"] {
+	if !supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["status"] && !supports["tls"] && !supports["traffic"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
@@ -49,7 +50,7 @@ func toSupportedInterface(base *apiRouter, supports map[capability]bool) router.
 			apiRouterWithHealthcheckSupportInst,
 		}
 	}
-	if supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["status"] && !supports["tls"] {
+	if supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["status"] && !supports["tls"] && !supports["traffic"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
@@ -62,7 +63,7 @@ func toSupportedInterface(base *apiRouter, supports map[capability]bool) router.
 			apiRouterWithHealthcheckSupportInst,
 		}
 	}
-	if !supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["status"] && !supports["tls"] {
+	if !supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["status"] && !supports["tls"] && !supports["traffic"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
@@ -73,7 +74,7 @@ func toSupportedInterface(base *apiRouter, supports map[capability]bool) router.
 			apiRouterWithInfoInst,
 		}
 	}
-	if supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["status"] && !supports["tls"] {
+	if supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["status"] && !supports["tls"] && !supports["traffic"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
@@ -86,7 +87,7 @@ func toSupportedInterface(base *apiRouter, supports map[capability]bool) router.
 			apiRouterWithInfoInst,
 		}
 	}
-	if !supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["status"] && !supports["tls"] {
+	if !supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["status"] && !supports["tls"] && !supports["traffic"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
@@ -99,7 +100,7 @@ func toSupportedInterface(base *apiRouter, supports map[capability]bool) router.
 			apiRouterWithInfoInst,
 		}
 	}
-	if supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["status"] && !supports["tls"] {
+	if supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["status"] && !supports["tls"] && !supports["traffic"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
@@ -114,7 +115,7 @@ func toSupportedInterface(base *apiRouter, supports map[capability]bool) router.
 			apiRouterWithInfoInst,
 		}
 	}
-	if !supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["status"] && !supports["tls"] {
+	if !supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["status"] && !supports["tls"] && !supports["traffic"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
@@ -125,7 +126,7 @@ func toSupportedInterface(base *apiRouter, supports map[capability]bool) router.
 			apiRouterWithStatusInst,
 		}
 	}
-	if supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["status"] && !supports["tls"] {
+	if supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["status"] && !supports["tls"] && !supports["traffic"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
@@ -138,7 +139,7 @@ func toSupportedInterface(base *apiRouter, supports map[capability]bool) router.
 			apiRouterWithStatusInst,
 		}
 	}
-	if !supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["status"] && !supports["tls"] {
+	if !supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["status"] && !supports["tls"] && !supports["traffic"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
@@ -151,7 +152,7 @@ func toSupportedInterface(base *apiRouter, supports map[capability]bool) router.
 			apiRouterWithStatusInst,
 		}
 	}
-	if supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["status"] && !supports["tls"] {
+	if supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["status"] && !supports["tls"] && !supports["traffic"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
@@ -166,7 +167,7 @@ func toSupportedInterface(base *apiRouter, supports map[capability]bool) router.
 			apiRouterWithStatusInst,
 		}
 	}
-	if !supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["status"] && !supports["tls"] {
+	if !supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["status"] && !supports["tls"] && !supports["traffic"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
@@ -179,7 +180,7 @@ func toSupportedInterface(base *apiRouter, supports map[capability]bool) router.
 			apiRouterWithStatusInst,
 		}
 	}
-	if supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["status"] && !supports["tls"] {
+	if supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["status"] && !supports["tls"] && !supports["traffic"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
@@ -194,7 +195,7 @@ func toSupportedInterface(base *apiRouter, supports map[capability]bool) router.
 			apiRouterWithStatusInst,
 		}
 	}
-	if !supports["cname"] && supports["healthcheck"] && supports["info"] && supports["status"] && !supports["tls"] {
+	if !supports["cname"] && supports["healthcheck"] && supports["info"] && supports["status"] && !supports["tls"] && !supports["traffic"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
@@ -209,7 +210,7 @@ func toSupportedInterface(base *apiRouter, supports map[capability]bool) router.
 			apiRouterWithStatusInst,
 		}
 	}
-	if supports["cname"] && supports["healthcheck"] && supports["info"] && supports["status"] && !supports["tls"] {
+	if supports["cname"] && supports["healthcheck"] && supports["info"] && supports["status"] && !supports["tls"] && !supports["traffic"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
@@ -226,7 +227,7 @@ func toSupportedInterface(base *apiRouter, supports map[capability]bool) router.
 			apiRouterWithStatusInst,
 		}
 	}
-	if !supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["status"] && supports["tls"] {
+	if !supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["status"] && supports["tls"] && !supports["traffic"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
@@ -237,7 +238,7 @@ func toSupportedInterface(base *apiRouter, supports map[capability]bool) router.
 			apiRouterWithTLSSupportInst,
 		}
 	}
-	if supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["status"] && supports["tls"] {
+	if supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["status"] && supports["tls"] && !supports["traffic"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
@@ -250,7 +251,7 @@ func toSupportedInterface(base *apiRouter, supports map[capability]bool) router.
 			apiRouterWithTLSSupportInst,
 		}
 	}
-	if !supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["status"] && supports["tls"] {
+	if !supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["status"] && supports["tls"] && !supports["traffic"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
@@ -263,7 +264,7 @@ func toSupportedInterface(base *apiRouter, supports map[capability]bool) router.
 			apiRouterWithTLSSupportInst,
 		}
 	}
-	if supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["status"] && supports["tls"] {
+	if supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["status"] && supports["tls"] && !supports["traffic"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
@@ -278,7 +279,7 @@ func toSupportedInterface(base *apiRouter, supports map[capability]bool) router.
 			apiRouterWithTLSSupportInst,
 		}
 	}
-	if !supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["status"] && supports["tls"] {
+	if !supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["status"] && supports["tls"] && !supports["traffic"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
@@ -291,7 +292,7 @@ func toSupportedInterface(base *apiRouter, supports map[capability]bool) router.
 			apiRouterWithTLSSupportInst,
 		}
 	}
-	if supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["status"] && supports["tls"] {
+	if supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["status"] && supports["tls"] && !supports["traffic"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
@@ -306,7 +307,7 @@ func toSupportedInterface(base *apiRouter, supports map[capability]bool) router.
 			apiRouterWithTLSSupportInst,
 		}
 	}
-	if !supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["status"] && supports["tls"] {
+	if !supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["status"] && supports["tls"] && !supports["traffic"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
@@ -321,7 +322,7 @@ func toSupportedInterface(base *apiRouter, supports map[capability]bool) router.
 			apiRouterWithTLSSupportInst,
 		}
 	}
-	if supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["status"] && supports["tls"] {
+	if supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["status"] && supports["tls"] && !supports["traffic"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
@@ -338,7 +339,7 @@ func toSupportedInterface(base *apiRouter, supports map[capability]bool) router.
 			apiRouterWithTLSSupportInst,
 		}
 	}
-	if !supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["status"] && supports["tls"] {
+	if !supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["status"] && supports["tls"] && !supports["traffic"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
@@ -351,7 +352,7 @@ func toSupportedInterface(base *apiRouter, supports map[capability]bool) router.
 			apiRouterWithTLSSupportInst,
 		}
 	}
-	if supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["status"] && supports["tls"] {
+	if supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["status"] && supports["tls"] && !supports["traffic"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
@@ -366,7 +367,7 @@ func toSupportedInterface(base *apiRouter, supports map[capability]bool) router.
 			apiRouterWithTLSSupportInst,
 		}
 	}
-	if !supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["status"] && supports["tls"] {
+	if !supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["status"] && supports["tls"] && !supports["traffic"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
@@ -381,7 +382,7 @@ func toSupportedInterface(base *apiRouter, supports map[capability]bool) router.
 			apiRouterWithTLSSupportInst,
 		}
 	}
-	if supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["status"] && supports["tls"] {
+	if supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["status"] && supports["tls"] && !supports["traffic"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
@@ -398,7 +399,7 @@ func toSupportedInterface(base *apiRouter, supports map[capability]bool) router.
 			apiRouterWithTLSSupportInst,
 		}
 	}
-	if !supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["status"] && supports["tls"] {
+	if !supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["status"] && supports["tls"] && !supports["traffic"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
@@ -413,7 +414,7 @@ func toSupportedInterface(base *apiRouter, supports map[capability]bool) router.
 			apiRouterWithTLSSupportInst,
 		}
 	}
-	if supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["status"] && supports["tls"] {
+	if supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["status"] && supports["tls"] && !supports["traffic"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
@@ -430,7 +431,7 @@ func toSupportedInterface(base *apiRouter, supports map[capability]bool) router.
 			apiRouterWithTLSSupportInst,
 		}
 	}
-	if !supports["cname"] && supports["healthcheck"] && supports["info"] && supports["status"] && supports["tls"] {
+	if !supports["cname"] && supports["healthcheck"] && supports["info"] && supports["status"] && supports["tls"] && !supports["traffic"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
@@ -447,7 +448,7 @@ func toSupportedInterface(base *apiRouter, supports map[capability]bool) router.
 			apiRouterWithTLSSupportInst,
 		}
 	}
-	if supports["cname"] && supports["healthcheck"] && supports["info"] && supports["status"] && supports["tls"] {
+	if supports["cname"] && supports["healthcheck"] && supports["info"] && supports["status"] && supports["tls"] && !supports["traffic"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
@@ -466,5 +467,517 @@ func toSupportedInterface(base *apiRouter, supports map[capability]bool) router.
 			apiRouterWithTLSSupportInst,
 		}
 	}
+	if !supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["status"] && !supports["tls"] && supports["traffic"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.TrafficRouter
+		}{
+			base,
+			base,
+			apiRouterWithTrafficInst,
+		}
+	}
+	if supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["status"] && !supports["tls"] && supports["traffic"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.TrafficRouter
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithTrafficInst,
+		}
+	}
+	if !supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["status"] && !supports["tls"] && supports["traffic"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CustomHealthcheckRouter
+			router.TrafficRouter
+		}{
+			base,
+			base,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithTrafficInst,
+		}
+	}
+	if supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["status"] && !supports["tls"] && supports["traffic"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.TrafficRouter
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithTrafficInst,
+		}
+	}
+	if !supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["status"] && !supports["tls"] && supports["traffic"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.InfoRouter
+			router.TrafficRouter
+		}{
+			base,
+			base,
+			apiRouterWithInfoInst,
+			apiRouterWithTrafficInst,
+		}
+	}
+	if supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["status"] && !supports["tls"] && supports["traffic"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.InfoRouter
+			router.TrafficRouter
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithTrafficInst,
+		}
+	}
+	if !supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["status"] && !supports["tls"] && supports["traffic"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.TrafficRouter
+		}{
+			base,
+			base,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithTrafficInst,
+		}
+	}
+	if supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["status"] && !supports["tls"] && supports["traffic"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.TrafficRouter
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithTrafficInst,
+		}
+	}
+	if !supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["status"] && !supports["tls"] && supports["traffic"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.StatusRouter
+			router.TrafficRouter
+		}{
+			base,
+			base,
+			apiRouterWithStatusInst,
+			apiRouterWithTrafficInst,
+		}
+	}
+	if supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["status"] && !supports["tls"] && supports["traffic"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.StatusRouter
+			router.TrafficRouter
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTrafficInst,
+		}
+	}
+	if !supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["status"] && !supports["tls"] && supports["traffic"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CustomHealthcheckRouter
+			router.StatusRouter
+			router.TrafficRouter
+		}{
+			base,
+			base,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTrafficInst,
+		}
+	}
+	if supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["status"] && !supports["tls"] && supports["traffic"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.StatusRouter
+			router.TrafficRouter
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTrafficInst,
+		}
+	}
+	if !supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["status"] && !supports["tls"] && supports["traffic"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.InfoRouter
+			router.StatusRouter
+			router.TrafficRouter
+		}{
+			base,
+			base,
+			apiRouterWithInfoInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTrafficInst,
+		}
+	}
+	if supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["status"] && !supports["tls"] && supports["traffic"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.InfoRouter
+			router.StatusRouter
+			router.TrafficRouter
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTrafficInst,
+		}
+	}
+	if !supports["cname"] && supports["healthcheck"] && supports["info"] && supports["status"] && !supports["tls"] && supports["traffic"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.StatusRouter
+			router.TrafficRouter
+		}{
+			base,
+			base,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTrafficInst,
+		}
+	}
+	if supports["cname"] && supports["healthcheck"] && supports["info"] && supports["status"] && !supports["tls"] && supports["traffic"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.StatusRouter
+			router.TrafficRouter
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTrafficInst,
+		}
+	}
+	if !supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["status"] && supports["tls"] && supports["traffic"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.TLSRouter
+			router.TrafficRouter
+		}{
+			base,
+			base,
+			apiRouterWithTLSSupportInst,
+			apiRouterWithTrafficInst,
+		}
+	}
+	if supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["status"] && supports["tls"] && supports["traffic"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.TLSRouter
+			router.TrafficRouter
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithTLSSupportInst,
+			apiRouterWithTrafficInst,
+		}
+	}
+	if !supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["status"] && supports["tls"] && supports["traffic"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CustomHealthcheckRouter
+			router.TLSRouter
+			router.TrafficRouter
+		}{
+			base,
+			base,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithTLSSupportInst,
+			apiRouterWithTrafficInst,
+		}
+	}
+	if supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["status"] && supports["tls"] && supports["traffic"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.TLSRouter
+			router.TrafficRouter
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithTLSSupportInst,
+			apiRouterWithTrafficInst,
+		}
+	}
+	if !supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["status"] && supports["tls"] && supports["traffic"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.InfoRouter
+			router.TLSRouter
+			router.TrafficRouter
+		}{
+			base,
+			base,
+			apiRouterWithInfoInst,
+			apiRouterWithTLSSupportInst,
+			apiRouterWithTrafficInst,
+		}
+	}
+	if supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["status"] && supports["tls"] && supports["traffic"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.InfoRouter
+			router.TLSRouter
+			router.TrafficRouter
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithTLSSupportInst,
+			apiRouterWithTrafficInst,
+		}
+	}
+	if !supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["status"] && supports["tls"] && supports["traffic"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.TLSRouter
+			router.TrafficRouter
+		}{
+			base,
+			base,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithTLSSupportInst,
+			apiRouterWithTrafficInst,
+		}
+	}
+	if supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["status"] && supports["tls"] && supports["traffic"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.TLSRouter
+			router.TrafficRouter
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithTLSSupportInst,
+			apiRouterWithTrafficInst,
+		}
+	}
+	if !supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["status"] && supports["tls"] && supports["traffic"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.StatusRouter
+			router.TLSRouter
+			router.TrafficRouter
+		}{
+			base,
+			base,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
+			apiRouterWithTrafficInst,
+		}
+	}
+	if supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["status"] && supports["tls"] && supports["traffic"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.StatusRouter
+			router.TLSRouter
+			router.TrafficRouter
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
+			apiRouterWithTrafficInst,
+		}
+	}
+	if !supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["status"] && supports["tls"] && supports["traffic"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CustomHealthcheckRouter
+			router.StatusRouter
+			router.TLSRouter
+			router.TrafficRouter
+		}{
+			base,
+			base,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
+			apiRouterWithTrafficInst,
+		}
+	}
+	if supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["status"] && supports["tls"] && supports["traffic"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.StatusRouter
+			router.TLSRouter
+			router.TrafficRouter
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
+			apiRouterWithTrafficInst,
+		}
+	}
+	if !supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["status"] && supports["tls"] && supports["traffic"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.InfoRouter
+			router.StatusRouter
+			router.TLSRouter
+			router.TrafficRouter
+		}{
+			base,
+			base,
+			apiRouterWithInfoInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
+			apiRouterWithTrafficInst,
+		}
+	}
+	if supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["status"] && supports["tls"] && supports["traffic"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.InfoRouter
+			router.StatusRouter
+			router.TLSRouter
+			router.TrafficRouter
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
+			apiRouterWithTrafficInst,
+		}
+	}
+	if !supports["cname"] && supports["healthcheck"] && supports["info"] && supports["status"] && supports["tls"] && supports["traffic"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.StatusRouter
+			router.TLSRouter
+			router.TrafficRouter
+		}{
+			base,
+			base,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
+			apiRouterWithTrafficInst,
+		}
+	}
+	if supports["cname"] && supports["healthcheck"] && supports["info"] && supports["status"] && supports["tls"] && supports["traffic"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.StatusRouter
+			router.TLSRouter
+			router.TrafficRouter
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
+			apiRouterWithTrafficInst,
+		}
+	}
 	return nil
 }
@@ -11,6 +11,7 @@ import (
 	"net/url"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/globalsign/mgo"
 	"github.com/globalsign/mgo/bson"
@@ -179,6 +180,12 @@ type StatusRouter interface {
 	GetBackendStatus(name string) (status BackendStatus, detail string, err error)
 }
 
+// TrafficRouter is a router able to tell when a backend last received a
+// request. A zero time is returned when the backend never received requests.
+type TrafficRouter interface {
+	LastRequest(name string) (time.Time, error)
+}
+
 type HealthcheckData struct {
 	Path   string
 	Status int
@@ -9,6 +9,7 @@ import (
 	"net/url"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/tsuru/tsuru/router"
@@ -33,6 +34,11 @@ var StatusRouter = statusRouter{
 	Status:     router.BackendStatusReady,
 }
 
+var TrafficRouter = trafficRouter{
+	fakeRouter:   newFakeRouter(),
+	LastRequests: make(map[string]time.Time),
+}
+
 var TLSRouter = tlsRouter{
 	fakeRouter: newFakeRouter(),
 	Certs:      make(map[string]string),
@@ -48,6 +54,7 @@ func init() {
 	router.Register("fake-opts", createOptsRouter)
 	router.Register("fake-info", createInfoRouter)
 	router.Register("fake-status", createStatusRouter)
+	router.Register("fake-traffic", createTrafficRouter)
 }
 
 func createRouter(name, prefix string) (router.Router, error) {
@@ -74,6 +81,10 @@ func createStatusRouter(name, prefix string) (router.Router, error) {
 	return &StatusRouter, nil
 }
 
+func createTrafficRouter(name, prefix string) (router.Router, error) {
+	return &TrafficRouter, nil
+}
+
 func newFakeRouter() fakeRouter {
 	return fakeRouter{cnames: make(map[string]string), backends: make(map[string][]string), failuresByIp: make(map[string]bool), healthcheck: make(map[string]router.HealthcheckData), mutex: &sync.Mutex{}}
 }
@@ -507,3 +518,24 @@ func (r *statusRouter) Reset() {
 	r.Status = router.BackendStatusReady
 	r.StatusDetail = ""
 }
+
+type trafficRouter struct {
+	fakeRouter
+	LastRequests map[string]time.Time
+}
+
+var _ router.TrafficRouter = &trafficRouter{}
+
+func (r *trafficRouter) LastRequest(name string) (time.Time, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if _, ok := r.backends[name]; !ok {
+		return time.Time{}, router.ErrBackendNotFound
+	}
+	return r.LastRequests[name], nil
+}
+
+func (r *trafficRouter) Reset() {
+	r.fakeRouter.Reset()
+	r.LastRequests = make(map[string]time.Time)
+}